@@ -3,12 +3,14 @@ package core
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 
 	"whatsapp-bot/handlers"
@@ -17,8 +19,8 @@ import (
 
 var (
 	globalClient      *whatsmeow.Client
-	globalTelegramBot *tgbotapi.BotAPI // Used in future updates for per-client event handling
-	globalClientMutex sync.RWMutex     // FIXED: Mutex untuk thread-safe access
+	globalTelegramBot *tgbotapi.BotAPI
+	globalClientMutex sync.RWMutex // FIXED: Mutex untuk thread-safe access
 )
 
 // SetGlobalClients mengatur global client references
@@ -36,35 +38,6 @@ func GetGlobalClient() *whatsmeow.Client {
 	return globalClient
 }
 
-// updateAccountStatusFromClient memperbarui status akun di database berdasarkan client yang triggered event
-func updateAccountStatusFromClient(client *whatsmeow.Client, status string) {
-	if client == nil || client.Store.ID == nil {
-		return
-	}
-
-	// Cari account ID berdasarkan phone number dari client
-	am := handlers.GetAccountManager()
-	accounts := am.GetAllAccounts()
-
-	for _, acc := range accounts {
-		accClient := am.GetClient(acc.ID)
-		if accClient == client {
-			// Found matching client, update status
-			if err := am.UpdateAccountStatus(acc.ID, status); err != nil {
-				utils.GetLogger().Warn("Failed to update account status for ID %d: %v", acc.ID, err)
-			} else {
-				utils.GetLogger().Info("Real-time status update: Account %d (%s) -> %s", acc.ID, acc.PhoneNumber, status)
-			}
-			return
-		}
-	}
-
-	// Fallback: update global client jika tidak ditemukan di AccountManager
-	if GetGlobalClient() == client {
-		utils.GetLogger().Debug("Global client status updated to %s", status)
-	}
-}
-
 // deleteBlockedAccountByID menghapus file database akun yang terblokir berdasarkan ID
 // Menggunakan RemoveAccount untuk menghapus akun, file database (switch sudah dihandle sebelumnya)
 // FIXED: Sekarang dipanggil secara realtime saat 404, disconnect, atau logout
@@ -77,6 +50,9 @@ func deleteBlockedAccountByID(accountID int) {
 		return
 	}
 
+	// Simpan pemilik akun SEBELUM dihapus (setelah RemoveAccount akun sudah tidak ada di AccountManager)
+	ownerTelegramID := handlers.GetAccountTelegramID(account)
+
 	utils.GetLogger().Info("deleteBlockedAccountByID: [REALTIME CLEANUP] Menghapus akun %d (%s) beserta file database-nya", account.ID, account.PhoneNumber)
 
 	// Gunakan RemoveAccount untuk menghapus akun dan file database
@@ -91,10 +67,10 @@ func deleteBlockedAccountByID(accountID int) {
 	} else {
 		utils.GetLogger().Info("deleteBlockedAccountByID: ✅ [REALTIME CLEANUP] Akun %d (%s) berhasil dihapus beserta file database-nya", account.ID, account.PhoneNumber)
 
-		// Kirim notifikasi ke Telegram
+		// Kirim notifikasi ke Telegram pemilik akun
 		notification := fmt.Sprintf("🗑️ **REALTIME CLEANUP - FILE DIHAPUS**\n\nAkun +%s telah terputus/logout/404.\n\n📁 **File yang dihapus:**\n• WhatsApp database (db, -shm, -wal)\n• Bot data database (db, -shm, -wal)\n\n✅ File database telah dihapus secara otomatis dari server.",
 			account.PhoneNumber)
-		handlers.SendToTelegram(notification)
+		handlers.SendToTelegramUser(ownerTelegramID, notification)
 	}
 }
 
@@ -105,68 +81,31 @@ func cleanupAccountDBOnError(client *whatsmeow.Client, errorReason string) {
 		return
 	}
 
-	am := handlers.GetAccountManager()
-	accounts := am.GetAllAccounts()
-	var accountID int = -1
-
-	// Cari account ID berdasarkan client
-	for _, acc := range accounts {
-		accClient := am.GetClient(acc.ID)
-		if accClient == client {
-			accountID = acc.ID
-			utils.GetLogger().Info("cleanupAccountDBOnError: [REALTIME CLEANUP] Account %d (%s) - Error: %s", acc.ID, acc.PhoneNumber, errorReason)
-			break
-		}
-	}
-
+	accountID := handlers.GetAccountManager().GetAccountIDByClient(client)
 	if accountID == -1 {
 		utils.GetLogger().Warn("cleanupAccountDBOnError: Account tidak ditemukan untuk client yang error")
 		return
 	}
 
+	utils.GetLogger().Info("cleanupAccountDBOnError: [REALTIME CLEANUP] Account %d - Error: %s", accountID, errorReason)
+
 	// Hapus database account secara realtime
 	deleteBlockedAccountByID(accountID)
 }
 
 // handleAccountDisconnection menangani disconnection/logout akun dengan auto-switch ke akun lain yang aktif
-func handleAccountDisconnection(disconnectedClient *whatsmeow.Client) {
-	if disconnectedClient == nil || disconnectedClient.Store == nil || disconnectedClient.Store.ID == nil {
-		return
-	}
-
+// Notifikasi dikirim ke Telegram user pemilik akun yang terputus
+func handleAccountDisconnection(disconnectedAccountID int) {
 	am := handlers.GetAccountManager()
 	currentAccount := am.GetCurrentAccount()
-	currentClient := am.GetCurrentClient()
-
-	// Cek apakah akun yang terputus adalah current account
-	isCurrentAccount := false
-	disconnectedAccountID := -1
-
-	if currentAccount != nil && currentClient != nil && currentClient == disconnectedClient {
-		isCurrentAccount = true
-		disconnectedAccountID = currentAccount.ID
-		utils.GetLogger().Info("handleAccountDisconnection: Current account %d (%s) disconnected/logged out", currentAccount.ID, currentAccount.PhoneNumber)
-	} else {
-		// Cari account ID yang sesuai dengan disconnected client
-		accounts := am.GetAllAccounts()
-		for _, acc := range accounts {
-			accClient := am.GetClient(acc.ID)
-			if accClient == disconnectedClient {
-				disconnectedAccountID = acc.ID
-				// Cek apakah ini current account
-				if currentAccount != nil && currentAccount.ID == acc.ID {
-					isCurrentAccount = true
-					utils.GetLogger().Info("handleAccountDisconnection: Current account %d (%s) disconnected/logged out", acc.ID, acc.PhoneNumber)
-				}
-				break
-			}
-		}
-	}
 
 	// Hanya lakukan auto-switch jika yang terputus adalah current account
-	if !isCurrentAccount || disconnectedAccountID == -1 {
+	if currentAccount == nil || currentAccount.ID != disconnectedAccountID {
 		return
 	}
+	utils.GetLogger().Info("handleAccountDisconnection: Current account %d (%s) disconnected/logged out", currentAccount.ID, currentAccount.PhoneNumber)
+
+	ownerTelegramID := handlers.GetAccountTelegramID(currentAccount)
 
 	// Cari akun lain yang masih aktif
 	allAccounts := am.GetAllAccounts()
@@ -202,215 +141,369 @@ func handleAccountDisconnection(disconnectedClient *whatsmeow.Client) {
 
 			// Kirim notifikasi ke Telegram
 			notification := fmt.Sprintf("⚠️ **AKUN TERPUTUS**\n\nAkun +%s telah terputus/logout.\n\n✅ **Auto-switch** ke akun aktif:\n📱 +%s",
-				func() string {
-					if currentAccount != nil {
-						return currentAccount.PhoneNumber
-					}
-					return "Unknown"
-				}(),
+				currentAccount.PhoneNumber,
 				nextActiveAccount.PhoneNumber)
 
-			handlers.SendToTelegram(notification)
+			handlers.SendToTelegramUser(ownerTelegramID, notification)
 			utils.GetLogger().Info("handleAccountDisconnection: ✅ Auto-switch berhasil ke akun %d (%s)", nextActiveAccount.ID, nextActiveAccount.PhoneNumber)
 		}
 	} else {
-		// Tidak ada akun aktif lain, clear current account dan kirim notifikasi
-		// Coba set ke akun yang tidak ada untuk clear current (trick untuk set currentID = -1)
-		// Atau lebih baik, cari akun yang masih ada tapi tidak aktif untuk dijadikan placeholder
-		// Tapi lebih aman, kita hanya tidak set current account dan biarkan currentID tetap, tapi set global client ke nil
-
-		// Set global client ke nil karena tidak ada akun aktif
+		// Tidak ada akun aktif lain, set global client ke nil dan kirim notifikasi
 		SetGlobalClients(nil, globalTelegramBot)
 		handlers.SetClients(nil, globalTelegramBot)
 
 		notification := fmt.Sprintf("⚠️ **SEMUA AKUN TERPUTUS**\n\nAkun +%s telah terputus/logout.\n\n❌ Tidak ada akun aktif lainnya.\n\n🔗 Gunakan fitur 'Login Baru' untuk menambahkan akun baru.",
-			func() string {
-				if currentAccount != nil {
-					return currentAccount.PhoneNumber
-				}
-				return "Unknown"
-			}())
+			currentAccount.PhoneNumber)
 
-		handlers.SendToTelegram(notification)
+		handlers.SendToTelegramUser(ownerTelegramID, notification)
 		utils.GetLogger().Warn("handleAccountDisconnection: Tidak ada akun aktif lain, global client di-set ke nil")
 	}
 }
 
-// EventHandler adalah event handler untuk WhatsApp events
+// NewAccountEventHandler membuat event handler yang terikat ke satu akun
+// Didaftarkan otomatis oleh AccountManager setiap kali client akun dibuat, sehingga setiap event
+// selalu membawa account ID sumbernya (penyimpanan grup, status, dan alert diarahkan ke akun tersebut)
+func NewAccountEventHandler(accountID int) func(evt interface{}) {
+	return func(evt interface{}) {
+		handleEvent(accountID, evt)
+	}
+}
+
+// EventHandler adalah event handler untuk client single-account (whatsapp.db default, sebelum multi-account)
+// Client multi-account menggunakan NewAccountEventHandler
 func EventHandler(evt interface{}) {
-	switch v := evt.(type) {
-	case *events.Message:
-		// Simpan grup ke database jika pesan dari grup (untuk fitur list grup)
-		// CRITICAL: Real-time refresh grup saat ada pesan baru (bukan 5 menit sekali)
-		if v.Info.IsGroup && !v.Info.IsFromMe {
-			groupJID := v.Info.Chat.String()
-			groupName := ""
+	handleEvent(noAccountID, evt)
+}
 
-			client := GetGlobalClient() // FIXED: Use thread-safe getter
-			if client != nil {
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-				defer cancel()
+// noAccountID menandai event dari client yang belum terdaftar di AccountManager
+const noAccountID = -1
 
-				groupInfo, err := client.GetGroupInfo(ctx, v.Info.Chat)
-				if err == nil && groupInfo != nil {
-					groupName = groupInfo.Name
-				}
-				// FIXED: Removed double cancel() - defer already handles cancellation
-			}
+// eventClient mendapatkan client sumber event
+func eventClient(accountID int) *whatsmeow.Client {
+	if accountID == noAccountID {
+		return GetGlobalClient()
+	}
+	return handlers.GetAccountManager().GetClient(accountID)
+}
 
-			// Save group to database secara real-time
-			go utils.SaveGroupToDB(groupJID, groupName)
-
-			// REAL-TIME REFRESH: Ambil semua grup dari WhatsApp dan update database
-			// Ini memastikan database selalu up-to-date, bukan hanya setiap 5 menit
-			go func() {
-				// FIXED: Add timeout untuk goroutine untuk mencegah leak
-				ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-				defer cancel()
-
-				// FIXED: Get client fresh dan validate dengan timeout
-				client := handlers.GetWhatsAppClient()
-				if client == nil || client.Store == nil || client.Store.ID == nil {
-					utils.GetLogger().Debug("Real-time group refresh: Client invalid, skipping")
-					return
-				}
+// saveEventGroups menyimpan grup ke database milik akun sumber event
+func saveEventGroups(accountID int, groups map[string]string) error {
+	if accountID == noAccountID {
+		return utils.BatchSaveGroupsToDB(groups)
+	}
 
-				// FIXED: Check connection dengan timeout
-				select {
-				case <-ctx.Done():
-					utils.GetLogger().Debug("Real-time group refresh: Context timeout before connection check")
-					return
-				default:
-					if !client.IsConnected() {
-						utils.GetLogger().Debug("Real-time group refresh: Client not connected, skipping")
-						return
-					}
-				}
+	account := handlers.GetAccountManager().GetAccount(accountID)
+	if account == nil || account.BotDataDBPath == "" {
+		return fmt.Errorf("akun %d tidak ditemukan atau belum punya database", accountID)
+	}
+	return utils.BatchSaveGroupsToAccountDB(account.BotDataDBPath, groups)
+}
 
-				// Fetch semua grup dari WhatsApp API (real-time update)
-				fetchCtx, fetchCancel := context.WithTimeout(ctx, 30*time.Second)
-				defer fetchCancel()
+// notifyEventOwner mengirim notifikasi event ke Telegram user pemilik akun sumber event
+func notifyEventOwner(accountID int, message string) {
+	if accountID == noAccountID {
+		handlers.SendToTelegram(message)
+		return
+	}
+	handlers.SendToAccountOwner(accountID, message)
+}
 
-				joinedGroups, err := client.GetJoinedGroups(fetchCtx)
-				if err != nil {
-					utils.GetLogger().Debug("Real-time group refresh: Gagal fetch groups: %v", err)
-					return
-				}
+// handleGroupMessage menyimpan grup dari pesan masuk dan melakukan real-time refresh daftar grup akun
+func handleGroupMessage(accountID int, v *events.Message) {
+	groupJID := v.Info.Chat.String()
+	groupName := ""
+
+	client := eventClient(accountID)
+	if client != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		groupInfo, err := client.GetGroupInfo(ctx, v.Info.Chat)
+		cancel()
+		if err == nil && groupInfo != nil {
+			groupName = groupInfo.Name
+		}
+	}
 
-				if len(joinedGroups) == 0 {
-					return
-				}
+	// Save group to database secara real-time (database milik akun sumber event)
+	go func() {
+		if err := saveEventGroups(accountID, map[string]string{groupJID: groupName}); err != nil {
+			utils.GetLogger().Debug("Real-time group save: account %d gagal simpan grup %s: %v", accountID, groupJID, err)
+		}
+	}()
+
+	// REAL-TIME REFRESH: Ambil semua grup dari WhatsApp dan update database
+	// Ini memastikan database selalu up-to-date, bukan hanya setiap 5 menit
+	go func() {
+		// FIXED: Add timeout untuk goroutine untuk mencegah leak
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		// Gunakan client milik akun sumber event (bukan client yang sedang aktif)
+		client := eventClient(accountID)
+		if client == nil || client.Store == nil || client.Store.ID == nil {
+			utils.GetLogger().Debug("Real-time group refresh: Client account %d invalid, skipping", accountID)
+			return
+		}
 
-				// Convert dan simpan ke database (real-time update)
-				groupsToSave := make(map[string]string)
-				for _, group := range joinedGroups {
-					if group != nil {
-						jidStr := group.JID.String()
-						if strings.HasSuffix(jidStr, "@g.us") {
-							groupName := group.Name
-							if groupName == "" {
-								groupName = fmt.Sprintf("Grup %s", group.JID.User)
-							}
-							groupsToSave[jidStr] = groupName
-						}
-					}
-				}
+		if !client.IsConnected() {
+			utils.GetLogger().Debug("Real-time group refresh: Client account %d not connected, skipping", accountID)
+			return
+		}
 
-				if len(groupsToSave) > 0 {
-					if err := utils.BatchSaveGroupsToDB(groupsToSave); err != nil {
-						utils.GetLogger().Debug("Real-time group refresh: Gagal batch save: %v", err)
-					} else {
-						utils.GetLogger().Debug("Real-time group refresh: ✅ Updated %d groups in database", len(groupsToSave))
-					}
-				}
-			}()
+		// Fetch semua grup dari WhatsApp API (real-time update)
+		fetchCtx, fetchCancel := context.WithTimeout(ctx, 30*time.Second)
+		defer fetchCancel()
+
+		joinedGroups, err := client.GetJoinedGroups(fetchCtx)
+		if err != nil {
+			utils.GetLogger().Debug("Real-time group refresh: Gagal fetch groups: %v", err)
+			return
 		}
-	case *events.Connected:
-		// Client connected - update status di database secara real-time
-		utils.GetLogger().Debug("WhatsApp client connected")
 
-		// Coba update status dari globalClient jika ada
-		client := GetGlobalClient() // FIXED: Use thread-safe getter
-		if client != nil {
-			go updateAccountStatusFromClient(client, "active")
+		if len(joinedGroups) == 0 {
+			return
 		}
-	case *events.Disconnected:
-		handlers.SendToTelegram("❌ Disconnected from WhatsApp!")
-		utils.GetLogger().Warn("WhatsApp client disconnected")
-
-		// FIXED: REALTIME CLEANUP - Update status ke inactive, handle auto-switch, dan hapus database
-		client := GetGlobalClient() // FIXED: Use thread-safe getter
-		if client != nil {
-			go func(disconnectedClient *whatsmeow.Client) {
-				updateAccountStatusFromClient(disconnectedClient, "inactive")
-
-				// Simpan account ID sebelum switch (karena setelah switch, globalClient akan berubah)
-				am := handlers.GetAccountManager()
-				accounts := am.GetAllAccounts()
-				var disconnectedAccountID int = -1
-				for _, acc := range accounts {
-					accClient := am.GetClient(acc.ID)
-					if accClient == disconnectedClient {
-						disconnectedAccountID = acc.ID
-						break
+
+		// Convert dan simpan ke database (real-time update)
+		groupsToSave := make(map[string]string)
+		for _, group := range joinedGroups {
+			if group != nil {
+				jidStr := group.JID.String()
+				if strings.HasSuffix(jidStr, "@g.us") {
+					groupName := group.Name
+					if groupName == "" {
+						groupName = fmt.Sprintf("Grup %s", group.JID.User)
 					}
+					groupsToSave[jidStr] = groupName
 				}
+			}
+		}
+
+		if len(groupsToSave) > 0 {
+			if err := saveEventGroups(accountID, groupsToSave); err != nil {
+				utils.GetLogger().Debug("Real-time group refresh: Gagal batch save: %v", err)
+			} else {
+				utils.GetLogger().Debug("Real-time group refresh: ✅ Updated %d groups in database account %d", len(groupsToSave), accountID)
+			}
+		}
+	}()
+}
 
-				// Handle disconnection dulu (auto-switch jika perlu)
-				handleAccountDisconnection(disconnectedClient)
+// handleAccountLost menangani akun yang logout: auto-switch lalu cleanup database
+// Status akun sudah dicatat supervisor (logged_out) sebelum fungsi ini dipanggil
+func handleAccountLost(accountID int, reason string) {
+	// Handle disconnection dulu (auto-switch jika perlu)
+	handleAccountDisconnection(accountID)
 
-				// REALTIME CLEANUP: Hapus database setelah disconnect
-				// Delay kecil untuk memastikan switch selesai
-				time.Sleep(1 * time.Second)
+	// REALTIME CLEANUP: Hapus database setelah logout
+	// Delay kecil untuk memastikan switch selesai
+	time.Sleep(1 * time.Second)
 
-				// Hapus account berdasarkan ID (bukan client, karena client sudah berubah)
-				if disconnectedAccountID != -1 {
-					utils.GetLogger().Info("events.Disconnected: [REALTIME CLEANUP] Menghapus database untuk account %d karena disconnect", disconnectedAccountID)
-					deleteBlockedAccountByID(disconnectedAccountID)
-				}
-			}(client) // Pass as parameter to avoid race condition
+	utils.GetLogger().Info("events.%s: [REALTIME CLEANUP] Menghapus database untuk account %d", reason, accountID)
+	deleteBlockedAccountByID(accountID)
+}
+
+// handleEvent memproses WhatsApp event dengan account ID sumbernya
+func handleEvent(accountID int, evt interface{}) {
+	// Gerakkan state machine supervisor akun (connected, backing-off, logged-out, dll)
+	if accountID != noAccountID {
+		handlers.HandleAccountLifecycleEvent(accountID, evt)
+	}
+
+	switch v := evt.(type) {
+	case *events.Message:
+		// Simpan grup ke database jika pesan dari grup (untuk fitur list grup)
+		// CRITICAL: Real-time refresh grup saat ada pesan baru (bukan 5 menit sekali)
+		if v.Info.IsGroup && !v.Info.IsFromMe {
+			// Moderasi otomatis berjalan terpisah agar tidak menunggu refresh grup
+			go handleModeration(accountID, v)
+			go handleKeywordWatch(accountID, v)
+			handleGroupMessage(accountID, v)
+		}
+	case *events.Connected:
+		// Status akun dicatat oleh supervisor (HandleAccountLifecycleEvent)
+		utils.GetLogger().Debug("WhatsApp client connected (account %d)", accountID)
+	case *events.Disconnected:
+		utils.GetLogger().Warn("WhatsApp client disconnected (account %d)", accountID)
+		if accountID == noAccountID {
+			handlers.SendToTelegram("❌ Disconnected from WhatsApp!")
+			return
+		}
+
+		// Koneksi putus sementara: supervisor akan reconnect dengan backoff, database TIDAK dihapus
+		notifyEventOwner(accountID, "❌ Disconnected from WhatsApp!\n\n🔁 Reconnect otomatis sedang dijadwalkan.")
+		go handleAccountDisconnection(accountID)
+	case *events.StreamReplaced:
+		utils.GetLogger().Warn("WhatsApp stream replaced (account %d)", accountID)
+		notifyEventOwner(accountID, "♻️ Sesi WhatsApp diambil alih oleh client lain dengan kunci yang sama.\n\nReconnect otomatis dihentikan. Gunakan menu 📡 Status Koneksi untuk reconnect manual.")
+		if accountID != noAccountID {
+			go handleAccountDisconnection(accountID)
+		}
+	case *events.TemporaryBan:
+		utils.GetLogger().Warn("WhatsApp temporary ban (account %d): %s", accountID, v.String())
+		notifyEventOwner(accountID, fmt.Sprintf("⛔ Akun terkena banned sementara.\n\n%s\n\n🔁 Reconnect otomatis setelah masa banned berakhir.", v.String()))
+		if accountID != noAccountID {
+			go handleAccountDisconnection(accountID)
 		}
 	case *events.LoggedOut:
-		handlers.SendToTelegram("🚪 Logged out!")
-		utils.GetLogger().Warn("WhatsApp client logged out")
+		notifyEventOwner(accountID, "🚪 Logged out!")
+		utils.GetLogger().Warn("WhatsApp client logged out (account %d)", accountID)
 
 		// FIXED: REALTIME CLEANUP - Update status ke inactive saat logout, handle auto-switch, dan hapus file database
-		// Urutan: 1. Update status, 2. Simpan account ID, 3. Handle disconnection (switch), 4. Hapus file database
-		client := GetGlobalClient() // FIXED: Use thread-safe getter
-		if client != nil {
-			go func(blockedClient *whatsmeow.Client) {
-				updateAccountStatusFromClient(blockedClient, "inactive")
-
-				// Simpan account ID sebelum switch (karena setelah switch, globalClient akan berubah)
-				am := handlers.GetAccountManager()
-				accounts := am.GetAllAccounts()
-				var blockedAccountID int = -1
-				for _, acc := range accounts {
-					accClient := am.GetClient(acc.ID)
-					if accClient == blockedClient {
-						blockedAccountID = acc.ID
-						break
-					}
-				}
+		if accountID != noAccountID {
+			go handleAccountLost(accountID, "LoggedOut")
+		}
+	case *events.JoinedGroup:
+		// Akun masuk grup: jika sebelumnya menunggu persetujuan admin berarti sudah disetujui
+		go handleJoinRequestUpdate(accountID, v.JID, utils.JoinRequestApproved)
+	case *events.GroupInfo:
+		if status := handlers.JoinRequestStatusFromGroupInfo(eventClient(accountID), v); status != "" {
+			go handleJoinRequestUpdate(accountID, v.JID, status)
+		}
+		if handlers.HasNewMembershipRequests(v) {
+			go handleMembershipRequests(accountID, v.JID)
+		}
+		if len(v.Join) > 0 {
+			go handleGroupJoins(accountID, v)
+		}
+	case *events.PairSuccess:
+		// Pair success handled in PairDeviceViaTelegram, skip here to avoid duplication
+		return
+	}
+}
 
-				// Handle disconnection dulu (auto-switch jika perlu)
-				handleAccountDisconnection(blockedClient)
+// eventBotDBPath mendapatkan path database bot milik akun sumber event ("" = database akun aktif)
+func eventBotDBPath(accountID int) (string, bool) {
+	if accountID == noAccountID {
+		return "", true
+	}
+	account := handlers.GetAccountManager().GetAccount(accountID)
+	if account == nil || account.BotDataDBPath == "" {
+		return "", false
+	}
+	return account.BotDataDBPath, true
+}
 
-				// REALTIME CLEANUP: Hapus database setelah logout
-				// Delay kecil untuk memastikan switch selesai
-				time.Sleep(1 * time.Second)
+// handleMembershipRequests menjalankan auto-approve permintaan masuk baru sesuai aturan akun sumber event
+func handleMembershipRequests(accountID int, groupJID types.JID) {
+	dbPath, ok := eventBotDBPath(accountID)
+	if !ok {
+		return
+	}
 
-				// Hapus account berdasarkan ID (bukan client, karena client sudah berubah)
-				if blockedAccountID != -1 {
-					utils.GetLogger().Info("events.LoggedOut: [REALTIME CLEANUP] Menghapus database untuk account %d karena logout", blockedAccountID)
-					deleteBlockedAccountByID(blockedAccountID)
-				}
-			}(client) // Pass client sebagai parameter untuk closure
+	approved, err := handlers.AutoApproveMembershipRequests(eventClient(accountID), dbPath, groupJID)
+	if err != nil {
+		utils.GetLogger().Debug("Auto-approve: account %d gagal proses permintaan %s: %v", accountID, groupJID, err)
+		return
+	}
+	if approved > 0 {
+		notifyEventOwner(accountID, fmt.Sprintf("🤖 Auto-approve: %d permintaan masuk disetujui\n\n👥 %s", approved, groupJID.String()))
+	}
+}
+
+// handleModeration memeriksa pesan grup terhadap aturan moderasi akun sumber event lalu melaporkan tindakannya
+func handleModeration(accountID int, v *events.Message) {
+	dbPath, ok := eventBotDBPath(accountID)
+	if !ok {
+		return
+	}
+
+	client := eventClient(accountID)
+	action, err := handlers.ModerateGroupMessage(client, dbPath, v)
+	if err != nil {
+		utils.GetLogger().Debug("Moderasi: account %d gagal memproses pesan %s: %v", accountID, v.Info.ID, err)
+	}
+	if action != nil {
+		handlers.ReportModerationAction(accountID, dbPath, client, action)
+	}
+}
+
+// handleKeywordWatch mencocokkan pesan grup dengan aturan pantau kata kunci akun sumber event lalu mengirim alert
+func handleKeywordWatch(accountID int, v *events.Message) {
+	dbPath, ok := eventBotDBPath(accountID)
+	if !ok {
+		return
+	}
+
+	alerts, err := handlers.MatchKeywordWatches(eventClient(accountID), dbPath, v)
+	if err != nil {
+		utils.GetLogger().Debug("Pantau kata kunci: account %d gagal memproses pesan %s: %v", accountID, v.Info.ID, err)
+	}
+	for _, alert := range alerts {
+		handlers.SendKeywordWatchAlert(accountID, alert)
+	}
+}
+
+// handleGroupJoins menangani anggota baru grup milik akun sumber event:
+// nomor blocklist dikeluarkan lebih dulu, anggota lainnya dijadwalkan pesan sambutan
+func handleGroupJoins(accountID int, v *events.GroupInfo) {
+	dbPath, ok := eventBotDBPath(accountID)
+	if !ok {
+		return
+	}
+	client := eventClient(accountID)
+
+	removed, labels, groupName, err := handlers.EnforceBlocklistOnJoin(client, dbPath, v)
+	if err != nil {
+		utils.GetLogger().Debug("Blocklist: account %d gagal memproses anggota baru %s: %v", accountID, v.JID, err)
+	}
+	if len(removed) > 0 {
+		if groupName == "" {
+			groupName = v.JID.String()
 		}
-	case *events.PairSuccess:
-		// Pair success handled in PairDeviceViaTelegram, skip here to avoid duplication
+		description := fmt.Sprintf("Blocklist: %s dikeluarkan dari %s", strings.Join(labels, ", "), groupName)
+		utils.LogActivityWithMetadataAt(dbPath, "blocklist_remove_join", description, 0, map[string]interface{}{
+			"group_jid": v.JID.String(),
+			"phones":    labels,
+		}, true)
+		notifyEventOwner(accountID, fmt.Sprintf("⛔ Blocklist: %d nomor dikeluarkan otomatis\n\n👥 %s\n🚫 %s", len(removed), groupName, strings.Join(labels, ", ")))
+
+		// Anggota yang sudah dikeluarkan tidak perlu disambut
+		remaining := make([]types.JID, 0, len(v.Join))
+		for _, jid := range v.Join {
+			if !slices.Contains(removed, jid) {
+				remaining = append(remaining, jid)
+			}
+		}
+		filtered := *v
+		filtered.Join = remaining
+		v = &filtered
+	}
+
+	handlers.QueueWelcomeForJoins(client, dbPath, v)
+}
+
+// handleJoinRequestUpdate memperbarui permintaan join pending milik akun sumber event lalu memberi tahu pemiliknya
+func handleJoinRequestUpdate(accountID int, groupJID types.JID, status string) {
+	client := eventClient(accountID)
+	if client == nil || client.Store.ID == nil {
+		return
+	}
+
+	dbPath, ok := eventBotDBPath(accountID)
+	if !ok {
 		return
 	}
+
+	ownerID := client.Store.ID.User
+	updated, err := utils.ResolveJoinRequestAt(dbPath, ownerID, groupJID.String(), status)
+	if err != nil {
+		utils.GetLogger().Debug("Join request update: account %d gagal update %s: %v", accountID, groupJID, err)
+		return
+	}
+	if !updated {
+		return // Bukan permintaan join yang sedang dipantau
+	}
+
+	name := groupJID.String()
+	if record, err := utils.GetJoinRequestAt(dbPath, ownerID, groupJID.String()); err == nil && record != nil && record.GroupName != "" {
+		name = record.GroupName
+	}
+
+	if status == utils.JoinRequestApproved {
+		notifyEventOwner(accountID, fmt.Sprintf("✅ Permintaan join disetujui admin\n\n👥 %s", name))
+	} else {
+		notifyEventOwner(accountID, fmt.Sprintf("❌ Permintaan join ditolak admin\n\n👥 %s", name))
+	}
 }
 
 // HandleWhatsAppError menangani error dari operasi WhatsApp dan melakukan cleanup jika perlu
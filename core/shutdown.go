@@ -11,6 +11,9 @@ import (
 	"go.mau.fi/whatsmeow"
 )
 
+// GracefulShutdownTimeout adalah batas waktu menunggu job berjalan selesai/checkpoint saat shutdown
+const GracefulShutdownTimeout = 30 * time.Second
+
 // ShutdownManager mengelola proses shutdown aplikasi
 type ShutdownManager struct {
 	waClient    *whatsmeow.Client
@@ -28,25 +31,59 @@ func NewShutdownManager(waClient *whatsmeow.Client, telegramBot *tgbotapi.BotAPI
 }
 
 // Shutdown melakukan graceful shutdown aplikasi
+// Urutan: tolak pekerjaan baru -> hentikan reconnect -> hentikan broadcast -> tunggu/checkpoint job
+// -> putuskan semua akun WhatsApp -> tutup semua koneksi database
 func (sm *ShutdownManager) Shutdown(ctx context.Context) error {
 	sm.logger.Phase("Initiating graceful shutdown...")
 
-	// Notify user
-	handlers.SendToTelegram("👋 Menutup bot...")
+	// 1. Tandai shutdown: update baru ditolak dan job massal berhenti di item berikutnya
+	handlers.BeginShutdown()
 
-	// Stop Telegram bot updates
+	// 2. Stop Telegram bot updates
 	if sm.telegramBot != nil {
 		sm.logger.Info("Stopping Telegram bot updates...")
 		sm.telegramBot.StopReceivingUpdates()
 		sm.logger.Success("Telegram bot stopped")
 	}
 
-	// Disconnect WhatsApp client
-	if sm.waClient != nil {
-		sm.logger.Info("Disconnecting WhatsApp client...")
+	// 3. Hentikan supervisor agar tidak ada reconnect selama shutdown
+	sm.logger.Info("Stopping account supervisors...")
+	handlers.StopAllAccountSupervisors()
+
+	// 4. Hentikan broadcast yang sedang berjalan dan beri tahu user-nya
+	stoppedBroadcasts := handlers.StopAllBroadcasts()
+	for _, chatID := range stoppedBroadcasts {
+		handlers.SendToTelegramUser(chatID, "⏹️ **BROADCAST DIHENTIKAN**\n\nBot sedang dimatikan (maintenance/restart). Silakan jalankan ulang broadcast setelah bot aktif kembali.")
+	}
+	if len(stoppedBroadcasts) > 0 {
+		sm.logger.Info("Stopped %d running broadcast(s)", len(stoppedBroadcasts))
+	}
+
+	// 5. Tunggu job massal berhenti di batas item (sisa item di-checkpoint dan dikirim ke user)
+	sm.logger.Info("Waiting for running group jobs...")
+	if forced := handlers.WaitForGroupJobs(ctx); forced > 0 {
+		sm.logger.Warn("%d group job(s) di-checkpoint paksa karena timeout shutdown", forced)
+	} else {
+		sm.logger.Success("All group jobs stopped")
+	}
+
+	// 6. Disconnect semua client WhatsApp (multi-account + client utama)
+	sm.logger.Info("Disconnecting WhatsApp clients...")
+	disconnected := handlers.GetAccountManager().DisconnectAll()
+	if sm.waClient != nil && sm.waClient.IsConnected() {
 		sm.waClient.Disconnect()
-		sm.logger.Success("WhatsApp client disconnected")
+		disconnected++
 	}
+	sm.logger.Success("WhatsApp clients disconnected (%d)", disconnected)
+
+	// 7. Tutup semua pool database (global + per akun)
+	sm.logger.Info("Closing database pools...")
+	utils.CloseDBPools()
+	utils.CloseAllAccountDBPools()
+	sm.logger.Success("Database pools closed")
+
+	// 8. Lepas lock instance & database sesi agar instance berikutnya bisa langsung start
+	utils.ReleaseAllLocks()
 
 	sm.logger.Success("Shutdown completed")
 	return nil
@@ -193,6 +193,11 @@ func (sm *StartupManager) initializeWhatsApp() error {
 		sm.config.WhatsAppDBPath = dbPath // Update config juga
 	}
 
+	// Kunci database sesi agar tidak dibuka proses lain
+	if err := utils.AcquireDBLock(dbPath); err != nil {
+		return fmt.Errorf("failed to lock WhatsApp database: %w", err)
+	}
+
 	// FIXED: Tambahkan _busy_timeout dan _locking_mode untuk mencegah "database table is locked" saat concurrent access
 	// Gunakan DELETE mode untuk menghilangkan -shm dan -wal files
 	dbConnectionString := fmt.Sprintf("file:%s?_foreign_keys=on&mode=rwc&_journal_mode=DELETE&cache=shared&_busy_timeout=10000&_sync=1&_locking_mode=EXCLUSIVE",
@@ -391,6 +396,15 @@ func (sm *StartupManager) finalizeSetup() error {
 
 	// Set current client jika ada akun yang loaded (kembali ke dalam blok if accountCount > 0 jika perlu)
 	if accountCount > 0 {
+		// Connect SEMUA akun secara paralel (bounded) dengan timeout per akun, lalu kirim laporan per user
+		// Hanya jika waClient belum ada (dari initializeWhatsApp)
+		var startupResults []*AccountStartupResult
+		if sm.waClient == nil {
+			startupBegin := time.Now()
+			startupResults = sm.connectAllAccounts(am.GetAllAccounts())
+			sm.sendStartupReports(startupResults, time.Since(startupBegin), sm.getStartupTargetUserID())
+		}
+
 		currentAccount := am.GetCurrentAccount()
 		if currentAccount != nil {
 			sm.logger.Info("Multi-account: Using account ID %d (%s) as current", currentAccount.ID, currentAccount.PhoneNumber)
@@ -435,22 +449,18 @@ func (sm *StartupManager) finalizeSetup() error {
 				sm.logger.Info("Multi-account: dbConfig verified - DBPath: %s", expectedDBPath)
 			}
 
-			// Hanya create client jika waClient belum ada (dari initializeWhatsApp)
+			// Gunakan hasil startup paralel untuk current account
 			if sm.waClient == nil {
-				client, err := am.CreateClient(currentAccount.ID)
-				if err == nil {
-					handlers.SetClients(client, sm.telegramBot)
-					sm.waClient = client
-					sm.logger.Success("Multi-account: Successfully loaded and connected account %s", currentAccount.PhoneNumber)
-
-					// Update status setelah berhasil connect
-					if err := am.UpdateAccountStatus(currentAccount.ID, "active"); err != nil {
-						sm.logger.Warn("Multi-account: Failed to update status for account %d: %v", currentAccount.ID, err)
+				for _, result := range startupResults {
+					if result != nil && result.Err == nil && result.Account.ID == currentAccount.ID {
+						handlers.SetClients(result.Client, sm.telegramBot)
+						sm.waClient = result.Client
+						sm.logger.Success("Multi-account: Successfully loaded and connected account %s", currentAccount.PhoneNumber)
+						break
 					}
-				} else {
-					sm.logger.Warn("Multi-account: Failed to create client for account %d: %v", currentAccount.ID, err)
-					// Update status ke inactive jika gagal connect
-					_ = am.UpdateAccountStatus(currentAccount.ID, "inactive")
+				}
+				if sm.waClient == nil {
+					sm.logger.Warn("Multi-account: Failed to connect current account %d (%s)", currentAccount.ID, currentAccount.PhoneNumber)
 				}
 			} else {
 				// Client sudah ada dari initializeWhatsApp, pastikan event handler terdaftar
@@ -494,64 +504,23 @@ func (sm *StartupManager) finalizeSetup() error {
 
 	// Show appropriate UI based on login status
 	// Send to first admin/user
-	var targetUserID int64
-	if len(sm.config.TelegramConfig.AdminIDs) > 0 {
-		targetUserID = sm.config.TelegramConfig.AdminIDs[0]
-	} else if len(sm.config.TelegramConfig.AllowedUserIDs) > 0 {
-		targetUserID = sm.config.TelegramConfig.AllowedUserIDs[0]
-	} else {
-		targetUserID = sm.config.TelegramConfig.UserAllowedID // Backward compatibility
-	}
+	targetUserID := sm.getStartupTargetUserID()
 
 	// IMPORTANT: Check multi-account client first, fallback to single account client
 	currentClient := am.GetCurrentClient()
 	currentAccount := am.GetCurrentAccount()
 
-	// CRITICAL FIX: Coba auto-connect ke SEMUA account (tidak peduli status)
+	// CRITICAL FIX: Jika current account gagal connect, gunakan akun lain yang sudah terhubung saat startup paralel
 	// Ini mengatasi masalah: account ada di DB tapi status "inactive", program kembali ke pairing menu
-	if currentClient == nil {
-		allAccounts := am.GetAllAccounts()
-		sm.logger.Info("finalizeSetup: Client belum ada, mencoba auto-connect ke %d account yang ditemukan...", len(allAccounts))
-
-		// Coba connect ke semua account secara berurutan
-		// Mulai dari currentAccount jika ada, lalu coba yang lain
-		accountsToTry := []*handlers.WhatsAppAccount{}
-
-		// Prioritaskan currentAccount jika ada
-		if currentAccount != nil {
-			accountsToTry = append(accountsToTry, currentAccount)
-		}
-
-		// Tambahkan account lain yang belum ada di list
-		for _, acc := range allAccounts {
-			if currentAccount == nil || acc.ID != currentAccount.ID {
-				accountsToTry = append(accountsToTry, acc)
-			}
-		}
-
-		// Coba connect ke setiap account
-		for _, acc := range accountsToTry {
-			sm.logger.Info("finalizeSetup: Mencoba auto-connect ke account %d (+%s)...", acc.ID, acc.PhoneNumber)
-
-			testClient, err := am.CreateClient(acc.ID)
-			if err != nil {
-				sm.logger.Warn("finalizeSetup: Gagal auto-connect ke account %d: %v", acc.ID, err)
-				// Update status ke inactive jika gagal
-				_ = am.UpdateAccountStatus(acc.ID, "inactive")
-				continue
-			}
-
-			// Cek apakah client valid
-			if testClient != nil && testClient.Store != nil && testClient.Store.ID != nil && testClient.IsConnected() {
+	if currentClient == nil || !currentClient.IsConnected() {
+		currentClient = nil
+		for _, acc := range am.GetAllAccounts() {
+			testClient := am.GetClient(acc.ID)
+			if testClient != nil && testClient.IsConnected() {
 				currentClient = testClient
 				am.SetCurrentAccount(acc.ID)
-				// Update status ke active setelah berhasil connect
-				_ = am.UpdateAccountStatus(acc.ID, "active")
-				sm.logger.Info("finalizeSetup: ✅ Berhasil auto-connect ke account %d (+%s)", acc.ID, acc.PhoneNumber)
+				sm.logger.Info("finalizeSetup: ✅ Menggunakan account %d (+%s) yang sudah terhubung", acc.ID, acc.PhoneNumber)
 				break
-			} else {
-				sm.logger.Warn("finalizeSetup: Client untuk account %d tidak valid setelah connect", acc.ID)
-				_ = am.UpdateAccountStatus(acc.ID, "inactive")
 			}
 		}
 
@@ -608,12 +577,24 @@ func (sm *StartupManager) finalizeSetup() error {
 	// Hanya berjalan jika client sudah login
 	if sm.waClient != nil && sm.waClient.Store.ID != nil {
 		handlers.StartPeriodicGroupRefresh(5 * time.Minute)
+		// Validasi link undangan di registry secara berkala untuk mendeteksi link yang sudah dicabut
+		handlers.StartPeriodicInviteLinkValidation(6 * time.Hour)
 	}
 
 	sm.logger.Success("Setup finalized")
 	return nil
 }
 
+// getStartupTargetUserID mendapatkan user Telegram utama (admin pertama, allowed user pertama, atau UserAllowedID)
+func (sm *StartupManager) getStartupTargetUserID() int64 {
+	if len(sm.config.TelegramConfig.AdminIDs) > 0 {
+		return sm.config.TelegramConfig.AdminIDs[0]
+	} else if len(sm.config.TelegramConfig.AllowedUserIDs) > 0 {
+		return sm.config.TelegramConfig.AllowedUserIDs[0]
+	}
+	return sm.config.TelegramConfig.UserAllowedID // Backward compatibility
+}
+
 // GetTelegramBot mendapatkan Telegram bot instance
 func (sm *StartupManager) GetTelegramBot() *tgbotapi.BotAPI {
 	return sm.telegramBot
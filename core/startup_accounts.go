@@ -0,0 +1,183 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+
+	"whatsapp-bot/handlers"
+	"whatsapp-bot/utils"
+)
+
+const (
+	// StartupConnectConcurrency adalah jumlah maksimal akun yang di-connect bersamaan saat startup
+	StartupConnectConcurrency = 5
+	// StartupConnectTimeout adalah batas waktu connect untuk SETIAP akun saat startup
+	StartupConnectTimeout = 45 * time.Second
+)
+
+// AccountStartupResult menyimpan hasil koneksi satu akun saat startup
+type AccountStartupResult struct {
+	Account  *handlers.WhatsAppAccount
+	Client   *whatsmeow.Client
+	Err      error
+	Duration time.Duration
+}
+
+// startupConnectResult adalah hasil CreateClient satu akun saat startup
+type startupConnectResult struct {
+	client *whatsmeow.Client
+	err    error
+}
+
+// connectAllAccounts menghubungkan semua akun secara paralel dengan batas concurrency
+// Setiap akun punya timeout sendiri sehingga satu akun yang lambat tidak menahan akun lain
+func (sm *StartupManager) connectAllAccounts(accounts []*handlers.WhatsAppAccount) []*AccountStartupResult {
+	am := handlers.GetAccountManager()
+	results := make([]*AccountStartupResult, len(accounts))
+
+	semaphore := make(chan struct{}, StartupConnectConcurrency)
+	var wg sync.WaitGroup
+
+	sm.logger.Phase(fmt.Sprintf("Connecting %d accounts (max %d concurrent, timeout %v per account)...", len(accounts), StartupConnectConcurrency, StartupConnectTimeout))
+
+	for i, acc := range accounts {
+		wg.Add(1)
+		go func(index int, account *handlers.WhatsAppAccount) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			startTime := time.Now()
+			result := &AccountStartupResult{Account: account}
+
+			done := make(chan startupConnectResult, 1)
+			go func() {
+				client, err := am.CreateClient(account.ID)
+				done <- startupConnectResult{client: client, err: err}
+			}()
+
+			// Catatan: jika timeout, CreateClient tetap selesai di background (dibatasi timeout internalnya)
+			timedOut := false
+			select {
+			case res := <-done:
+				result.Client = res.client
+				result.Err = res.err
+			case <-time.After(StartupConnectTimeout):
+				result.Err = fmt.Errorf("timeout setelah %v", StartupConnectTimeout)
+				timedOut = true
+			}
+
+			if result.Err == nil && (result.Client == nil || !result.Client.IsConnected()) {
+				result.Err = fmt.Errorf("client tidak terhubung setelah connect")
+			}
+			result.Duration = time.Since(startTime)
+
+			if errors.Is(result.Err, utils.ErrLockHeld) {
+				// Database sesi dipakai proses lain: lewati akun ini, jangan reconnect
+				sm.logger.Warn("Startup: Account %d (+%s) dilewati: %v", account.ID, account.PhoneNumber, result.Err)
+			} else if result.Err != nil {
+				sm.logger.Warn("Startup: Account %d (+%s) gagal connect dalam %v: %v", account.ID, account.PhoneNumber, result.Duration.Round(time.Millisecond), result.Err)
+				if timedOut {
+					// CreateClient masih berjalan: tunggu selesai sebelum diserahkan ke supervisor
+					// agar dua proses connect tidak berebut store akun yang sama
+					go sm.handOffAfterConnect(account, done)
+				} else {
+					// Serahkan ke supervisor untuk reconnect dengan backoff (jika client sudah dibuat)
+					handlers.ScheduleAccountReconnect(account.ID)
+				}
+			} else {
+				sm.logger.Success("Startup: Account %d (+%s) connected dalam %v", account.ID, account.PhoneNumber, result.Duration.Round(time.Millisecond))
+			}
+
+			results[index] = result
+		}(i, acc)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// handOffAfterConnect menunggu CreateClient akun yang melewati timeout startup selesai,
+// lalu menyerahkan akun ke supervisor untuk reconnect jika hasilnya tetap gagal
+func (sm *StartupManager) handOffAfterConnect(account *handlers.WhatsAppAccount, done <-chan startupConnectResult) {
+	res := <-done
+	if res.err == nil && res.client != nil && res.client.IsConnected() {
+		sm.logger.Success("Startup: Account %d (+%s) akhirnya connected setelah timeout", account.ID, account.PhoneNumber)
+		return
+	}
+	if errors.Is(res.err, utils.ErrLockHeld) {
+		return
+	}
+	handlers.ScheduleAccountReconnect(account.ID)
+}
+
+// sendStartupReports mengirim laporan startup ke setiap user Telegram pemilik akun
+// Akun yang pemiliknya tidak bisa ditentukan dilaporkan ke fallbackUserID
+func (sm *StartupManager) sendStartupReports(results []*AccountStartupResult, totalDuration time.Duration, fallbackUserID int64) {
+	resultsByUser := make(map[int64][]*AccountStartupResult)
+	userOrder := []int64{}
+
+	for _, result := range results {
+		if result == nil || result.Account == nil {
+			continue
+		}
+		ownerID := handlers.GetAccountTelegramID(result.Account)
+		if ownerID == 0 {
+			ownerID = fallbackUserID
+		}
+		if _, exists := resultsByUser[ownerID]; !exists {
+			userOrder = append(userOrder, ownerID)
+		}
+		resultsByUser[ownerID] = append(resultsByUser[ownerID], result)
+	}
+
+	for _, userID := range userOrder {
+		handlers.SendToTelegramUser(userID, formatStartupReport(resultsByUser[userID], totalDuration))
+	}
+}
+
+// formatStartupReport membuat pesan laporan startup untuk satu user
+func formatStartupReport(results []*AccountStartupResult, totalDuration time.Duration) string {
+	var connected, failed []*AccountStartupResult
+	for _, result := range results {
+		if result.Err == nil {
+			connected = append(connected, result)
+		} else {
+			failed = append(failed, result)
+		}
+	}
+
+	var report strings.Builder
+	report.WriteString("🚀 **LAPORAN STARTUP**\n\n")
+	report.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	report.WriteString(fmt.Sprintf("📊 **Total Akun:** %d\n", len(results)))
+	report.WriteString(fmt.Sprintf("✅ **Terhubung:** %d\n", len(connected)))
+	report.WriteString(fmt.Sprintf("❌ **Gagal:** %d\n\n", len(failed)))
+
+	if len(connected) > 0 {
+		report.WriteString("✅ **AKUN TERHUBUNG:**\n")
+		for _, result := range connected {
+			report.WriteString(fmt.Sprintf("• +%s (%.1f detik)\n", result.Account.PhoneNumber, result.Duration.Seconds()))
+		}
+		report.WriteString("\n")
+	}
+
+	if len(failed) > 0 {
+		report.WriteString("❌ **AKUN GAGAL:**\n")
+		for _, result := range failed {
+			report.WriteString(fmt.Sprintf("• +%s\n   └ %s\n", result.Account.PhoneNumber, result.Err.Error()))
+		}
+		report.WriteString("\n🔁 Akun yang gagal akan dicoba ulang otomatis. Cek menu 📡 Status Koneksi.\n\n")
+	}
+
+	report.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	report.WriteString(fmt.Sprintf("⏱️ **Waktu Startup:** %.1f detik", totalDuration.Seconds()))
+
+	return report.String()
+}
@@ -0,0 +1,627 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// AccountState adalah state lifecycle koneksi sebuah akun WhatsApp
+type AccountState string
+
+const (
+	StatePairing           AccountState = "pairing"
+	StateConnecting        AccountState = "connecting"
+	StateConnected         AccountState = "connected"
+	StateDisconnected      AccountState = "disconnected"
+	StateBackingOff        AccountState = "backing_off"
+	StateLoggedOut         AccountState = "logged_out"
+	StateStreamReplaced    AccountState = "stream_replaced"
+	StateTemporarilyBanned AccountState = "temporarily_banned"
+)
+
+// Konfigurasi backoff reconnect otomatis
+const (
+	reconnectBaseDelay   = 5 * time.Second
+	reconnectMaxDelay    = 5 * time.Minute
+	reconnectWaitTimeout = 15 * time.Second
+	defaultTempBanDelay  = 1 * time.Hour
+)
+
+// accountStateTransitions mendefinisikan transisi yang valid untuk setiap state
+// Transisi di luar tabel ini tetap dicatat (event WhatsApp adalah sumber kebenaran) tapi di-log sebagai warning
+var accountStateTransitions = map[AccountState][]AccountState{
+	StatePairing:           {StateConnecting, StateConnected, StateDisconnected, StateLoggedOut},
+	StateConnecting:        {StateConnected, StateDisconnected, StateBackingOff, StateLoggedOut, StateStreamReplaced, StateTemporarilyBanned},
+	StateConnected:         {StateDisconnected, StateLoggedOut, StateStreamReplaced, StateTemporarilyBanned},
+	StateDisconnected:      {StateConnecting, StateConnected, StateBackingOff, StateLoggedOut},
+	StateBackingOff:        {StateConnecting, StateConnected, StateLoggedOut},
+	StateLoggedOut:         {StatePairing},
+	StateStreamReplaced:    {StateConnecting, StateConnected},
+	StateTemporarilyBanned: {StateBackingOff, StateConnecting, StateConnected, StateLoggedOut},
+}
+
+// AccountStatus menyimpan state lifecycle terkini sebuah akun
+type AccountStatus struct {
+	AccountID int
+	State     AccountState
+	Since     time.Time
+	Reason    string
+	Attempt   int       // Percobaan reconnect ke-berapa (0 jika tidak sedang reconnect)
+	NextRetry time.Time // Jadwal reconnect berikutnya (zero jika tidak ada)
+}
+
+// accountSupervisor mengawasi koneksi satu akun dan menjalankan reconnect otomatis
+type accountSupervisor struct {
+	status       AccountStatus
+	reconnecting bool // Loop reconnect (otomatis atau manual) sedang memegang akun ini
+	stopCh       chan struct{}
+	retryNow     chan struct{} // Memotong jeda backoff saat user menekan reconnect manual
+}
+
+var (
+	accountSupervisors      = make(map[int]*accountSupervisor)
+	accountSupervisorsMutex sync.Mutex
+	supervisorsStopped      bool
+)
+
+// getAccountSupervisor mendapatkan (atau membuat) supervisor untuk akun
+// Caller harus memegang accountSupervisorsMutex
+func getAccountSupervisor(accountID int) *accountSupervisor {
+	sup, exists := accountSupervisors[accountID]
+	if !exists {
+		sup = &accountSupervisor{
+			status:   AccountStatus{AccountID: accountID, State: StateDisconnected, Since: time.Now()},
+			stopCh:   make(chan struct{}),
+			retryNow: make(chan struct{}, 1),
+		}
+		if persisted, err := loadPersistedAccountState(accountID); err == nil && persisted != nil {
+			sup.status = *persisted
+		}
+		accountSupervisors[accountID] = sup
+	}
+	return sup
+}
+
+// isTerminalAccountState mengecek apakah state tidak boleh di-reconnect otomatis
+func isTerminalAccountState(state AccountState) bool {
+	return state == StateLoggedOut || state == StateStreamReplaced
+}
+
+// isValidAccountTransition mengecek apakah transisi state terdaftar di state machine
+func isValidAccountTransition(from, to AccountState) bool {
+	for _, allowed := range accountStateTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAccountStatus mendapatkan state lifecycle terkini sebuah akun
+func GetAccountStatus(accountID int) AccountStatus {
+	accountSupervisorsMutex.Lock()
+	defer accountSupervisorsMutex.Unlock()
+	return getAccountSupervisor(accountID).status
+}
+
+// TransitionAccountState memindahkan akun ke state baru, mencatat transisi ke database master,
+// dan menyelaraskan kolom status lama ("active"/"inactive") di whatsapp_accounts
+func TransitionAccountState(accountID int, to AccountState, reason string) {
+	accountSupervisorsMutex.Lock()
+	sup := getAccountSupervisor(accountID)
+	from := sup.status.State
+	if from == to && sup.status.Reason == reason {
+		accountSupervisorsMutex.Unlock()
+		return
+	}
+
+	if from != to && !isValidAccountTransition(from, to) {
+		utils.GetLogger().Warn("Supervisor: Transisi tidak biasa untuk account %d: %s -> %s (%s)", accountID, from, to, reason)
+	}
+
+	sup.status.State = to
+	sup.status.Reason = reason
+	if from != to {
+		sup.status.Since = time.Now()
+	}
+	if to == StateConnected || isTerminalAccountState(to) {
+		sup.status.Attempt = 0
+		sup.status.NextRetry = time.Time{}
+	}
+	status := sup.status
+	accountSupervisorsMutex.Unlock()
+
+	utils.GetLogger().Info("Supervisor: Account %d %s -> %s (%s)", accountID, from, to, reason)
+
+	if err := persistAccountTransition(status, from); err != nil {
+		utils.GetLogger().Warn("Supervisor: Gagal simpan transisi account %d: %v", accountID, err)
+	}
+
+	// Selaraskan status lama agar fitur lain (daftar akun, switch) tetap konsisten
+	legacyStatus := "inactive"
+	if to == StateConnected {
+		legacyStatus = "active"
+	}
+	am := GetAccountManager()
+	if account := am.GetAccount(accountID); account != nil && account.Status != legacyStatus {
+		if err := am.UpdateAccountStatus(accountID, legacyStatus); err != nil {
+			utils.GetLogger().Warn("Supervisor: Gagal update status account %d: %v", accountID, err)
+		}
+	}
+}
+
+// HandleAccountLifecycleEvent menggerakkan state machine akun berdasarkan event whatsmeow
+// Dipanggil dari event handler per akun (core.NewAccountEventHandler)
+func HandleAccountLifecycleEvent(accountID int, evt interface{}) {
+	switch v := evt.(type) {
+	case *events.QR:
+		TransitionAccountState(accountID, StatePairing, "menunggu scan QR / kode pairing")
+	case *events.PairSuccess:
+		TransitionAccountState(accountID, StateConnecting, "pairing berhasil, menyambungkan ulang")
+	case *events.Connected:
+		TransitionAccountState(accountID, StateConnected, "terhubung")
+	case *events.Disconnected:
+		TransitionAccountState(accountID, StateDisconnected, "koneksi terputus")
+		startAccountReconnect(accountID, 0)
+	case *events.ConnectFailure:
+		TransitionAccountState(accountID, StateDisconnected, fmt.Sprintf("connect failure %d: %s", v.Reason, v.Message))
+		startAccountReconnect(accountID, 0)
+	case *events.ClientOutdated:
+		TransitionAccountState(accountID, StateDisconnected, "versi client ditolak server (outdated)")
+	case *events.LoggedOut:
+		TransitionAccountState(accountID, StateLoggedOut, fmt.Sprintf("logout (reason %d)", v.Reason))
+		stopAccountReconnect(accountID)
+	case *events.StreamReplaced:
+		TransitionAccountState(accountID, StateStreamReplaced, "sesi diambil alih client lain dengan kunci yang sama")
+		stopAccountReconnect(accountID)
+	case *events.TemporaryBan:
+		delay := v.Expire
+		if delay <= 0 {
+			delay = defaultTempBanDelay
+		}
+		TransitionAccountState(accountID, StateTemporarilyBanned, v.String())
+		startAccountReconnect(accountID, delay)
+	}
+}
+
+// reconnectBackoffDelay menghitung delay exponential backoff untuk percobaan ke-n
+func reconnectBackoffDelay(attempt int) time.Duration {
+	delay := reconnectBaseDelay
+	for i := 1; i < attempt && delay < reconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > reconnectMaxDelay {
+		delay = reconnectMaxDelay
+	}
+	return delay
+}
+
+// startAccountReconnect memulai loop reconnect dengan exponential backoff untuk akun
+// initialDelay > 0 dipakai untuk menunggu masa temporary ban sebelum percobaan pertama
+func startAccountReconnect(accountID int, initialDelay time.Duration) {
+	accountSupervisorsMutex.Lock()
+	if supervisorsStopped {
+		accountSupervisorsMutex.Unlock()
+		return
+	}
+	sup := getAccountSupervisor(accountID)
+	if sup.reconnecting {
+		accountSupervisorsMutex.Unlock()
+		return
+	}
+	sup.reconnecting = true
+	stopCh := sup.stopCh
+	accountSupervisorsMutex.Unlock()
+
+	go runAccountReconnect(accountID, sup, stopCh, initialDelay)
+}
+
+// releaseAccountReconnect melepas tanda reconnect agar loop berikutnya boleh dimulai
+// Permintaan retry yang tersisa dibuang supaya loop berikutnya tidak melewati jeda pertamanya
+func releaseAccountReconnect(sup *accountSupervisor) {
+	accountSupervisorsMutex.Lock()
+	sup.reconnecting = false
+	select {
+	case <-sup.retryNow:
+	default:
+	}
+	accountSupervisorsMutex.Unlock()
+}
+
+// runAccountReconnect menjalankan loop backoff sampai berhasil, dihentikan, atau akun masuk state terminal
+// Caller harus sudah menandai sup.reconnecting; tanda dilepas saat loop selesai
+func runAccountReconnect(accountID int, sup *accountSupervisor, stopCh chan struct{}, initialDelay time.Duration) {
+	defer releaseAccountReconnect(sup)
+
+	for attempt := 1; ; attempt++ {
+		delay := reconnectBackoffDelay(attempt)
+		if attempt == 1 && initialDelay > 0 {
+			delay = initialDelay
+		}
+
+		accountSupervisorsMutex.Lock()
+		sup.status.Attempt = attempt
+		sup.status.NextRetry = time.Now().Add(delay)
+		accountSupervisorsMutex.Unlock()
+		TransitionAccountState(accountID, StateBackingOff, fmt.Sprintf("reconnect percobaan ke-%d dalam %s", attempt, delay))
+
+		select {
+		case <-time.After(delay):
+		case <-sup.retryNow:
+		case <-stopCh:
+			return
+		}
+
+		if isTerminalAccountState(GetAccountStatus(accountID).State) {
+			return
+		}
+
+		am := GetAccountManager()
+		am.mutex.RLock()
+		client := am.clients[accountID]
+		am.mutex.RUnlock()
+		if client == nil || client.Store == nil || client.Store.ID == nil {
+			utils.GetLogger().Warn("Supervisor: Client account %d tidak tersedia, reconnect dihentikan", accountID)
+			return
+		}
+
+		if reconnectAccountClient(accountID, client) {
+			return
+		}
+	}
+}
+
+// reconnectAccountClient mencoba menyambungkan ulang client akun, return true jika berhasil
+func reconnectAccountClient(accountID int, client *whatsmeow.Client) bool {
+	if client.IsConnected() {
+		TransitionAccountState(accountID, StateConnected, "sudah terhubung")
+		return true
+	}
+
+	TransitionAccountState(accountID, StateConnecting, "menyambungkan ulang")
+	if err := client.Connect(); err != nil {
+		utils.GetLogger().Warn("Supervisor: Reconnect account %d gagal: %v", accountID, err)
+		return false
+	}
+
+	deadline := time.Now().Add(reconnectWaitTimeout)
+	for !client.IsConnected() && time.Now().Before(deadline) {
+		time.Sleep(500 * time.Millisecond)
+	}
+	if !client.IsConnected() {
+		utils.GetLogger().Warn("Supervisor: Reconnect account %d timeout", accountID)
+		client.Disconnect()
+		return false
+	}
+
+	// State connected dicatat oleh event Connected, tapi pastikan tercatat jika event terlambat
+	TransitionAccountState(accountID, StateConnected, "reconnect berhasil")
+	return true
+}
+
+// stopAccountReconnect menghentikan loop reconnect akun yang sedang berjalan
+func stopAccountReconnect(accountID int) {
+	accountSupervisorsMutex.Lock()
+	defer accountSupervisorsMutex.Unlock()
+
+	sup, exists := accountSupervisors[accountID]
+	if !exists {
+		return
+	}
+	close(sup.stopCh)
+	sup.stopCh = make(chan struct{})
+}
+
+// RemoveAccountSupervisor menghentikan dan menghapus supervisor akun (dipanggil saat akun dihapus)
+func RemoveAccountSupervisor(accountID int) {
+	accountSupervisorsMutex.Lock()
+	defer accountSupervisorsMutex.Unlock()
+
+	if sup, exists := accountSupervisors[accountID]; exists {
+		close(sup.stopCh)
+		delete(accountSupervisors, accountID)
+	}
+}
+
+// StopAllAccountSupervisors menghentikan semua loop reconnect (dipakai saat shutdown)
+func StopAllAccountSupervisors() {
+	accountSupervisorsMutex.Lock()
+	defer accountSupervisorsMutex.Unlock()
+
+	supervisorsStopped = true
+	for _, sup := range accountSupervisors {
+		close(sup.stopCh)
+		sup.stopCh = make(chan struct{})
+	}
+}
+
+// initAccountStateTables membuat tabel untuk menyimpan state dan riwayat transisi akun
+func initAccountStateTables() error {
+	db, err := getMasterBotDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS account_states (
+			account_id INTEGER PRIMARY KEY,
+			state TEXT NOT NULL,
+			reason TEXT,
+			since DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS account_state_transitions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id INTEGER NOT NULL,
+			from_state TEXT,
+			to_state TEXT NOT NULL,
+			reason TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_account_state_transitions_account ON account_state_transitions(account_id, created_at)`)
+	return err
+}
+
+// persistAccountTransition menyimpan state terkini dan riwayat transisi ke database master
+func persistAccountTransition(status AccountStatus, from AccountState) error {
+	db, err := getMasterBotDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	since := status.Since.Format("2006-01-02 15:04:05")
+	if _, err := db.Exec(`
+		INSERT INTO account_states (account_id, state, reason, since)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(account_id) DO UPDATE SET state = excluded.state, reason = excluded.reason, since = excluded.since
+	`, status.AccountID, string(status.State), status.Reason, since); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO account_state_transitions (account_id, from_state, to_state, reason)
+		VALUES (?, ?, ?, ?)
+	`, status.AccountID, string(from), string(status.State), status.Reason)
+	return err
+}
+
+// loadPersistedAccountState memuat state terakhir akun dari database master (nil jika belum ada)
+func loadPersistedAccountState(accountID int) (*AccountStatus, error) {
+	db, err := getMasterBotDB()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	var state, reason, since string
+	err = db.QueryRow(`SELECT state, COALESCE(reason, ''), since FROM account_states WHERE account_id = ?`, accountID).Scan(&state, &reason, &since)
+	if err != nil {
+		return nil, err
+	}
+
+	sinceTime, err := time.ParseInLocation("2006-01-02 15:04:05", since, time.Local)
+	if err != nil {
+		sinceTime = time.Now()
+	}
+
+	return &AccountStatus{
+		AccountID: accountID,
+		State:     AccountState(state),
+		Since:     sinceTime,
+		Reason:    reason,
+	}, nil
+}
+
+// clearAccountCurrentState menghapus state terkini akun yang dihapus dari database master
+// Riwayat transisi dibiarkan sebagai jejak audit; state terkini dihapus karena ID akun bisa dipakai ulang
+// dan akun baru tidak boleh mewarisi state lama (misalnya logged_out yang memblokir reconnect)
+func clearAccountCurrentState(accountID int) {
+	db, err := getMasterBotDB()
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	_, _ = db.Exec("DELETE FROM account_states WHERE account_id = ?", accountID)
+}
+
+// beginAccountPairing menandai akun milik user dengan nomor tersebut sedang pairing ulang
+// Return account ID, atau -1 jika nomor belum terdaftar (akun baru baru mendapat ID setelah pairing berhasil)
+func beginAccountPairing(telegramID int64, phone string) int {
+	for _, acc := range GetAccountManager().GetAllAccounts() {
+		if acc.PhoneNumber == phone && GetAccountTelegramID(acc) == telegramID {
+			stopAccountReconnect(acc.ID)
+			TransitionAccountState(acc.ID, StatePairing, "pairing ulang via kode")
+			return acc.ID
+		}
+	}
+	return -1
+}
+
+// getAccountStateLabel mendapatkan label tampilan untuk state akun
+func getAccountStateLabel(state AccountState) string {
+	switch state {
+	case StatePairing:
+		return "🔗 Pairing"
+	case StateConnecting:
+		return "🔄 Menghubungkan"
+	case StateConnected:
+		return "🟢 Terhubung"
+	case StateDisconnected:
+		return "🔴 Terputus"
+	case StateBackingOff:
+		return "⏳ Menunggu Reconnect"
+	case StateLoggedOut:
+		return "🚪 Logout"
+	case StateStreamReplaced:
+		return "♻️ Diambil Alih Sesi Lain"
+	case StateTemporarilyBanned:
+		return "⛔ Banned Sementara"
+	default:
+		return "❔ " + string(state)
+	}
+}
+
+// formatStateDuration memformat durasi sejak state berubah (contoh: 2 jam 15 menit)
+func formatStateDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d detik", int(d.Seconds()))
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%d menit", int(d.Minutes()))
+	}
+	if d < 24*time.Hour {
+		return fmt.Sprintf("%d jam %d menit", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%d hari %d jam", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+// ShowAccountStatusEdit menampilkan state koneksi setiap akun milik user beserta sejak kapan
+// SECURITY: Hanya menampilkan akun milik user yang memanggil (filter by TelegramID)
+func ShowAccountStatusEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	am := GetAccountManager()
+
+	userAccounts := []*WhatsAppAccount{}
+	for _, acc := range am.GetAllAccounts() {
+		if GetAccountTelegramID(acc) == chatID {
+			userAccounts = append(userAccounts, acc)
+		}
+	}
+	sort.Slice(userAccounts, func(i, j int) bool { return userAccounts[i].ID < userAccounts[j].ID })
+
+	var msgText strings.Builder
+	msgText.WriteString("📡 **STATUS KONEKSI AKUN**\n\n")
+	msgText.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	var keyboardRows [][]tgbotapi.InlineKeyboardButton
+
+	if len(userAccounts) == 0 {
+		msgText.WriteString("❌ Belum ada akun WhatsApp terdaftar.\n\n")
+		msgText.WriteString("💡 Gunakan 'Login Baru' untuk menambahkan akun.")
+	} else {
+		now := time.Now()
+		for i, acc := range userAccounts {
+			status := GetAccountStatus(acc.ID)
+			msgText.WriteString(fmt.Sprintf("%d. 📱 **+%s**\n", i+1, acc.PhoneNumber))
+			msgText.WriteString(fmt.Sprintf("   %s\n", getAccountStateLabel(status.State)))
+			msgText.WriteString(fmt.Sprintf("   🕐 Sejak: %s (%s)\n", status.Since.Format("02/01 15:04:05"), formatStateDuration(now.Sub(status.Since))))
+			if status.Reason != "" {
+				msgText.WriteString(fmt.Sprintf("   📝 %s\n", status.Reason))
+			}
+			if status.State == StateBackingOff && !status.NextRetry.IsZero() {
+				msgText.WriteString(fmt.Sprintf("   🔁 Percobaan ke-%d, berikutnya %s\n", status.Attempt, status.NextRetry.Format("15:04:05")))
+			}
+			msgText.WriteString("\n")
+
+			if status.State != StateConnected && status.State != StateLoggedOut {
+				keyboardRows = append(keyboardRows, tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🔁 Reconnect +%s", acc.PhoneNumber), fmt.Sprintf("account_reconnect_%d", acc.ID)),
+				))
+			}
+		}
+		msgText.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		msgText.WriteString("💡 Akun yang terputus akan di-reconnect otomatis dengan jeda bertahap.")
+	}
+
+	keyboardRows = append(keyboardRows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Refresh", "account_status_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "multi_account_menu"),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(keyboardRows...)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, msgText.String())
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleAccountReconnectRequest menjalankan reconnect manual untuk akun dari tombol status
+func HandleAccountReconnectRequest(accountIDStr string, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	accountID, err := strconv.Atoi(accountIDStr)
+	if err != nil {
+		return
+	}
+
+	am := GetAccountManager()
+	account := am.GetAccount(accountID)
+	if account == nil || GetAccountTelegramID(account) != chatID {
+		msg := tgbotapi.NewMessage(chatID, "❌ Akun tidak ditemukan.")
+		telegramBot.Send(msg)
+		return
+	}
+
+	// Cek dan tandai reconnect dalam satu lock agar tidak ada dua reconnect paralel untuk akun yang sama
+	accountSupervisorsMutex.Lock()
+	if supervisorsStopped {
+		accountSupervisorsMutex.Unlock()
+		return
+	}
+	sup := getAccountSupervisor(accountID)
+	if sup.reconnecting {
+		// Loop backoff sedang berjalan: potong jedanya agar langsung mencoba
+		select {
+		case sup.retryNow <- struct{}{}:
+		default:
+		}
+		accountSupervisorsMutex.Unlock()
+		ShowAccountStatusEdit(telegramBot, chatID, messageID)
+		return
+	}
+	sup.reconnecting = true
+	stopCh := sup.stopCh
+	accountSupervisorsMutex.Unlock()
+
+	go func() {
+		am.mutex.RLock()
+		client := am.clients[accountID]
+		am.mutex.RUnlock()
+
+		if client == nil {
+			if _, err := am.CreateClient(accountID); err != nil {
+				TransitionAccountState(accountID, StateDisconnected, fmt.Sprintf("reconnect manual gagal: %v", err))
+			}
+			releaseAccountReconnect(sup)
+		} else if reconnectAccountClient(accountID, client) {
+			releaseAccountReconnect(sup)
+		} else {
+			// Gagal: lanjutkan ke loop backoff yang memegang tanda reconnect yang sama
+			go runAccountReconnect(accountID, sup, stopCh, 0)
+		}
+		ShowAccountStatusEdit(telegramBot, chatID, messageID)
+	}()
+}
+
+// ScheduleAccountReconnect menjadwalkan reconnect dengan backoff untuk akun yang gagal connect
+// (misalnya saat startup). Tidak melakukan apa-apa jika akun sudah logout atau client belum dibuat
+func ScheduleAccountReconnect(accountID int) {
+	if isTerminalAccountState(GetAccountStatus(accountID).State) {
+		return
+	}
+	startAccountReconnect(accountID, 0)
+}
@@ -47,6 +47,12 @@ func AutoFetchGroupsAfterLogin(client *whatsmeow.Client, telegramBot *tgbotapi.B
 			return
 		}
 
+		// Cache status admin akun per grup untuk preflight operasi admin
+		cacheGroupCapabilities(client, joinedGroups)
+
+		// Cache keanggotaan komunitas (komunitas, subgrup, grup pengumuman)
+		cacheGroupCommunities(joinedGroups)
+
 		// Convert dan simpan ke database
 		groupsToSave := make(map[string]string)
 		for _, group := range joinedGroups {
@@ -133,6 +139,12 @@ func performPeriodicGroupRefresh() {
 		return
 	}
 
+	// Cache status admin akun per grup untuk preflight operasi admin
+	cacheGroupCapabilities(client, joinedGroups)
+
+	// Cache keanggotaan komunitas (komunitas, subgrup, grup pengumuman)
+	cacheGroupCommunities(joinedGroups)
+
 	// Convert dan simpan ke database
 	groupsToSave := make(map[string]string)
 	for _, group := range joinedGroups {
@@ -2130,6 +2130,24 @@ func StopBroadcast(chatID int64) {
 	state.IsRunning = false
 }
 
+// StopAllBroadcasts stops all running broadcasts (used on graceful shutdown)
+// Returns chat IDs whose broadcast was stopped so callers can notify them
+func StopAllBroadcasts() []int64 {
+	broadcastMutex.Lock()
+	running := make([]int64, 0)
+	for chatID, state := range broadcastStates {
+		if state != nil && state.IsRunning {
+			running = append(running, chatID)
+		}
+	}
+	broadcastMutex.Unlock()
+
+	for _, chatID := range running {
+		StopBroadcast(chatID)
+	}
+	return running
+}
+
 // IsBroadcastRunning checks if broadcast is running
 func IsBroadcastRunning(chatID int64) bool {
 	state := GetBroadcastState(chatID)
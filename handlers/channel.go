@@ -0,0 +1,489 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Jenis input yang ditunggu menu saluran
+const (
+	channelInputCreate      = "create"
+	channelInputName        = "name"
+	channelInputDescription = "description"
+	channelInputPhoto       = "photo"
+)
+
+const (
+	// Notice ToS pembuatan saluran (lihat dokumentasi AcceptTOSNotice di whatsmeow)
+	channelTOSNoticeID    = "20601218"
+	channelTOSNoticeStage = "5"
+	// Batas panjang nama / deskripsi saluran
+	channelMaxNameLength        = 100
+	channelMaxDescriptionLength = 2048
+	// Prefix link undangan saluran
+	channelInviteLinkPrefix = "https://whatsapp.com/channel/"
+)
+
+// ChannelState menyimpan state menu saluran per chat
+type ChannelState struct {
+	WaitingFor string          // "" = tidak menunggu input, lihat konstanta channelInput*
+	Channels   []utils.Channel // Daftar saluran di menu terakhir (callback memakai index)
+	Current    *utils.Channel  // Saluran yang sedang dibuka
+}
+
+var channelStates = make(map[int64]*ChannelState)
+
+// getChannelState mengambil (atau membuat) state saluran untuk chat
+func getChannelState(chatID int64) *ChannelState {
+	state := channelStates[chatID]
+	if state == nil {
+		state = &ChannelState{}
+		channelStates[chatID] = state
+	}
+	return state
+}
+
+// channelFromMetadata mengubah metadata newsletter whatsmeow ke data saluran yang disimpan
+func channelFromMetadata(meta *types.NewsletterMetadata) utils.Channel {
+	c := utils.Channel{
+		JID:             meta.ID.String(),
+		Name:            meta.ThreadMeta.Name.Text,
+		Description:     meta.ThreadMeta.Description.Text,
+		InviteCode:      meta.ThreadMeta.InviteCode,
+		SubscriberCount: meta.ThreadMeta.SubscriberCount,
+		Verified:        meta.ThreadMeta.VerificationState == types.NewsletterVerificationStateVerified,
+	}
+	if meta.ViewerMeta != nil {
+		c.Role = string(meta.ViewerMeta.Role)
+	}
+	return c
+}
+
+// channelInviteLink membuat link undangan saluran dari kode undangan
+func channelInviteLink(c *utils.Channel) string {
+	if c.InviteCode == "" {
+		return ""
+	}
+	return channelInviteLinkPrefix + c.InviteCode
+}
+
+// channelCanManage mengecek apakah akun bisa mengubah saluran (owner / admin)
+func channelCanManage(c *utils.Channel) bool {
+	return c.Role == string(types.NewsletterRoleOwner) || c.Role == string(types.NewsletterRoleAdmin)
+}
+
+// channelRoleLabel memformat peran akun di saluran
+func channelRoleLabel(role string) string {
+	switch types.NewsletterRole(role) {
+	case types.NewsletterRoleOwner:
+		return "👑 Pemilik"
+	case types.NewsletterRoleAdmin:
+		return "🛡️ Admin"
+	case types.NewsletterRoleSubscriber:
+		return "👁️ Mengikuti"
+	}
+	return "👁️ " + role
+}
+
+// refreshChannels memperbarui cache saluran dari WhatsApp (saluran yang dimiliki / diikuti)
+func refreshChannels(client *whatsmeow.Client) error {
+	if client == nil || !client.IsConnected() {
+		return fmt.Errorf("client WhatsApp tidak terhubung")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	newsletters, err := client.GetSubscribedNewsletters(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	var channels []utils.Channel
+	for _, meta := range newsletters {
+		if meta != nil {
+			channels = append(channels, channelFromMetadata(meta))
+		}
+	}
+	return utils.ReplaceChannels(channels)
+}
+
+// ShowChannelMenu menampilkan daftar saluran akun (messageID 0 = kirim pesan baru)
+func ShowChannelMenu(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := getChannelState(chatID)
+	state.WaitingFor = ""
+
+	refreshErr := refreshChannels(client)
+	channels, err := utils.GetChannels()
+	if err != nil {
+		refreshErr = err
+	}
+	state.Channels = channels
+
+	var text strings.Builder
+	text.WriteString("📣 SALURAN WHATSAPP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Saluran yang dimiliki atau diikuti akun ini. Buka saluran untuk melihat detail, mengambil link, atau mengubah nama/deskripsi/foto (khusus pemilik/admin).\n\n")
+	if refreshErr != nil {
+		text.WriteString(fmt.Sprintf("⚠️ Data mungkin belum terbaru: %v\n\n", refreshErr))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(channels) == 0 {
+		text.WriteString("Akun ini belum memiliki atau mengikuti saluran.")
+	}
+	for i, c := range channels {
+		text.WriteString(fmt.Sprintf("%d. %s\n   %s • 👥 %d pengikut\n", i+1, c.Name, channelRoleLabel(c.Role), c.SubscriberCount))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(truncateButtonLabel(fmt.Sprintf("📣 %d. %s", i+1, c.Name)), fmt.Sprintf("channel_view_%d", i)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Buat Saluran", "channel_create"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text.String())
+		msg.ReplyMarkup = keyboard
+		telegramBot.Send(msg)
+		return
+	}
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// OpenChannel membuka detail saluran dari index daftar di menu
+func OpenChannel(index int, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || index < 0 || index >= len(state.Channels) {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Daftar saluran sudah berubah. Buka ulang menu saluran."))
+		return
+	}
+	c := state.Channels[index]
+	state.Current = &c
+	ShowChannelDetail(chatID, messageID, telegramBot)
+}
+
+// ShowChannelDetail menampilkan detail saluran yang sedang dibuka (messageID 0 = pesan baru)
+func ShowChannelDetail(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || state.Current == nil {
+		return
+	}
+	state.WaitingFor = ""
+	c := state.Current
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📣 SALURAN: %s\n\n", c.Name))
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("🆔 JID: %s\n", c.JID))
+	text.WriteString(fmt.Sprintf("👤 Peran: %s\n", channelRoleLabel(c.Role)))
+	text.WriteString(fmt.Sprintf("👥 Pengikut: %d\n", c.SubscriberCount))
+	if c.Verified {
+		text.WriteString("✅ Terverifikasi\n")
+	}
+	if link := channelInviteLink(c); link != "" {
+		text.WriteString(fmt.Sprintf("🔗 Link: %s\n", link))
+	}
+	text.WriteString("\n📝 Deskripsi:\n")
+	if c.Description != "" {
+		text.WriteString(c.Description + "\n")
+	} else {
+		text.WriteString("-\n")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if channelCanManage(c) {
+		rows = append(rows,
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✏️ Ubah Nama", "channel_edit_name"),
+				tgbotapi.NewInlineKeyboardButtonData("📝 Ubah Deskripsi", "channel_edit_description"),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🖼️ Ubah Foto", "channel_edit_photo"),
+			),
+		)
+	} else {
+		text.WriteString("\n💡 Hanya pemilik/admin saluran yang bisa mengubah info saluran.")
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔗 Ambil Link", "channel_get_link"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Daftar Saluran", "channel_menu"),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text.String())
+		msg.ReplyMarkup = keyboard
+		telegramBot.Send(msg)
+		return
+	}
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// SendChannelInviteLink mengambil info terbaru saluran lalu mengirim link undangannya
+func SendChannelInviteLink(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || state.Current == nil {
+		return
+	}
+	if client == nil || client.Store.ID == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	jid, err := types.ParseJID(state.Current.JID)
+	var meta *types.NewsletterMetadata
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		meta, err = client.GetNewsletterInfo(ctx, jid)
+		cancel()
+	}
+	if err == nil && meta != nil {
+		updated := channelFromMetadata(meta)
+		if updated.Role == "" {
+			updated.Role = state.Current.Role // GetNewsletterInfo tidak selalu menyertakan viewer metadata
+		}
+		state.Current = &updated
+		utils.SaveChannel(updated)
+	}
+
+	link := channelInviteLink(state.Current)
+	if link == "" {
+		errText := "link tidak tersedia"
+		if err != nil {
+			errText = err.Error()
+		}
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal mengambil link saluran %s: %s", state.Current.Name, errText)))
+		return
+	}
+
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("🔗 Link saluran %s:\n%s", state.Current.Name, link)))
+}
+
+// StartChannelInput meminta input untuk buat saluran / ubah nama / deskripsi / foto
+func StartChannelInput(inputType string, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := getChannelState(chatID)
+	if inputType != channelInputCreate && (state.Current == nil || !channelCanManage(state.Current)) {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Saluran tidak ditemukan atau akun ini bukan pemilik/admin saluran."))
+		return
+	}
+	state.WaitingFor = inputType
+
+	var prompt string
+	cancelData := "channel_back"
+	switch inputType {
+	case channelInputCreate:
+		cancelData = "channel_menu"
+		prompt = "📣 BUAT SALURAN\n\n" +
+			"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+			"Baris 1: nama saluran\n" +
+			"Baris berikutnya (opsional): deskripsi\n\n" +
+			"Contoh:\nInfo Alumni 2020\nKabar terbaru untuk alumni angkatan 2020\n\n" +
+			"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+			"💡 Dengan membuat saluran, akun ini menyetujui ketentuan saluran WhatsApp.\n\n" +
+			"⏳ Menunggu input..."
+	case channelInputName:
+		prompt = fmt.Sprintf("✏️ UBAH NAMA SALURAN\n\n📣 Saat ini: %s\n\nKirim nama baru (maksimal %d karakter).\n\n⏳ Menunggu input...", state.Current.Name, channelMaxNameLength)
+	case channelInputDescription:
+		prompt = fmt.Sprintf("📝 UBAH DESKRIPSI SALURAN\n\n📣 Saluran: %s\n\nKirim deskripsi baru (maksimal %d karakter). Kirim - untuk mengosongkan deskripsi.\n\n⏳ Menunggu input...", state.Current.Name, channelMaxDescriptionLength)
+	case channelInputPhoto:
+		prompt = fmt.Sprintf("🖼️ UBAH FOTO SALURAN\n\n📣 Saluran: %s\n\nKirim foto (JPG/PNG) sebagai foto atau file. Foto otomatis dipotong persegi.\n\n⏳ Menunggu foto...", state.Current.Name)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", cancelData),
+		),
+	)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, prompt)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleChannelTextInput memproses input teks menu saluran (buat saluran / ubah nama / ubah deskripsi)
+func HandleChannelTextInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || state.WaitingFor == "" {
+		return
+	}
+	if state.WaitingFor == channelInputPhoto {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "🖼️ Kirim foto saluran (sebagai foto atau file gambar)."))
+		return
+	}
+	if client == nil || client.Store.ID == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	input = strings.TrimSpace(input)
+	switch state.WaitingFor {
+	case channelInputCreate:
+		name, description, _ := strings.Cut(input, "\n")
+		name, description = strings.TrimSpace(name), strings.TrimSpace(description)
+		if !validateChannelText(name, description, chatID, telegramBot) {
+			return
+		}
+		state.WaitingFor = ""
+		go createChannel(name, description, chatID, client, telegramBot)
+
+	case channelInputName:
+		if !validateChannelText(input, "", chatID, telegramBot) {
+			return
+		}
+		state.WaitingFor = ""
+		go updateChannel(map[string]any{"name": input}, "nama", chatID, client, telegramBot)
+
+	case channelInputDescription:
+		if input == "-" {
+			input = ""
+		}
+		if !validateChannelText(state.Current.Name, input, chatID, telegramBot) {
+			return
+		}
+		state.WaitingFor = ""
+		go updateChannel(map[string]any{"description": input}, "deskripsi", chatID, client, telegramBot)
+	}
+}
+
+// validateChannelText memvalidasi nama dan deskripsi saluran, mengirim pesan error jika tidak valid
+func validateChannelText(name, description string, chatID int64, telegramBot *tgbotapi.BotAPI) bool {
+	switch {
+	case name == "":
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Nama saluran tidak boleh kosong!"))
+	case utf8.RuneCountInString(name) > channelMaxNameLength:
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Nama saluran maksimal %d karakter!", channelMaxNameLength)))
+	case utf8.RuneCountInString(description) > channelMaxDescriptionLength:
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Deskripsi saluran maksimal %d karakter!", channelMaxDescriptionLength)))
+	default:
+		return true
+	}
+	return false
+}
+
+// HandleChannelPhotoUpload mengunduh foto dari Telegram lalu memasangnya sebagai foto saluran
+func HandleChannelPhotoUpload(fileID string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || state.WaitingFor != channelInputPhoto {
+		return
+	}
+	if client == nil || client.Store.ID == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	imgData, err := downloadZipPhotoFile(fileID, telegramBot.Token)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error mengunduh foto: %v", err)))
+		return
+	}
+	photoBytes, _, err := preparePhotoWithOptions(imgData, DefaultPhotoOptions)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Foto tidak valid: %v\n\nGunakan foto JPG/PNG yang valid.", err)))
+		return
+	}
+
+	state.WaitingFor = ""
+	// Foto dikirim sebagai base64 (encoding JSON []byte), sama seperti CreateNewsletterParams.Picture
+	go updateChannel(map[string]any{"picture": photoBytes}, "foto", chatID, client, telegramBot)
+}
+
+// createChannel membuat saluran baru lalu membuka detailnya
+func createChannel(name, description string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	loadingMsg, _ := telegramBot.Send(tgbotapi.NewMessage(chatID, "⏳ Membuat saluran..."))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Pembuatan saluran ditolak server sebelum notice ToS saluran disetujui
+	if err := client.AcceptTOSNotice(ctx, channelTOSNoticeID, channelTOSNoticeStage); err != nil {
+		utils.GetGrupLogger().Debug("createChannel: AcceptTOSNotice gagal: %v", err)
+	}
+	meta, err := client.CreateNewsletter(ctx, whatsmeow.CreateNewsletterParams{Name: name, Description: description})
+	telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, loadingMsg.MessageID))
+	if err != nil || meta == nil {
+		if err == nil {
+			err = fmt.Errorf("respons kosong dari server")
+		}
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorUnknown, err, "Gagal membuat saluran"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	created := channelFromMetadata(meta)
+	if created.Name == "" {
+		created.Name = name
+		created.Description = description
+	}
+	if created.Role == "" {
+		created.Role = string(types.NewsletterRoleOwner)
+	}
+	utils.SaveChannel(created)
+	utils.LogActivity("channel_create", fmt.Sprintf("Created channel %s (%s)", created.Name, created.JID), chatID)
+
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Saluran \"%s\" berhasil dibuat!", created.Name)))
+
+	state := getChannelState(chatID)
+	state.Current = &created
+	ShowChannelDetail(chatID, 0, telegramBot)
+}
+
+// updateChannel mengubah metadata saluran yang sedang dibuka (name / description / picture)
+func updateChannel(updates map[string]any, label string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := channelStates[chatID]
+	if state == nil || state.Current == nil {
+		return
+	}
+	current := state.Current
+
+	updates["settings"] = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	err := updateNewsletterMetadata(ctx, client, current.JID, updates)
+	cancel()
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorUnknown, err, fmt.Sprintf("Gagal mengubah %s saluran", label)))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	if name, ok := updates["name"].(string); ok {
+		current.Name = name
+	}
+	if description, ok := updates["description"].(string); ok {
+		current.Description = description
+	}
+	utils.SaveChannel(*current)
+	utils.LogActivity("channel_update", fmt.Sprintf("Updated %s of channel %s", label, current.JID), chatID)
+
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ %s saluran %s berhasil diubah.", strings.ToUpper(label[:1])+label[1:], current.Name)))
+	ShowChannelDetail(chatID, 0, telegramBot)
+}
+
+// IsWaitingForChannelInput mengecek apakah user sedang mengisi input menu saluran
+func IsWaitingForChannelInput(chatID int64) bool {
+	state := channelStates[chatID]
+	return state != nil && state.WaitingFor != ""
+}
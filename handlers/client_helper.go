@@ -4,6 +4,7 @@ import (
 	"whatsapp-bot/utils"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
 )
 
 // GetActiveClientOrFallback mendapatkan client aktif atau fallback ke parameter
@@ -45,3 +46,23 @@ func ValidateClientForBackgroundProcess(fallbackClient *whatsmeow.Client, proces
 
 	return client, false
 }
+
+// isOwnJID mengecek apakah JID (nomor atau LID) milik akun client ini
+func isOwnJID(client *whatsmeow.Client, jid types.JID) bool {
+	if client == nil || client.Store.ID == nil || jid.User == "" {
+		return false
+	}
+	if jid.User == client.Store.ID.User {
+		return true
+	}
+	return client.Store.LID.User != "" && jid.User == client.Store.LID.User
+}
+
+// clientAccountID mengembalikan ID akun WhatsApp (nomor) milik client, kosong jika client belum login
+// Dipakai sebagai account_id data per akun (registry link, aturan, template, blocklist, dll)
+func clientAccountID(client *whatsmeow.Client) string {
+	if client == nil || client.Store.ID == nil {
+		return ""
+	}
+	return client.Store.ID.User
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+)
+
+// csvTableRow adalah satu baris data CSV beserta nomor barisnya di file (header = baris 1)
+type csvTableRow struct {
+	Line  int
+	Cells []string
+}
+
+// readCSVTable membaca CSV hasil ekspor spreadsheet dan memetakan header ke nama kolom kanonik lewat aliases
+// Return index kolom per nama kanonik dan baris data (baris kosong dilewati)
+func readCSVTable(content []byte, aliases map[string]string) (map[string]int, []csvTableRow, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")) // BOM dari Excel
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	// Spreadsheet dengan locale Indonesia biasanya mengekspor CSV dengan pemisah ";"
+	firstLine, _, _ := strings.Cut(string(content), "\n")
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("format CSV salah: %v", err)
+	}
+	if len(records) < 2 {
+		return nil, nil, fmt.Errorf("file harus berisi header dan minimal satu baris data")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		column, ok := aliases[strings.ToLower(strings.TrimSpace(header))]
+		if !ok {
+			return nil, nil, fmt.Errorf("kolom \"%s\" tidak dikenal", header)
+		}
+		if _, exists := columns[column]; exists {
+			return nil, nil, fmt.Errorf("kolom \"%s\" muncul lebih dari sekali", header)
+		}
+		columns[column] = i
+	}
+
+	var rows []csvTableRow
+	for i, record := range records[1:] {
+		// Lewati baris kosong
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, csvTableRow{Line: i + 2, Cells: record})
+	}
+	return columns, rows, nil
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// Alasan grup dilewati saat preflight operasi admin
+const (
+	preflightNotMember = "not_member"
+	preflightNotAdmin  = "not_admin"
+)
+
+// Syarat operasi yang dicek saat preflight
+const (
+	requireAdmin     = "admin"      // Hanya admin (pengaturan grup, link undangan, admin/unadmin)
+	requireEditInfo  = "edit_info"  // Admin, atau semua anggota jika info grup tidak dikunci
+	requireAddMember = "add_member" // Admin, atau semua anggota jika mode tambah anggota terbuka
+)
+
+const (
+	// Cache status grup dianggap basi setelah durasi ini dan di-refresh via GetJoinedGroups
+	groupCapabilityMaxAge = 30 * time.Minute
+	// Maksimal nama grup yang ditampilkan per alasan skip
+	preflightDisplayLimit = 10
+	// Konfirmasi preflight yang tidak dijawab selama ini dianggap ditinggalkan
+	preflightConfirmTimeout = 30 * time.Minute
+)
+
+// adminPreflight adalah operasi bulk yang menunggu konfirmasi karena sebagian grup akan dilewati
+type adminPreflight struct {
+	Name    string
+	Allowed []GroupLinkInfo
+	Start   func(allowed []GroupLinkInfo)
+	Cleanup func() // Dijalankan jika operasi tidak jadi dimulai (boleh nil)
+}
+
+var (
+	pendingPreflights      = make(map[int64]*adminPreflight)
+	pendingPreflightsMutex sync.Mutex
+)
+
+// takePendingPreflight mengambil dan menghapus preflight yang menunggu konfirmasi untuk chat
+func takePendingPreflight(chatID int64) *adminPreflight {
+	pendingPreflightsMutex.Lock()
+	defer pendingPreflightsMutex.Unlock()
+	pending := pendingPreflights[chatID]
+	delete(pendingPreflights, chatID)
+	return pending
+}
+
+// setPendingPreflight menyimpan preflight yang menunggu konfirmasi
+// Preflight lama di chat yang sama dianggap ditinggalkan, begitu juga yang tidak dijawab sampai timeout
+func setPendingPreflight(chatID int64, pending *adminPreflight) {
+	pendingPreflightsMutex.Lock()
+	previous := pendingPreflights[chatID]
+	pendingPreflights[chatID] = pending
+	pendingPreflightsMutex.Unlock()
+
+	if previous != nil {
+		previous.cleanup()
+	}
+
+	time.AfterFunc(preflightConfirmTimeout, func() {
+		pendingPreflightsMutex.Lock()
+		expired := pendingPreflights[chatID] == pending
+		if expired {
+			delete(pendingPreflights, chatID)
+		}
+		pendingPreflightsMutex.Unlock()
+		if expired {
+			pending.cleanup()
+		}
+	})
+}
+
+// cleanup menjalankan Cleanup preflight jika ada
+func (p *adminPreflight) cleanup() {
+	if p != nil && p.Cleanup != nil {
+		p.Cleanup()
+	}
+}
+
+// groupCapabilitiesFromJoined membuat cache status akun dari hasil GetJoinedGroups
+func groupCapabilitiesFromJoined(client *whatsmeow.Client, joinedGroups []*types.GroupInfo) []utils.GroupCapability {
+	var caps []utils.GroupCapability
+	for _, group := range joinedGroups {
+		if group == nil {
+			continue
+		}
+		c := utils.GroupCapability{
+			GroupJID:     group.JID.String(),
+			IsAnnounce:   group.IsAnnounce,
+			IsLocked:     group.IsLocked,
+			MemberAddAll: group.MemberAddMode == types.GroupMemberAddModeAllMember,
+		}
+		for _, participant := range group.Participants {
+			if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+				c.IsAdmin = participant.IsAdmin || participant.IsSuperAdmin
+				break
+			}
+		}
+		caps = append(caps, c)
+	}
+	return caps
+}
+
+// cacheGroupCapabilities menyimpan status akun (member/admin) di semua grup hasil GetJoinedGroups
+func cacheGroupCapabilities(client *whatsmeow.Client, joinedGroups []*types.GroupInfo) {
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		return
+	}
+	if err := utils.ReplaceGroupCapabilities(accountID, groupCapabilitiesFromJoined(client, joinedGroups)); err != nil {
+		utils.GetGrupLogger().Warn("cacheGroupCapabilities: Gagal menyimpan cache status grup: %v", err)
+	}
+}
+
+// loadGroupCapabilities mengambil cache status akun; refresh dari WhatsApp jika basi atau ada grup yang belum tercatat
+// Return ok=false jika status tidak bisa dipastikan (cache kosong dan refresh gagal)
+func loadGroupCapabilities(client *whatsmeow.Client, groups []GroupLinkInfo) (map[string]utils.GroupCapability, bool) {
+	accountID := clientAccountID(client)
+	caps, refreshedAt, err := utils.GetGroupCapabilities(accountID)
+
+	stale := err != nil || len(caps) == 0 || time.Since(refreshedAt) > groupCapabilityMaxAge
+	if !stale {
+		for _, group := range groups {
+			if _, ok := caps[group.JID]; !ok {
+				stale = true // Grup baru atau akun baru keluar: pastikan dengan data terbaru
+				break
+			}
+		}
+	}
+	if !stale {
+		return caps, true
+	}
+
+	if client == nil || !client.IsConnected() {
+		return caps, len(caps) > 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	joinedGroups, err := client.GetJoinedGroups(ctx)
+	cancel()
+	if err != nil {
+		utils.GetGrupLogger().Debug("loadGroupCapabilities: GetJoinedGroups gagal: %v", err)
+		return caps, len(caps) > 0
+	}
+
+	cacheGroupCapabilities(client, joinedGroups)
+	caps = make(map[string]utils.GroupCapability)
+	for _, c := range groupCapabilitiesFromJoined(client, joinedGroups) {
+		caps[c.GroupJID] = c
+	}
+	return caps, true
+}
+
+// splitGroupsByCapability memisahkan grup yang bisa diproses dan yang akan dilewati (per alasan) sesuai syarat operasi
+func splitGroupsByCapability(groups []GroupLinkInfo, caps map[string]utils.GroupCapability, requirement string) ([]GroupLinkInfo, map[string][]GroupLinkInfo) {
+	var allowed []GroupLinkInfo
+	skipped := make(map[string][]GroupLinkInfo)
+
+	for _, group := range groups {
+		c, ok := caps[group.JID]
+		switch {
+		case !ok:
+			skipped[preflightNotMember] = append(skipped[preflightNotMember], group)
+		case c.IsAdmin,
+			requirement == requireEditInfo && !c.IsLocked,
+			requirement == requireAddMember && c.MemberAddAll:
+			allowed = append(allowed, group)
+		default:
+			skipped[preflightNotAdmin] = append(skipped[preflightNotAdmin], group)
+		}
+	}
+	return allowed, skipped
+}
+
+// StartWithAdminPreflight mengecek status admin akun di grup target sebelum operasi bulk dimulai
+// Jika semua grup bisa diproses, start langsung dijalankan; jika tidak, breakdown ditampilkan untuk dikonfirmasi
+func StartWithAdminPreflight(chatID int64, name string, groups []GroupLinkInfo, requirement string, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, start func(allowed []GroupLinkInfo)) {
+	StartWithAdminPreflightCleanup(chatID, name, groups, requirement, client, telegramBot, start, nil)
+}
+
+// StartWithAdminPreflightCleanup sama dengan StartWithAdminPreflight, ditambah cleanup yang dijalankan jika
+// operasi tidak jadi dimulai: dibatalkan, tidak ada grup yang bisa diproses, atau konfirmasi ditinggalkan
+func StartWithAdminPreflightCleanup(chatID int64, name string, groups []GroupLinkInfo, requirement string, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, start func(allowed []GroupLinkInfo), cleanup func()) {
+	go func() {
+		caps, ok := loadGroupCapabilities(client, groups)
+		if !ok {
+			// Status tidak bisa dipastikan: jangan blokir, biarkan error per grup yang melapor
+			start(groups)
+			return
+		}
+
+		allowed, skipped := splitGroupsByCapability(groups, caps, requirement)
+		if len(skipped) == 0 {
+			start(allowed)
+			return
+		}
+
+		var text strings.Builder
+		text.WriteString(fmt.Sprintf("🛡️ CEK STATUS ADMIN - %s\n\n", strings.ToUpper(name)))
+		text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		text.WriteString(fmt.Sprintf("📋 Total grup: %d\n", len(groups)))
+		text.WriteString(fmt.Sprintf("✅ Bisa diproses: %d\n", len(allowed)))
+		text.WriteString(fmt.Sprintf("⏭️ Akan dilewati: %d\n", len(groups)-len(allowed)))
+
+		sections := []struct {
+			reason string
+			title  string
+		}{
+			{preflightNotAdmin, "👤 Bukan admin"},
+			{preflightNotMember, "🚪 Bukan member"},
+		}
+		for _, section := range sections {
+			list := skipped[section.reason]
+			if len(list) == 0 {
+				continue
+			}
+			text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+			text.WriteString(fmt.Sprintf("%s (%d):\n", section.title, len(list)))
+			for i, group := range list {
+				if i >= preflightDisplayLimit {
+					text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(list)-preflightDisplayLimit))
+					break
+				}
+				text.WriteString(fmt.Sprintf("• %s\n", group.Name))
+			}
+		}
+
+		var rows [][]tgbotapi.InlineKeyboardButton
+		if len(allowed) > 0 {
+			setPendingPreflight(chatID, &adminPreflight{Name: name, Allowed: allowed, Start: start, Cleanup: cleanup})
+			text.WriteString(fmt.Sprintf("\n💡 Lanjutkan untuk memproses %d grup yang bisa diproses saja.", len(allowed)))
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("▶️ Lanjutkan (%d grup)", len(allowed)), "preflight_continue"),
+			))
+		} else {
+			text.WriteString("\n❌ Tidak ada grup yang bisa diproses. Jadikan akun ini admin terlebih dahulu.")
+			if cleanup != nil {
+				cleanup()
+			}
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "preflight_cancel"),
+		))
+
+		msg := tgbotapi.NewMessage(chatID, text.String())
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+		telegramBot.Send(msg)
+	}()
+}
+
+// ContinueAdminPreflight menjalankan operasi yang tertunda hanya untuk grup yang bisa diproses
+func ContinueAdminPreflight(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	pending := takePendingPreflight(chatID)
+	if pending == nil {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Tidak ada proses yang menunggu konfirmasi.")
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("🚀 Melanjutkan %s untuk %d grup...", pending.Name, len(pending.Allowed)))
+	telegramBot.Send(editMsg)
+	go pending.Start(pending.Allowed)
+}
+
+// CancelAdminPreflight membatalkan operasi yang menunggu konfirmasi preflight
+func CancelAdminPreflight(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	takePendingPreflight(chatID).cleanup()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Proses dibatalkan.")
+	telegramBot.Send(editMsg)
+}
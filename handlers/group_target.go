@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"whatsapp-bot/utils"
+)
+
+// resolveGroupOrTagTarget mengubah input target (nama grup persis atau tag:key=value) menjadi target tersimpan dan labelnya
+// Dipakai roster admin, template sambutan, aturan moderasi dan pantau kata kunci
+func resolveGroupOrTagTarget(target string) (string, string, error) {
+	if selector, ok := strings.CutPrefix(target, utils.GroupTagPrefix); ok {
+		key, value, ok := strings.Cut(selector, "=")
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return "", "", fmt.Errorf("Format tag salah. Contoh: tag:kota=Jakarta")
+		}
+		groups, _ := utils.GetGroupsByTag(key, value)
+		if len(groups) == 0 {
+			return "", "", fmt.Errorf("Tidak ada grup dengan tag %s=%s. Atur tag via /tag terlebih dahulu.", key, value)
+		}
+		return utils.GroupTagPrefix + key + "=" + value, fmt.Sprintf("%s=%s (%d grup)", key, value, len(groups)), nil
+	}
+
+	groups, err := utils.SearchGroupsExact(target)
+	if err != nil || len(groups) == 0 {
+		return "", "", fmt.Errorf("Grup \"%s\" tidak ditemukan.", target)
+	}
+	if len(groups) > 1 {
+		return "", "", fmt.Errorf("Ditemukan %d grup untuk \"%s\". Gunakan nama grup yang persis, atau tag untuk beberapa grup.", len(groups), target)
+	}
+	for jid, name := range groups {
+		return jid, name, nil
+	}
+	return "", "", fmt.Errorf("Grup \"%s\" tidak ditemukan.", target)
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+)
+
+// Cache metadata grup dianggap basi setelah durasi ini dan diambil ulang dari WhatsApp
+const groupMetadataMaxAge = 6 * time.Hour
+
+// GroupTemplateData adalah data yang tersedia di template per grup
+// Contoh: {{.Name}}, {{.MemberCount}}, {{.CreatedAt}}, {{.InviteLink}}, {{.Tags.kota}}
+type GroupTemplateData struct {
+	Name        string
+	JID         string
+	No          int // Nomor urut grup dalam proses (mulai dari 1)
+	MemberCount int
+	CreatedAt   string // Tanggal grup dibuat (format 02-01-2006)
+	Topic       string // Deskripsi grup saat ini
+	InviteLink  string
+	Tags        map[string]string // Tag custom per grup (diatur via /tag)
+}
+
+// groupTemplateFuncs adalah fungsi tambahan yang bisa dipakai di template
+var groupTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"default": func(fallback string, value interface{}) string {
+		text := fmt.Sprint(value)
+		if value == nil || text == "" || text == "0" || text == "<no value>" {
+			return fallback
+		}
+		return text
+	},
+}
+
+// IsGroupTemplate mengecek apakah teks memakai sintaks template per grup ({{ ... }})
+func IsGroupTemplate(text string) bool {
+	return strings.Contains(text, "{{") && strings.Contains(text, "}}")
+}
+
+// ParseGroupTemplate mem-parse teks template per grup
+func ParseGroupTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("group").Funcs(groupTemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("template tidak valid: %v", err)
+	}
+	return tmpl, nil
+}
+
+// RenderGroupTemplate merender template untuk satu grup
+func RenderGroupTemplate(tmpl *template.Template, data *GroupTemplateData) (string, error) {
+	var result strings.Builder
+	if err := tmpl.Execute(&result, data); err != nil {
+		return "", fmt.Errorf("gagal render template untuk %s: %v", data.Name, err)
+	}
+	// missingkey=zero menghasilkan "<no value>" untuk tag yang tidak ada
+	return strings.TrimSpace(strings.ReplaceAll(result.String(), "<no value>", "")), nil
+}
+
+// LoadGroupTemplateData menyiapkan data template untuk grup dari cache metadata
+// Metadata yang belum ada/basi diambil dari WhatsApp (jika client tersedia) lalu disimpan ke cache
+// Invite link hanya diambil jika template memakainya (butuh bot admin)
+func LoadGroupTemplateData(client *whatsmeow.Client, group GroupLinkInfo, index int, templateText string) *GroupTemplateData {
+	data := &GroupTemplateData{
+		Name: group.Name,
+		JID:  group.JID,
+		No:   index + 1,
+		Tags: map[string]string{},
+	}
+
+	meta, err := utils.GetGroupMetadata(group.JID)
+	if err != nil {
+		utils.GetGrupLogger().Warn("GroupTemplate: Gagal baca cache metadata %s: %v", group.JID, err)
+	}
+
+	clientReady := client != nil && client.IsConnected()
+	needsInfo := strings.Contains(templateText, ".MemberCount") || strings.Contains(templateText, ".CreatedAt") || strings.Contains(templateText, ".Topic")
+	if needsInfo && clientReady && (meta == nil || meta.GroupCreated.IsZero() || time.Since(meta.UpdatedAt) > groupMetadataMaxAge) {
+		if fresh := fetchGroupMetadata(client, group.JID); fresh != nil {
+			if meta != nil {
+				fresh.InviteLink = meta.InviteLink
+			}
+			meta = fresh
+		}
+	}
+
+	if strings.Contains(templateText, ".InviteLink") && clientReady && (meta == nil || meta.InviteLink == "") {
+		if link := fetchGroupInviteLink(client, group.JID); link != "" {
+			if meta == nil {
+				meta = &utils.GroupMetadata{JID: group.JID}
+			}
+			meta.InviteLink = link
+		}
+	}
+
+	if meta != nil {
+		data.MemberCount = meta.MemberCount
+		data.Topic = meta.Topic
+		data.InviteLink = meta.InviteLink
+		if !meta.GroupCreated.IsZero() {
+			data.CreatedAt = meta.GroupCreated.Local().Format("02-01-2006")
+		}
+	}
+
+	if tags, err := utils.GetGroupTags(group.JID); err == nil {
+		data.Tags = tags
+	}
+
+	return data
+}
+
+// fetchGroupMetadata mengambil info grup dari WhatsApp dan menyimpannya ke cache
+func fetchGroupMetadata(client *whatsmeow.Client, groupJID string) *utils.GroupMetadata {
+	jid, err := parseJIDFromString(groupJID)
+	if err != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	info, err := client.GetGroupInfo(ctx, jid)
+	if err != nil || info == nil {
+		utils.GetGrupLogger().Warn("GroupTemplate: Gagal ambil info grup %s: %v", groupJID, err)
+		return nil
+	}
+
+	meta := &utils.GroupMetadata{
+		JID:          groupJID,
+		MemberCount:  len(info.Participants),
+		GroupCreated: info.GroupCreated,
+		Topic:        info.Topic,
+		UpdatedAt:    time.Now(),
+	}
+	if err := utils.SaveGroupMetadata(meta); err != nil {
+		utils.GetGrupLogger().Warn("GroupTemplate: Gagal simpan cache metadata %s: %v", groupJID, err)
+	}
+	return meta
+}
+
+// fetchGroupInviteLink mengambil link undangan grup dari WhatsApp dan menyimpannya ke cache
+func fetchGroupInviteLink(client *whatsmeow.Client, groupJID string) string {
+	jid, err := parseJIDFromString(groupJID)
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	link, err := client.GetGroupInviteLink(ctx, jid, false)
+	if err != nil {
+		return ""
+	}
+	utils.SaveGroupInviteLink(groupJID, link)
+	return link
+}
+
+// BuildGroupTemplatePreview merender template untuk beberapa grup pertama sebagai preview
+// Return error jika template tidak valid atau gagal dirender
+func BuildGroupTemplatePreview(client *whatsmeow.Client, groups []GroupLinkInfo, templateText string, limit int) (string, error) {
+	tmpl, err := ParseGroupTemplate(templateText)
+	if err != nil {
+		return "", err
+	}
+
+	var preview strings.Builder
+	for i, group := range groups {
+		if i >= limit {
+			preview.WriteString(fmt.Sprintf("... dan %d grup lainnya (dirender saat proses berjalan)\n", len(groups)-limit))
+			break
+		}
+		rendered, err := RenderGroupTemplate(tmpl, LoadGroupTemplateData(client, group, i, templateText))
+		if err != nil {
+			return "", err
+		}
+		preview.WriteString(fmt.Sprintf("📌 %s\n%s\n\n", group.Name, rendered))
+	}
+	return preview.String(), nil
+}
+
+// groupTemplateHelp adalah penjelasan singkat placeholder template per grup untuk ditampilkan ke user
+const groupTemplateHelp = "🧩 Template per grup (opsional):\n" +
+	"• {{.Name}} - nama grup\n" +
+	"• {{.MemberCount}} - jumlah anggota\n" +
+	"• {{.CreatedAt}} - tanggal grup dibuat\n" +
+	"• {{.InviteLink}} - link undangan (bot harus admin)\n" +
+	"• {{.No}} - nomor urut\n" +
+	"• {{.Tags.kota}} - tag custom (atur via /tag)\n" +
+	"• {{if .Tags.kota}}...{{else}}...{{end}} - kondisi"
+
+// HandleGroupTagCommand memproses command /tag untuk mengatur tag custom per grup
+// Format: /tag Nama Grup | kota=Jakarta; admin=Budi (nilai kosong menghapus tag)
+// Tanpa "|" command menampilkan tag grup yang tersimpan
+func HandleGroupTagCommand(args string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		msg := tgbotapi.NewMessage(chatID, "🏷️ TAG GRUP\n\n"+
+			"Atur tag:\n/tag Nama Grup | kota=Jakarta; admin=Budi\n\n"+
+			"Lihat tag:\n/tag Nama Grup\n\n"+
+			"Hapus tag: kosongkan nilainya, contoh kota=\n\n"+
+			"Tag dipakai di template sebagai {{.Tags.kota}}")
+		telegramBot.Send(msg)
+		return
+	}
+
+	groupName, assignments, hasAssignments := strings.Cut(args, "|")
+	groupName = strings.TrimSpace(groupName)
+
+	matches, err := utils.SearchGroupsExact(groupName)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal mencari grup: %v", err)))
+		return
+	}
+	if len(matches) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Grup \"%s\" tidak ditemukan.\n\n💡 Pastikan daftar grup sudah disinkronkan lewat menu Lihat Grup.", groupName)))
+		return
+	}
+	if len(matches) > 1 {
+		var names []string
+		for _, group := range utils.SortGroupsNaturally(matches) {
+			names = append(names, "• "+group.Name)
+		}
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("⚠️ Ditemukan %d grup yang cocok, tulis nama grup lebih lengkap:\n\n%s", len(matches), strings.Join(names, "\n"))))
+		return
+	}
+
+	var groupJID, matchedName string
+	for jid, name := range matches {
+		groupJID, matchedName = jid, name
+	}
+
+	if hasAssignments {
+		updated := 0
+		for _, pair := range strings.Split(assignments, ";") {
+			key, value, ok := strings.Cut(pair, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				continue
+			}
+			if err := utils.SetGroupTag(groupJID, key, strings.TrimSpace(value)); err != nil {
+				telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal menyimpan tag %s: %v", key, err)))
+				return
+			}
+			updated++
+		}
+		if updated == 0 {
+			telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Format tag tidak valid. Contoh: /tag Nama Grup | kota=Jakarta; admin=Budi"))
+			return
+		}
+	}
+
+	tags, err := utils.GetGroupTags(groupJID)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal membaca tag: %v", err)))
+		return
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🏷️ TAG GRUP: %s\n\n", matchedName))
+	if len(tags) == 0 {
+		result.WriteString("Belum ada tag.")
+	} else {
+		keys := make([]string, 0, len(tags))
+		for key := range tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result.WriteString(fmt.Sprintf("• %s = %s\n", key, tags[key]))
+		}
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, result.String()))
+}
@@ -214,6 +214,9 @@ func fetchAllGroups(client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, chat
 		if err == nil && len(joinedGroups) > 0 {
 			logger.Info("Berhasil mengambil %d grup dari GetJoinedGroups()", len(joinedGroups))
 
+			// Perbarui keanggotaan komunitas agar daftar grup bisa menandai komunitas & subgrup
+			cacheGroupCommunities(joinedGroups)
+
 			// Convert dari whatsmeow types.GroupInfo ke GroupInfo kita
 			for _, group := range joinedGroups {
 				if group != nil {
@@ -565,6 +568,13 @@ func sendGroupListInChunks(telegramBot *tgbotapi.BotAPI, chatID int64, groups []
 
 	messageCount := 1
 
+	// Tandai komunitas, subgrup dan grup pengumuman komunitas
+	communities, _ := utils.GetGroupCommunities()
+	names := make(map[string]string, len(groups))
+	for _, group := range groups {
+		names[group.JID.String()] = group.Name
+	}
+
 	for _, group := range groups {
 		// Format nama grup (escape markdown jika perlu)
 		// Note: grup sudah difilter oleh filterValidGroups sebelumnya
@@ -572,6 +582,14 @@ func sendGroupListInChunks(telegramBot *tgbotapi.BotAPI, chatID int64, groups []
 
 		// Format baris grup tanpa nomor urut (hanya nama grup)
 		line := fmt.Sprintf("%s\n", groupName)
+		switch role, community := communityGroupLabel(group.JID.String(), communities, names); role {
+		case "komunitas":
+			line = fmt.Sprintf("🏘️ %s _(komunitas)_\n", groupName)
+		case "pengumuman":
+			line = fmt.Sprintf("📢 %s _(pengumuman %s)_\n", groupName, escapeMarkdown(community))
+		case "subgrup":
+			line = fmt.Sprintf("%s _(🏘️ %s)_\n", groupName, escapeMarkdown(community))
+		}
 
 		// Cek apakah menambahkan baris ini akan melebihi batas
 		if currentMessageLength+len(line) > MaxMessageLength && currentMessageLength > len(header) {
@@ -681,6 +699,7 @@ func showGroupMenu(telegramBot *tgbotapi.BotAPI, chatID int64, client *whatsmeow
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📝 Atur Deskripsi", "change_description_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Ganti Nama", "rename_group_menu"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📢 Atur Pesan", "change_logging_menu"),
@@ -698,9 +717,24 @@ func showGroupMenu(telegramBot *tgbotapi.BotAPI, chatID int64, client *whatsmeow
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚙️ Atur Semua Pengaturan", "change_all_settings_menu"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📥 Import CSV per Grup", "csv_import_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Buat Grup Otomatis", "create_group_menu"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏘️ Komunitas", "community_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("📣 Saluran", "channel_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(WelcomeLabel(client), "welcome_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🛡️ Moderasi", "moderation_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⛔ Blocklist Nomor", "blocklist_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🔔 Pantau Kata Kunci", "watch_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚪 Join Grup Otomatis", "join_group_menu"),
 		),
@@ -741,12 +775,20 @@ func ShowGroupManagementMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, mes
 🔗 **Ambil Link** - Get link undangan grup
 🖼️ **Ganti Foto** - Ubah foto profil grup
 📝 **Atur Deskripsi** - Ubah deskripsi grup
+✏️ **Ganti Nama** - Ganti nama grup massal (template, penomoran, regex)
 📢 **Atur Pesan** - Aktifkan/nonaktifkan pesan
 👥 **Atur Tambah Anggota** - Atur izin tambah anggota
 ✅ **Atur Persetujuan** - Atur approval anggota baru
 ⏱️ **Atur Pesan Sementara** - Atur durasi pesan sementara
 🔧 **Atur Edit Grup** - Atur izin edit pengaturan grup
 ⚙️ **Atur Semua Pengaturan** - Atur semua pengaturan sekaligus
+📥 **Import CSV per Grup** - Nama, deskripsi & pengaturan berbeda tiap grup dari CSV
+🏘️ **Komunitas** - Buat komunitas, tautkan/lepas grup, lihat subgrup
+📣 **Saluran** - Kelola saluran (channel): buat, ubah info & foto, ambil link
+👋 **Pesan Sambutan** - Sambut anggota baru dengan mention & peraturan grup
+🛡️ **Moderasi** - Tindak link undangan, forward berantai, flood & kata terlarang otomatis
+⛔ **Blocklist Nomor** - Keluarkan otomatis nomor terlarang dari grup yang dikelola
+🔔 **Pantau Kata Kunci** - Alert ke Telegram saat kata kunci / regex muncul di grup
 📥 **Export** - Download daftar ke file
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -772,6 +814,7 @@ func ShowGroupManagementMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, mes
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📝 Atur Deskripsi", "change_description_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Ganti Nama", "rename_group_menu"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📢 Atur Pesan", "change_logging_menu"),
@@ -789,9 +832,24 @@ func ShowGroupManagementMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, mes
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚙️ Atur Semua Pengaturan", "change_all_settings_menu"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📥 Import CSV per Grup", "csv_import_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Buat Grup Otomatis", "create_group_menu"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏘️ Komunitas", "community_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("📣 Saluran", "channel_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(WelcomeLabel(client), "welcome_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🛡️ Moderasi", "moderation_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⛔ Blocklist Nomor", "blocklist_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🔔 Pantau Kata Kunci", "watch_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚪 Join Grup Otomatis", "join_group_menu"),
 		),
@@ -809,7 +809,10 @@ Masukkan berapa detik delay antar nomor saat menambahkan member.
 		}
 
 		// Process in goroutine
-		go ProcessAddMember(state, chatID, client, telegramBot)
+		StartWithAdminPreflight(chatID, "Add Member", state.SelectedGroups, requireAddMember, client, telegramBot, func(allowed []GroupLinkInfo) {
+			state.SelectedGroups = allowed
+			ProcessAddMember(state, chatID, client, telegramBot)
+		})
 
 		// Clear state after processing starts
 		// State will be cleared after processing completes
@@ -864,7 +867,10 @@ func HandleNumberDelayInputForAddMember(input string, chatID int64, telegramBot
 	}
 
 	// Process in goroutine
-	go ProcessAddMember(state, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Add Member", state.SelectedGroups, requireAddMember, client, telegramBot, func(allowed []GroupLinkInfo) {
+		state.SelectedGroups = allowed
+		ProcessAddMember(state, chatID, client, telegramBot)
+	})
 }
 
 // ProcessAddMember memproses penambahan member ke grup
@@ -921,7 +927,15 @@ func ProcessAddMember(state *AddMemberState, chatID int64, client *whatsmeow.Cli
 	}
 
 	// Process each group
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Tambah Anggota", groupJobItems(state.SelectedGroups))
+	defer job.Finish()
+
 	for i, group := range state.SelectedGroups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// Parse group JID
 		groupJID, err := parseJIDFromString(group.JID)
 		if err != nil {
@@ -945,7 +959,7 @@ func ProcessAddMember(state *AddMemberState, chatID int64, client *whatsmeow.Cli
 					goto cleanup // Exit outer loop
 				}
 
-				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 				results, err := validClient.UpdateGroupParticipants(ctx, groupJID, []types.JID{jid}, whatsmeow.ParticipantChangeAdd)
 				cancel()
 
@@ -977,7 +991,11 @@ func ProcessAddMember(state *AddMemberState, chatID int64, client *whatsmeow.Cli
 
 				// Delay between numbers (except last one)
 				if j < len(participantJIDs)-1 {
-					time.Sleep(time.Duration(state.NumberDelaySeconds) * time.Second)
+					if !job.Sleep(time.Duration(state.NumberDelaySeconds) * time.Second) {
+						// Bot shutdown: checkpoint mulai dari grup ini (nomor yang sudah masuk aman diulang)
+						job.Next(i)
+						goto cleanup
+					}
 				}
 			}
 		} else {
@@ -992,7 +1010,7 @@ func ProcessAddMember(state *AddMemberState, chatID int64, client *whatsmeow.Cli
 				break
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 60*time.Second)
 			results, err := validClient.UpdateGroupParticipants(ctx, groupJID, participantJIDs, whatsmeow.ParticipantChangeAdd)
 			cancel()
 
@@ -1074,7 +1092,7 @@ func ProcessAddMember(state *AddMemberState, chatID int64, client *whatsmeow.Cli
 
 		// Delay between groups (except last one)
 		if i < len(state.SelectedGroups)-1 {
-			time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 		}
 	}
 
@@ -1092,7 +1110,7 @@ cleanup:
 	}
 
 	// Send final summary
-	summaryMsg := fmt.Sprintf(`✅ **PROSES SELESAI**
+	summaryMsg := job.PausedNote() + fmt.Sprintf(`✅ **PROSES SELESAI**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -46,6 +46,7 @@ Fitur ini akan mengangkat nomor menjadi admin di grup WhatsApp secara otomatis.
 • Nomor harus sudah menjadi anggota grup
 • Delay membantu menghindari rate limit WhatsApp (jeda antar grup)
 • Proses mungkin memakan waktu untuk banyak grup
+• 📋 Roster Admin: simpan daftar admin tetap per grup/tag, bot menyinkronkan selisihnya
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -58,6 +59,9 @@ Fitur ini akan mengangkat nomor menjadi admin di grup WhatsApp secara otomatis.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Mulai", "start_admin_process"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Roster Admin (Sinkron)", "admin_roster_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
 		),
@@ -86,6 +90,7 @@ Fitur ini akan mengangkat nomor menjadi admin di grup WhatsApp secara otomatis.
 • Nomor harus sudah menjadi anggota grup
 • Delay membantu menghindari rate limit WhatsApp (jeda antar grup)
 • Proses mungkin memakan waktu untuk banyak grup
+• 📋 Roster Admin: simpan daftar admin tetap per grup/tag, bot menyinkronkan selisihnya
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -95,6 +100,9 @@ Fitur ini akan mengangkat nomor menjadi admin di grup WhatsApp secara otomatis.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Mulai", "start_admin_process"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Roster Admin (Sinkron)", "admin_roster_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
 		),
@@ -627,7 +635,10 @@ func HandlePhoneInputForAdmin(phoneInput string, chatID int64, telegramBot *tgbo
 		client = GetWhatsAppClient() // Fallback
 	}
 	// Process in goroutine
-	go ProcessAdminUnadmin(state, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, actionText, state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		state.SelectedGroups = allowed
+		ProcessAdminUnadmin(state, chatID, client, telegramBot)
+	})
 }
 
 // normalizePhoneForComparison menormalisasi nomor telepon untuk perbandingan
@@ -707,7 +718,15 @@ func ProcessAdminUnadmin(state *AdminState, chatID int64, client *whatsmeow.Clie
 		return
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Admin/Unadmin", groupJobItems(state.SelectedGroups))
+	defer job.Finish()
+
 	for i, group := range state.SelectedGroups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// HIGH FIX: Ambil active client di setiap iterasi (admin/unadmin bisa pakai WaClient global!)
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessAdminUnadmin", i, totalGroups)
 		if shouldStop {
@@ -731,7 +750,7 @@ func ProcessAdminUnadmin(state *AdminState, chatID int64, client *whatsmeow.Clie
 
 		// Process ALL phone numbers for this group SIMULTANEOUSLY (batch)
 		// Delay hanya digunakan antar grup, bukan antar nomor
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 		results, err := validClient.UpdateGroupParticipants(ctx, groupJID, participantJIDs, action)
 		cancel()
 
@@ -747,7 +766,7 @@ func ProcessAdminUnadmin(state *AdminState, chatID int64, client *whatsmeow.Clie
 			time.Sleep(500 * time.Millisecond)
 
 			// Verifikasi real-time: Ambil info grup setelah operasi untuk memastikan status admin aktual
-			verifyCtx, verifyCancel := context.WithTimeout(context.Background(), 15*time.Second)
+			verifyCtx, verifyCancel := context.WithTimeout(job.Context(), 15*time.Second)
 			groupInfo, verifyErr := validClient.GetGroupInfo(verifyCtx, groupJID)
 			verifyCancel()
 
@@ -937,7 +956,7 @@ func ProcessAdminUnadmin(state *AdminState, chatID int64, client *whatsmeow.Clie
 
 		// Delay between groups (except last group)
 		if i < totalGroups-1 && state.DelaySeconds > 0 {
-			time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 		}
 	}
 
@@ -949,7 +968,7 @@ func ProcessAdminUnadmin(state *AdminState, chatID int64, client *whatsmeow.Clie
 
 	// Final summary
 	totalOps := totalGroups * totalPhones
-	summaryMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	summaryMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 📊 **RINGKASAN**
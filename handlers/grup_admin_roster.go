@@ -0,0 +1,639 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// AdminRosterState menyimpan input dan hasil preview sinkronisasi roster admin per chat
+type AdminRosterState struct {
+	WaitingForInput bool
+	Roster          *utils.AdminRoster // Roster yang sedang di-preview
+	Plans           []adminRosterPlan
+}
+
+// adminRosterPlan adalah selisih roster dengan admin aktual di satu grup
+type adminRosterPlan struct {
+	Group   GroupLinkInfo
+	Promote []rosterParticipant // Ada di roster, member grup, belum admin
+	Demote  []rosterParticipant // Admin yang tidak ada di roster (hanya jika DemoteUnlisted)
+	Missing []string            // Nomor roster yang belum menjadi member grup
+	Skipped []string            // Admin yang tidak diturunkan: owner grup / akun ini / nomor tidak diketahui
+	Error   string              // Grup tidak bisa diproses (gagal ambil info / bukan admin)
+}
+
+// rosterParticipant adalah peserta grup beserta label nomornya untuk laporan
+type rosterParticipant struct {
+	JID   types.JID
+	Label string
+}
+
+var adminRosterStates = make(map[int64]*AdminRosterState)
+
+const (
+	// Jeda antar request GetGroupInfo saat menghitung preview
+	adminRosterPreviewDelay = time.Second
+	// Jeda antar grup saat menerapkan roster
+	adminRosterApplyDelay = 2 * time.Second
+	// Maksimal grup yang dirinci di preview / laporan
+	adminRosterDisplayLimit = 15
+)
+
+// ShowAdminRosterMenuEdit menampilkan daftar roster admin akun beserta tombol preview/opsi
+func ShowAdminRosterMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildAdminRosterMenu()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildAdminRosterMenu membuat teks daftar roster (plain text karena nama grup bebas)
+func buildAdminRosterMenu() (string, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var text strings.Builder
+	text.WriteString("📋 ROSTER ADMIN\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Tentukan daftar admin yang diinginkan untuk satu grup atau koleksi grup (tag). ")
+	text.WriteString("Bot menghitung selisih dengan admin aktual: mengangkat yang belum admin dan (opsional) menurunkan admin yang tidak terdaftar.\n\n")
+	text.WriteString("🛡️ Owner grup dan akun bot tidak pernah diturunkan.\n")
+
+	rosters, err := utils.GetAdminRosters(clientAccountID(GetWhatsAppClient()))
+	if err != nil {
+		text.WriteString(fmt.Sprintf("\n❌ Gagal memuat roster: %v\n", err))
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(rosters) == 0 && err == nil {
+		text.WriteString("Belum ada roster. Klik ➕ untuk menambahkan.")
+	}
+
+	for i, roster := range rosters {
+		demoteText := "OFF"
+		if roster.DemoteUnlisted {
+			demoteText = "ON"
+		}
+		text.WriteString(fmt.Sprintf("%d. %s\n", i+1, adminRosterTargetLabel(&roster)))
+		text.WriteString(fmt.Sprintf("   👑 %d admin • ⬇️ Turunkan lainnya: %s\n", len(roster.Phones), demoteText))
+
+		id := strconv.FormatInt(roster.ID, 10)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🔍 Preview %d", i+1), "roster_preview_"+id),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("⬇️ %s", demoteText), "roster_demote_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("🗑️", "roster_delete_"+id),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah / Ubah Roster", "roster_add"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "admin_menu"),
+		),
+	)
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// adminRosterTargetLabel memformat target roster untuk ditampilkan
+func adminRosterTargetLabel(roster *utils.AdminRoster) string {
+	if roster.IsTagTarget() {
+		return "🏷️ " + roster.TargetName
+	}
+	if roster.TargetName != "" {
+		return "👥 " + roster.TargetName
+	}
+	return "👥 " + roster.Target
+}
+
+// StartAdminRosterInput meminta input roster baru (target + daftar nomor admin)
+func StartAdminRosterInput(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	adminRosterStates[chatID] = &AdminRosterState{WaitingForInput: true}
+
+	promptMsg := `📋 **INPUT ROSTER ADMIN**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Format:**
+Baris 1: nama grup (persis) atau koleksi tag
+Baris berikutnya: nomor admin (satu per baris)
+
+**Contoh grup:**
+` + "`Grup Keluarga\n628123456789\n628987654321`" + `
+
+**Contoh koleksi (tag):**
+` + "`tag:kota=Jakarta\n628123456789`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 **Tips:**
+• Tag diatur via /tag
+• Target yang sama akan ditimpa dengan roster baru
+• Nomor bot tidak perlu dimasukkan
+
+⏳ Menunggu input...`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "admin_roster_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleAdminRosterInput menyimpan roster dari input user lalu menampilkan daftar roster
+func HandleAdminRosterInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := adminRosterStates[chatID]
+	if state == nil || !state.WaitingForInput {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	target := strings.TrimSpace(lines[0])
+	if target == "" || len(lines) < 2 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Format salah. Baris 1 berisi nama grup / tag:key=value, baris berikutnya nomor admin."))
+		return
+	}
+
+	roster := &utils.AdminRoster{AccountID: clientAccountID(client)}
+	var err error
+	roster.Target, roster.TargetName, err = resolveGroupOrTagTarget(target)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+
+	seen := make(map[string]bool)
+	var invalid []string
+	for _, line := range lines[1:] {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		phone := normalizePhoneForComparison(line)
+		if phone == "" {
+			invalid = append(invalid, line)
+			continue
+		}
+		if !seen[phone] {
+			seen[phone] = true
+			roster.Phones = append(roster.Phones, phone)
+		}
+	}
+	if len(roster.Phones) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tidak ada nomor admin yang valid!"))
+		return
+	}
+
+	// Pertahankan opsi demote jika roster untuk target ini sudah ada
+	if existing, err := utils.GetAdminRosters(roster.AccountID); err == nil {
+		for _, r := range existing {
+			if r.Target == roster.Target {
+				roster.DemoteUnlisted = r.DemoteUnlisted
+			}
+		}
+	}
+
+	if err := utils.SaveAdminRoster(roster); err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan roster admin"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+	delete(adminRosterStates, chatID)
+
+	result := fmt.Sprintf("✅ Roster disimpan: %s\n👑 %d nomor admin", adminRosterTargetLabel(roster), len(roster.Phones))
+	if len(invalid) > 0 {
+		result += fmt.Sprintf("\n⚠️ %d nomor tidak valid diabaikan: %s", len(invalid), strings.Join(invalid, ", "))
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, result))
+
+	text, keyboard := buildAdminRosterMenu()
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// ToggleAdminRosterDemote mengubah opsi turunkan admin yang tidak terdaftar
+func ToggleAdminRosterDemote(rosterID int64, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	accountID := clientAccountID(GetWhatsAppClient())
+	roster, err := utils.GetAdminRoster(accountID, rosterID)
+	if err == nil && roster != nil {
+		utils.SetAdminRosterDemote(accountID, rosterID, !roster.DemoteUnlisted)
+	}
+	ShowAdminRosterMenuEdit(telegramBot, chatID, messageID)
+}
+
+// DeleteAdminRosterEntry menghapus roster lalu menampilkan ulang daftar
+func DeleteAdminRosterEntry(rosterID int64, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	utils.DeleteAdminRoster(clientAccountID(GetWhatsAppClient()), rosterID)
+	ShowAdminRosterMenuEdit(telegramBot, chatID, messageID)
+}
+
+// resolveAdminRosterGroups mengambil grup yang menjadi target roster
+func resolveAdminRosterGroups(roster *utils.AdminRoster) []GroupLinkInfo {
+	groups := make(map[string]string)
+	if key, value, ok := roster.TagSelector(); ok {
+		groups, _ = utils.GetGroupsByTag(key, value)
+	} else if !roster.IsTagTarget() {
+		groups[roster.Target] = roster.TargetName
+	}
+
+	var result []GroupLinkInfo
+	for jid, name := range groups {
+		result = append(result, GroupLinkInfo{JID: jid, Name: name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Name) < strings.ToLower(result[j].Name)
+	})
+	return result
+}
+
+// participantPhone mendapatkan nomor peserta grup (dari JID, field PhoneNumber, atau mapping LID)
+func participantPhone(client *whatsmeow.Client, participant types.GroupParticipant) string {
+	if participant.JID.Server == types.DefaultUserServer {
+		return participant.JID.User
+	}
+	if !participant.PhoneNumber.IsEmpty() {
+		return participant.PhoneNumber.User
+	}
+	return requesterPhone(client, participant.JID)
+}
+
+// computeAdminRosterPlan menghitung selisih roster dengan peserta grup saat ini
+func computeAdminRosterPlan(client *whatsmeow.Client, roster *utils.AdminRoster, group GroupLinkInfo) adminRosterPlan {
+	plan := adminRosterPlan{Group: group}
+
+	jid, err := parseJIDFromString(group.JID)
+	if err != nil {
+		plan.Error = "JID tidak valid"
+		return plan
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	info, err := client.GetGroupInfo(ctx, jid)
+	cancel()
+	if err != nil || info == nil {
+		plan.Error = fmt.Sprintf("gagal ambil info grup: %v", err)
+		return plan
+	}
+	if info.Name != "" {
+		plan.Group.Name = info.Name
+	}
+
+	wanted := make(map[string]bool)
+	for _, phone := range roster.Phones {
+		wanted[phone] = true
+	}
+
+	isBotAdmin := false
+	found := make(map[string]bool)
+	for _, participant := range info.Participants {
+		own := isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber)
+		if own {
+			isBotAdmin = participant.IsAdmin || participant.IsSuperAdmin
+		}
+
+		phone := participantPhone(client, participant)
+		normalized := normalizePhoneForComparison(phone)
+		if normalized == "" {
+			normalized = phone
+		}
+		label := requesterLabel(client, participant.JID)
+		if phone != "" {
+			label = "+" + phone
+		}
+
+		if normalized != "" && wanted[normalized] {
+			found[normalized] = true
+			if !participant.IsAdmin && !participant.IsSuperAdmin && !own {
+				plan.Promote = append(plan.Promote, rosterParticipant{JID: participant.JID, Label: label})
+			}
+			continue
+		}
+
+		if !roster.DemoteUnlisted || (!participant.IsAdmin && !participant.IsSuperAdmin) {
+			continue
+		}
+		// Owner grup dan akun ini tidak pernah diturunkan
+		if own || participant.IsSuperAdmin || participant.JID == info.OwnerJID {
+			plan.Skipped = append(plan.Skipped, label)
+			continue
+		}
+		// Nomor tidak bisa dipastikan (LID tanpa mapping): bisa jadi ada di roster, jangan diturunkan
+		if phone == "" {
+			plan.Skipped = append(plan.Skipped, label+" (nomor tidak diketahui)")
+			continue
+		}
+		plan.Demote = append(plan.Demote, rosterParticipant{JID: participant.JID, Label: label})
+	}
+
+	for _, phone := range roster.Phones {
+		if !found[phone] {
+			plan.Missing = append(plan.Missing, "+"+phone)
+		}
+	}
+
+	if !isBotAdmin {
+		plan.Error = "akun bot bukan admin grup"
+	}
+	return plan
+}
+
+// PreviewAdminRoster menghitung rencana sinkronisasi roster lalu menampilkan preview sebelum diterapkan
+func PreviewAdminRoster(rosterID int64, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	roster, err := utils.GetAdminRoster(clientAccountID(client), rosterID)
+	if err != nil || roster == nil {
+		ShowAdminRosterMenuEdit(telegramBot, chatID, messageID)
+		return
+	}
+
+	groups := resolveAdminRosterGroups(roster)
+	if len(groups) == 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Tidak ada grup untuk roster ini.")
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "admin_roster_menu")),
+		)
+		editMsg.ReplyMarkup = &keyboard
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	state := &AdminRosterState{Roster: roster}
+	for i, group := range groups {
+		if !client.IsConnected() {
+			break
+		}
+		if i > 0 {
+			time.Sleep(adminRosterPreviewDelay)
+		}
+		if i%5 == 0 {
+			loading := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("🔍 Menghitung selisih roster... (%d/%d grup)", i+1, len(groups)))
+			telegramBot.Send(loading)
+		}
+		state.Plans = append(state.Plans, computeAdminRosterPlan(client, roster, group))
+	}
+	adminRosterStates[chatID] = state
+
+	totalPromote, totalDemote := 0, 0
+	for _, plan := range state.Plans {
+		if plan.Error == "" {
+			totalPromote += len(plan.Promote)
+			totalDemote += len(plan.Demote)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString("🔍 PREVIEW ROSTER ADMIN\n\n")
+	text.WriteString(adminRosterTargetLabel(roster) + "\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("📋 Grup: %d\n", len(state.Plans)))
+	text.WriteString(fmt.Sprintf("⬆️ Akan diangkat: %d\n", totalPromote))
+	text.WriteString(fmt.Sprintf("⬇️ Akan diturunkan: %d\n", totalDemote))
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	writeAdminRosterPlans(&text, state.Plans, false)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if totalPromote+totalDemote > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Terapkan", "roster_apply"),
+		))
+	} else {
+		text.WriteString("\n✅ Semua grup sudah sesuai roster.")
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "admin_roster_menu"),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, truncateRosterText(text.String()))
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// writeAdminRosterPlans menulis rincian per grup (preview atau laporan hasil)
+func writeAdminRosterPlans(text *strings.Builder, plans []adminRosterPlan, applied bool) {
+	promoteTitle, demoteTitle := "⬆️ Angkat", "⬇️ Turunkan"
+	if applied {
+		promoteTitle, demoteTitle = "⬆️ Diangkat", "⬇️ Diturunkan"
+	}
+
+	shown := 0
+	for _, plan := range plans {
+		if plan.Error == "" && len(plan.Promote)+len(plan.Demote)+len(plan.Missing) == 0 {
+			continue
+		}
+		if shown >= adminRosterDisplayLimit {
+			text.WriteString(fmt.Sprintf("\n... dan grup lainnya (%d grup total)\n", len(plans)))
+			break
+		}
+		shown++
+
+		text.WriteString(fmt.Sprintf("\n👥 %s\n", plan.Group.Name))
+		if plan.Error != "" {
+			text.WriteString(fmt.Sprintf("   ❌ %s\n", plan.Error))
+			continue
+		}
+		if len(plan.Promote) > 0 {
+			text.WriteString(fmt.Sprintf("   %s: %s\n", promoteTitle, rosterLabels(plan.Promote)))
+		}
+		if len(plan.Demote) > 0 {
+			text.WriteString(fmt.Sprintf("   %s: %s\n", demoteTitle, rosterLabels(plan.Demote)))
+		}
+		if len(plan.Missing) > 0 {
+			text.WriteString(fmt.Sprintf("   ⚠️ Belum member: %s\n", strings.Join(plan.Missing, ", ")))
+		}
+		if len(plan.Skipped) > 0 {
+			text.WriteString(fmt.Sprintf("   🛡️ Dilindungi: %s\n", strings.Join(plan.Skipped, ", ")))
+		}
+	}
+}
+
+// rosterLabels menggabungkan label peserta untuk ditampilkan
+func rosterLabels(participants []rosterParticipant) string {
+	labels := make([]string, len(participants))
+	for i, participant := range participants {
+		labels[i] = participant.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// truncateRosterText memotong teks agar tidak melebihi batas pesan Telegram
+// Potongan dimundurkan ke awal karakter agar emoji/huruf multi-byte tidak terbelah
+func truncateRosterText(text string) string {
+	const maxLen = 4000
+	if len(text) <= maxLen {
+		return text
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "\n..."
+}
+
+// ApplyAdminRoster menerapkan rencana preview: angkat admin yang kurang lalu turunkan yang tidak terdaftar
+func ApplyAdminRoster(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := adminRosterStates[chatID]
+	if state == nil || state.Roster == nil || len(state.Plans) == 0 {
+		ShowAdminRosterMenuEdit(telegramBot, chatID, messageID)
+		return
+	}
+	delete(adminRosterStates, chatID)
+
+	var targets []adminRosterPlan
+	for _, plan := range state.Plans {
+		if plan.Error == "" && len(plan.Promote)+len(plan.Demote) > 0 {
+			targets = append(targets, plan)
+		}
+	}
+
+	groups := make([]GroupLinkInfo, len(targets))
+	for i, plan := range targets {
+		groups[i] = plan.Group
+	}
+
+	job := StartGroupJob(chatID, "Roster Admin", groupJobItems(groups))
+	defer job.Finish()
+
+	var report []adminRosterPlan
+	promoted, demoted, failed := 0, 0, 0
+	var failures []string
+
+	for i, plan := range targets {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ApplyAdminRoster", i, len(targets))
+		if shouldStop {
+			failures = append(failures, fmt.Sprintf("⚠️ Client terputus pada grup %d/%d", i+1, len(targets)))
+			break
+		}
+
+		progress := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("⏳ Menerapkan roster admin...\n\n%s\n📊 %d/%d grup\n👥 %s",
+			generateProgressBar((i*100)/len(targets)), i+1, len(targets), plan.Group.Name))
+		telegramBot.Send(progress)
+
+		groupJID, _ := parseJIDFromString(plan.Group.JID)
+		result := adminRosterPlan{Group: plan.Group, Missing: plan.Missing, Skipped: plan.Skipped}
+
+		ok, errs := applyRosterChange(job.Context(), validClient, groupJID, plan.Promote, whatsmeow.ParticipantChangePromote)
+		result.Promote = ok
+		promoted += len(ok)
+		for _, e := range errs {
+			failures = append(failures, fmt.Sprintf("❌ %s - angkat %s", plan.Group.Name, e))
+		}
+
+		ok, errs = applyRosterChange(job.Context(), validClient, groupJID, plan.Demote, whatsmeow.ParticipantChangeDemote)
+		result.Demote = ok
+		demoted += len(ok)
+		for _, e := range errs {
+			failures = append(failures, fmt.Sprintf("❌ %s - turunkan %s", plan.Group.Name, e))
+		}
+
+		failed = len(failures)
+		report = append(report, result)
+
+		if i < len(targets)-1 {
+			job.Sleep(adminRosterApplyDelay)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString(job.PausedNote())
+	text.WriteString("🎉 ROSTER ADMIN DITERAPKAN\n\n")
+	text.WriteString(adminRosterTargetLabel(state.Roster) + "\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("📋 Grup diproses: %d\n", len(report)))
+	text.WriteString(fmt.Sprintf("⬆️ Diangkat: %d\n", promoted))
+	text.WriteString(fmt.Sprintf("⬇️ Diturunkan: %d\n", demoted))
+	text.WriteString(fmt.Sprintf("❌ Gagal: %d\n", failed))
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	writeAdminRosterPlans(&text, report, true)
+	if len(failures) > 0 {
+		text.WriteString("\n❌ Gagal:\n")
+		for i, failure := range failures {
+			if i >= adminRosterDisplayLimit {
+				text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(failures)-adminRosterDisplayLimit))
+				break
+			}
+			text.WriteString(failure + "\n")
+		}
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Roster Admin", "admin_roster_menu"),
+		),
+	)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, truncateRosterText(text.String()))
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+
+	utils.LogActivity("admin_roster", fmt.Sprintf("Roster %s: %d diangkat, %d diturunkan, %d gagal", state.Roster.TargetName, promoted, demoted, failed), chatID)
+}
+
+// applyRosterChange menjalankan promote/demote satu grup. Return peserta yang berhasil dan daftar kegagalan
+func applyRosterChange(ctx context.Context, client *whatsmeow.Client, groupJID types.JID, participants []rosterParticipant, action whatsmeow.ParticipantChange) ([]rosterParticipant, []string) {
+	if len(participants) == 0 {
+		return nil, nil
+	}
+
+	jids := make([]types.JID, len(participants))
+	for i, participant := range participants {
+		jids[i] = participant.JID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	results, err := client.UpdateGroupParticipants(ctx, groupJID, jids, action)
+	cancel()
+	if err != nil {
+		return nil, []string{fmt.Sprintf("%s (%v)", rosterLabels(participants), err)}
+	}
+
+	errorCodes := make(map[string]int)
+	for _, result := range results {
+		errorCodes[result.JID.User] = result.Error
+		if !result.PhoneNumber.IsEmpty() {
+			errorCodes[result.PhoneNumber.User] = result.Error
+		}
+	}
+
+	var succeeded []rosterParticipant
+	var failures []string
+	for _, participant := range participants {
+		if code := errorCodes[participant.JID.User]; code != 0 {
+			failures = append(failures, fmt.Sprintf("%s (error code: %d)", participant.Label, code))
+			continue
+		}
+		succeeded = append(succeeded, participant)
+	}
+	return succeeded, failures
+}
+
+// IsWaitingForAdminRosterInput mengecek apakah user sedang mengisi roster admin
+func IsWaitingForAdminRosterInput(chatID int64) bool {
+	state := adminRosterStates[chatID]
+	return state != nil && state.WaitingForInput
+}
+
+// CancelAdminRosterInput membatalkan input roster admin
+func CancelAdminRosterInput(chatID int64) {
+	delete(adminRosterStates, chatID)
+}
@@ -0,0 +1,521 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// BlocklistState menyimpan input nomor dan hasil sweep blocklist per chat
+type BlocklistState struct {
+	WaitingForAdd    bool
+	WaitingForRemove bool
+	Plans            []blocklistSweepPlan // Hasil scan sweep yang menunggu konfirmasi
+}
+
+// blocklistSweepPlan adalah peserta blocklist yang ditemukan di satu grup
+type blocklistSweepPlan struct {
+	Group   GroupLinkInfo
+	Members []rosterParticipant
+	Skipped []string // Owner grup tidak bisa dikeluarkan
+}
+
+var blocklistStates = make(map[int64]*BlocklistState)
+
+const (
+	// Maksimal nomor blocklist yang ditampilkan di menu
+	blocklistDisplayLimit = 30
+	// Jeda antar grup saat mengeluarkan peserta hasil sweep
+	blocklistSweepDelay = 2 * time.Second
+)
+
+// ShowBlocklistMenuEdit menampilkan blocklist nomor akun beserta tombol tambah/hapus/sweep
+func ShowBlocklistMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildBlocklistMenu()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildBlocklistMenu membuat teks daftar blocklist (plain text karena catatan bebas)
+func buildBlocklistMenu() (string, tgbotapi.InlineKeyboardMarkup) {
+	entries, err := utils.GetBlocklistAt("", clientAccountID(GetWhatsAppClient()))
+
+	var text strings.Builder
+	text.WriteString("⛔ BLOCKLIST NOMOR\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Nomor di blocklist otomatis dikeluarkan saat masuk / ditambahkan ke grup tempat akun ini admin, dan Anda diberi tahu di sini.\n\n")
+	text.WriteString("🧹 Sweep memeriksa seluruh peserta di semua grup yang Anda kelola.\n")
+
+	if err != nil {
+		text.WriteString(fmt.Sprintf("\n❌ Gagal memuat blocklist: %v\n", err))
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(entries) == 0 && err == nil {
+		text.WriteString("Blocklist masih kosong. Klik ➕ untuk menambahkan nomor.")
+	} else if len(entries) > 0 {
+		text.WriteString(fmt.Sprintf("📋 %d nomor:\n", len(entries)))
+		for i, entry := range entries {
+			if i >= blocklistDisplayLimit {
+				text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(entries)-blocklistDisplayLimit))
+				break
+			}
+			if entry.Note != "" {
+				text.WriteString(fmt.Sprintf("• +%s - %s\n", entry.Phone, entry.Note))
+			} else {
+				text.WriteString(fmt.Sprintf("• +%s\n", entry.Phone))
+			}
+		}
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah Nomor", "blocklist_add"),
+			tgbotapi.NewInlineKeyboardButtonData("➖ Hapus Nomor", "blocklist_remove"),
+		),
+	}
+	if len(entries) > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧹 Sweep Semua Grup", "blocklist_sweep"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+	))
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// StartBlocklistInput meminta user mengetik nomor yang akan ditambahkan / dihapus dari blocklist
+func StartBlocklistInput(add bool, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	blocklistStates[chatID] = &BlocklistState{WaitingForAdd: add, WaitingForRemove: !add}
+
+	var promptMsg string
+	if add {
+		promptMsg = `➕ **TAMBAH KE BLOCKLIST**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Ketik nomor, satu per baris. Catatan opsional setelah tanda ` + "`-`" + `.
+
+**Contoh:**
+` + "`628123456789 - spam promosi\n081234567890`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Format 08xx otomatis diubah ke 628xx.
+
+⏳ Menunggu input...`
+	} else {
+		promptMsg = `➖ **HAPUS DARI BLOCKLIST**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Ketik nomor yang akan dihapus, satu per baris (atau pisahkan dengan koma).
+
+⏳ Menunggu input...`
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "blocklist_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// parseBlocklistInput membaca nomor (dan catatan opsional) dari input user. Return entri valid dan baris yang ditolak
+func parseBlocklistInput(input string) ([]utils.BlocklistEntry, []string) {
+	var entries []utils.BlocklistEntry
+	var invalid []string
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Baris tanpa catatan boleh berisi beberapa nomor dipisah koma
+		numbers, note, hasNote := strings.Cut(line, " - ")
+		fields := []string{numbers}
+		if !hasNote {
+			fields = strings.Split(line, ",")
+		}
+
+		for _, field := range fields {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			phone := normalizePhoneForComparison(field)
+			if phone == "" {
+				invalid = append(invalid, field)
+				continue
+			}
+			if !seen[phone] {
+				seen[phone] = true
+				entries = append(entries, utils.BlocklistEntry{Phone: phone, Note: strings.TrimSpace(note)})
+			}
+		}
+	}
+	return entries, invalid
+}
+
+// HandleBlocklistInput menyimpan / menghapus nomor blocklist dari input user
+func HandleBlocklistInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := blocklistStates[chatID]
+	if state == nil || (!state.WaitingForAdd && !state.WaitingForRemove) {
+		return
+	}
+
+	entries, invalid := parseBlocklistInput(input)
+	if len(entries) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tidak ada nomor yang valid!\n\nContoh: 628123456789 atau 081234567890"))
+		return
+	}
+
+	accountID := clientAccountID(client)
+	var resultMsg string
+	if state.WaitingForAdd {
+		if err := utils.AddBlocklistEntries(accountID, entries); err != nil {
+			msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan blocklist"))
+			msg.ParseMode = "Markdown"
+			telegramBot.Send(msg)
+			return
+		}
+		resultMsg = fmt.Sprintf("✅ %d nomor ditambahkan ke blocklist.\n\n💡 Jalankan 🧹 Sweep untuk mengeluarkan nomor yang sudah terlanjur ada di grup.", len(entries))
+		utils.LogActivity("blocklist_add", fmt.Sprintf("Added %d numbers to blocklist", len(entries)), chatID)
+	} else {
+		phones := make([]string, len(entries))
+		for i, entry := range entries {
+			phones[i] = entry.Phone
+		}
+		removed, err := utils.RemoveBlocklistPhones(accountID, phones)
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menghapus dari blocklist"))
+			msg.ParseMode = "Markdown"
+			telegramBot.Send(msg)
+			return
+		}
+		resultMsg = fmt.Sprintf("✅ %d nomor dihapus dari blocklist.", removed)
+		if removed < len(phones) {
+			resultMsg += fmt.Sprintf("\nℹ️ %d nomor tidak ada di blocklist.", len(phones)-removed)
+		}
+		utils.LogActivity("blocklist_remove", fmt.Sprintf("Removed %d numbers from blocklist", removed), chatID)
+	}
+	delete(blocklistStates, chatID)
+
+	if len(invalid) > 0 {
+		resultMsg += fmt.Sprintf("\n\n⚠️ Diabaikan (format tidak valid): %s", strings.Join(invalid, ", "))
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, resultMsg))
+
+	text, keyboard := buildBlocklistMenu()
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// blocklistedParticipant mengecek apakah peserta grup ada di blocklist. Return label peserta jika cocok
+func blocklistedParticipant(client *whatsmeow.Client, blocklist map[string]bool, participant types.GroupParticipant) (string, bool) {
+	phone := participantPhone(client, participant)
+	normalized := normalizePhoneForComparison(phone)
+	if normalized == "" {
+		normalized = phone
+	}
+	if normalized == "" || !blocklist[normalized] {
+		return "", false
+	}
+	return "+" + normalized, true
+}
+
+// ScanBlocklistSweep memeriksa peserta semua grup tempat akun ini admin lalu menampilkan preview peserta blocklist
+// Memakai satu request GetJoinedGroups (sudah berisi daftar peserta) agar tidak perlu GetGroupInfo per grup
+func ScanBlocklistSweep(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	backKeyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData("🔙 Blocklist", "blocklist_menu")),
+	)
+
+	if client == nil || !client.IsConnected() {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+		editMsg.ReplyMarkup = &backKeyboard
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	blocklist, err := utils.GetBlocklistSetAt("", clientAccountID(client))
+	if err != nil || len(blocklist) == 0 {
+		ShowBlocklistMenuEdit(telegramBot, chatID, messageID)
+		return
+	}
+
+	telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "🔍 Memindai peserta semua grup..."))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	joinedGroups, err := client.GetJoinedGroups(ctx)
+	cancel()
+	if err != nil {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengambil daftar grup"))
+		editMsg.ParseMode = "Markdown"
+		editMsg.ReplyMarkup = &backKeyboard
+		telegramBot.Send(editMsg)
+		return
+	}
+	cacheGroupCapabilities(client, joinedGroups)
+
+	state := &BlocklistState{}
+	adminGroups, totalMembers := 0, 0
+	for _, group := range joinedGroups {
+		if group == nil {
+			continue
+		}
+
+		isAdmin := false
+		plan := blocklistSweepPlan{Group: GroupLinkInfo{JID: group.JID.String(), Name: group.Name}}
+		for _, participant := range group.Participants {
+			if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+				isAdmin = participant.IsAdmin || participant.IsSuperAdmin
+				continue
+			}
+			label, blocked := blocklistedParticipant(client, blocklist, participant)
+			if !blocked {
+				continue
+			}
+			// Owner grup tidak bisa dikeluarkan
+			if participant.IsSuperAdmin || participant.JID == group.OwnerJID {
+				plan.Skipped = append(plan.Skipped, label)
+				continue
+			}
+			plan.Members = append(plan.Members, rosterParticipant{JID: participant.JID, Label: label})
+		}
+
+		if !isAdmin {
+			continue
+		}
+		adminGroups++
+		if len(plan.Members)+len(plan.Skipped) > 0 {
+			totalMembers += len(plan.Members)
+			state.Plans = append(state.Plans, plan)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString("🧹 HASIL SWEEP BLOCKLIST\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("👑 Grup yang dikelola: %d\n", adminGroups))
+	text.WriteString(fmt.Sprintf("⛔ Nomor di blocklist: %d\n", len(blocklist)))
+	text.WriteString(fmt.Sprintf("🚫 Akan dikeluarkan: %d peserta di %d grup\n", totalMembers, len(state.Plans)))
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	writeBlocklistSweepPlans(&text, state.Plans)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if totalMembers > 0 {
+		blocklistStates[chatID] = state
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🚫 Keluarkan %d Peserta", totalMembers), "blocklist_sweep_apply"),
+		))
+	} else {
+		text.WriteString("\n✅ Tidak ada peserta blocklist di grup yang Anda kelola.")
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 Blocklist", "blocklist_menu"),
+	))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, truncateRosterText(text.String()))
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// writeBlocklistSweepPlans menulis rincian peserta blocklist per grup
+func writeBlocklistSweepPlans(text *strings.Builder, plans []blocklistSweepPlan) {
+	for i, plan := range plans {
+		if i >= adminRosterDisplayLimit {
+			text.WriteString(fmt.Sprintf("\n... dan %d grup lainnya\n", len(plans)-adminRosterDisplayLimit))
+			break
+		}
+		text.WriteString(fmt.Sprintf("\n👥 %s\n", plan.Group.Name))
+		if len(plan.Members) > 0 {
+			text.WriteString(fmt.Sprintf("   🚫 %s\n", rosterLabels(plan.Members)))
+		}
+		if len(plan.Skipped) > 0 {
+			text.WriteString(fmt.Sprintf("   🛡️ Owner grup (tidak bisa dikeluarkan): %s\n", strings.Join(plan.Skipped, ", ")))
+		}
+	}
+}
+
+// ApplyBlocklistSweep mengeluarkan peserta blocklist hasil scan sweep dari setiap grup
+func ApplyBlocklistSweep(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := blocklistStates[chatID]
+	if state == nil || len(state.Plans) == 0 {
+		ShowBlocklistMenuEdit(telegramBot, chatID, messageID)
+		return
+	}
+	delete(blocklistStates, chatID)
+
+	var targets []blocklistSweepPlan
+	for _, plan := range state.Plans {
+		if len(plan.Members) > 0 {
+			targets = append(targets, plan)
+		}
+	}
+
+	groups := make([]GroupLinkInfo, len(targets))
+	for i, plan := range targets {
+		groups[i] = plan.Group
+	}
+
+	job := StartGroupJob(chatID, "Sweep Blocklist", groupJobItems(groups))
+	defer job.Finish()
+
+	removed := 0
+	var failures []string
+	var report []blocklistSweepPlan
+
+	for i, plan := range targets {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ApplyBlocklistSweep", i, len(targets))
+		if shouldStop {
+			failures = append(failures, fmt.Sprintf("⚠️ Client terputus pada grup %d/%d", i+1, len(targets)))
+			break
+		}
+
+		progress := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("⏳ Mengeluarkan peserta blocklist...\n\n%s\n📊 %d/%d grup\n👥 %s",
+			generateProgressBar((i*100)/len(targets)), i+1, len(targets), plan.Group.Name))
+		telegramBot.Send(progress)
+
+		groupJID, _ := parseJIDFromString(plan.Group.JID)
+		ok, errs := applyRosterChange(job.Context(), validClient, groupJID, plan.Members, whatsmeow.ParticipantChangeRemove)
+		removed += len(ok)
+		for _, e := range errs {
+			failures = append(failures, fmt.Sprintf("❌ %s - %s", plan.Group.Name, e))
+		}
+		if len(ok) > 0 {
+			report = append(report, blocklistSweepPlan{Group: plan.Group, Members: ok})
+		}
+
+		if i < len(targets)-1 {
+			job.Sleep(blocklistSweepDelay)
+		}
+	}
+
+	utils.LogActivityWithMetadata("blocklist_sweep", fmt.Sprintf("Blocklist sweep removed %d participants from %d groups", removed, len(report)), chatID,
+		map[string]interface{}{"removed": removed, "groups": len(report), "failed": len(failures)}, len(failures) == 0)
+
+	var text strings.Builder
+	text.WriteString(job.PausedNote())
+	text.WriteString("🎉 SWEEP BLOCKLIST SELESAI\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("🚫 Dikeluarkan: %d peserta\n", removed))
+	text.WriteString(fmt.Sprintf("👥 Grup: %d\n", len(report)))
+	text.WriteString(fmt.Sprintf("❌ Gagal: %d\n", len(failures)))
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	writeBlocklistSweepPlans(&text, report)
+	if len(failures) > 0 {
+		text.WriteString("\n❌ Gagal:\n")
+		for i, failure := range failures {
+			if i >= adminRosterDisplayLimit {
+				text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(failures)-adminRosterDisplayLimit))
+				break
+			}
+			text.WriteString(failure + "\n")
+		}
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Blocklist", "blocklist_menu"),
+		),
+	)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, truncateRosterText(text.String()))
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// EnforceBlocklistOnJoin mengeluarkan anggota baru yang ada di blocklist akun (dbPath = database akun sumber event)
+// Return JID yang dikeluarkan beserta label nomornya, dan nama grup untuk notifikasi
+func EnforceBlocklistOnJoin(client *whatsmeow.Client, dbPath string, evt *events.GroupInfo) ([]types.JID, []string, string, error) {
+	if client == nil || client.Store.ID == nil || len(evt.Join) == 0 {
+		return nil, nil, "", nil
+	}
+
+	blocklist, err := utils.GetBlocklistSetAt(dbPath, client.Store.ID.User)
+	if err != nil || len(blocklist) == 0 {
+		return nil, nil, "", err
+	}
+
+	var matches []rosterParticipant
+	for _, jid := range evt.Join {
+		if isOwnJID(client, jid) {
+			continue
+		}
+		phone := requesterPhone(client, jid)
+		normalized := normalizePhoneForComparison(phone)
+		if normalized == "" {
+			normalized = phone
+		}
+		if normalized != "" && blocklist[normalized] {
+			matches = append(matches, rosterParticipant{JID: jid, Label: "+" + normalized})
+		}
+	}
+	if len(matches) == 0 {
+		return nil, nil, "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	info, err := client.GetGroupInfo(ctx, evt.JID)
+	cancel()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	isAdmin := false
+	for _, participant := range info.Participants {
+		if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+			isAdmin = participant.IsAdmin || participant.IsSuperAdmin
+			break
+		}
+	}
+	if !isAdmin {
+		return nil, nil, info.Name, nil
+	}
+
+	removed, errs := applyRosterChange(context.Background(), client, evt.JID, matches, whatsmeow.ParticipantChangeRemove)
+	jids := make([]types.JID, len(removed))
+	labels := make([]string, len(removed))
+	for i, participant := range removed {
+		jids[i] = participant.JID
+		labels[i] = participant.Label
+	}
+	if len(errs) > 0 {
+		err = fmt.Errorf("gagal mengeluarkan %s", strings.Join(errs, ", "))
+	}
+	return jids, labels, info.Name, err
+}
+
+// IsWaitingForBlocklistInput mengecek apakah user sedang mengetik nomor blocklist
+func IsWaitingForBlocklistInput(chatID int64) bool {
+	state := blocklistStates[chatID]
+	return state != nil && (state.WaitingForAdd || state.WaitingForRemove)
+}
+
+// CancelBlocklistInput membatalkan input / sweep blocklist yang tertunda
+func CancelBlocklistInput(chatID int64) {
+	delete(blocklistStates, chatID)
+}
@@ -739,7 +739,9 @@ func ProcessAllSettings(chatID int64, client *whatsmeow.Client, telegramBot *tgb
 	telegramBot.Send(msg)
 
 	// Process in goroutine
-	go ProcessAllSettingsBatch(state.SelectedGroups, state.DelaySeconds, state, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Atur Semua Setting", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessAllSettingsBatch(allowed, state.DelaySeconds, state, chatID, client, telegramBot)
+	})
 
 	// State will be cleared after processing completes in ProcessAllSettingsBatch
 }
@@ -774,7 +776,15 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 	var progressMsgSent *tgbotapi.Message
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Atur Semua Pengaturan", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// CRITICAL FIX: Ambil active client di setiap iterasi untuk proses SANGAT panjang!
 		// ProcessAllSettingsBatch bisa berjalan 1+ jam untuk banyak grup × banyak settings
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessAllSettingsBatch", i, totalGroups)
@@ -799,7 +809,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 		if state.MessageLogging != nil {
 			currentOp++
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			defer cancel() // FIXED: Use defer to ensure cancellation
 			if *state.MessageLogging {
 				err = validClient.SetGroupAnnounce(ctx, jid, false)
@@ -817,7 +827,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 		if state.MemberAdd != nil {
 			currentOp++
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			defer cancel() // FIXED: Use defer to ensure cancellation
 			var addMode types.GroupMemberAddMode
 			if *state.MemberAdd {
@@ -837,7 +847,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 		if state.JoinApproval != nil {
 			currentOp++
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			defer cancel() // FIXED: Use defer to ensure cancellation
 			err = validClient.SetGroupJoinApprovalMode(ctx, jid, *state.JoinApproval)
 
@@ -851,7 +861,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 		if state.Ephemeral != nil {
 			currentOp++
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			timer := time.Duration(*state.Ephemeral) * time.Second
 			settingTS := time.Now()
 			err = validClient.SetDisappearingTimer(ctx, jid, timer, settingTS)
@@ -867,7 +877,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 
 		if state.EditSettings != nil {
 			currentOp++
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			locked := !*state.EditSettings
 			err = validClient.SetGroupLocked(ctx, jid, locked)
 			cancel()
@@ -884,7 +894,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 		// Delay hanya diterapkan setelah semua pengaturan untuk grup ini selesai
 		// dan sebelum lanjut ke grup berikutnya
 		if i < len(groups)-1 && delay > 0 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 
 		// Show progress (per grup, bukan per operasi)
@@ -924,7 +934,7 @@ func ProcessAllSettingsBatch(groups []GroupLinkInfo, delay int, state *GroupAllS
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
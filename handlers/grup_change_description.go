@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"text/template"
 	"time"
 	"whatsapp-bot/utils"
 
@@ -19,6 +20,7 @@ type GroupDescriptionState struct {
 	WaitingForGroupName   bool
 	WaitingForDelay       bool
 	WaitingForDescription bool
+	WaitingForConfirm     bool // Menunggu konfirmasi preview template per grup
 	SelectedGroups        []GroupLinkInfo
 	Keyword               string
 	DelaySeconds          int
@@ -430,6 +432,8 @@ Ketik deskripsi yang ingin Anda gunakan untuk grup-grup tersebut.
 • Multi-line untuk format rapi
 • Pastikan tidak lebih dari 1024 karakter
 
+%s
+
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 **Contoh Deskripsi:**
@@ -443,7 +447,7 @@ Rules:
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-⏳ Menunggu deskripsi dari Anda...`, len(state.SelectedGroups), delay)
+⏳ Menunggu deskripsi dari Anda...`, len(state.SelectedGroups), delay, groupTemplateHelp)
 
 	msg := tgbotapi.NewMessage(chatID, descriptionMsg)
 	msg.ParseMode = "Markdown"
@@ -482,6 +486,28 @@ func HandleDescriptionInput(description string, chatID int64, client *whatsmeow.
 	state.Description = description
 	state.WaitingForDescription = false
 
+	// Template per grup: tampilkan preview hasil render sebelum job berjalan
+	if IsGroupTemplate(description) {
+		preview, err := BuildGroupTemplatePreview(client, state.SelectedGroups, description, 3)
+		if err != nil {
+			state.WaitingForDescription = true
+			errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ %v\n\nSilakan ketik ulang deskripsi.", err))
+			telegramBot.Send(errorMsg)
+			return
+		}
+
+		state.WaitingForConfirm = true
+		previewMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("👀 PREVIEW DESKRIPSI PER GRUP\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n%s━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n📊 Total: %d grup\n💡 Klik Terapkan untuk mulai mengubah deskripsi.", preview, len(state.SelectedGroups)))
+		previewMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✅ Terapkan", "description_template_apply"),
+				tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_change_description"),
+			),
+		)
+		telegramBot.Send(previewMsg)
+		return
+	}
+
 	// Start processing
 	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Deskripsi diterima!\n\n📝 **Deskripsi:**\n%s\n\n📊 **Panjang:** %d karakter\n\n🚀 Memulai proses ubah deskripsi untuk %d grup...",
 		description, len(description), len(state.SelectedGroups)))
@@ -489,22 +515,61 @@ func HandleDescriptionInput(description string, chatID int64, client *whatsmeow.
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeDescriptions(state.SelectedGroups, state.DelaySeconds, state.Description, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Ubah Deskripsi", state.SelectedGroups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeDescriptions(allowed, state.DelaySeconds, state.Description, chatID, client, telegramBot)
+	})
+
+	// Clear state
+	delete(groupDescriptionStates, chatID)
+}
+
+// ApplyDescriptionTemplate menjalankan ubah deskripsi setelah preview template per grup dikonfirmasi
+func ApplyDescriptionTemplate(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := groupDescriptionStates[chatID]
+	if state == nil || !state.WaitingForConfirm {
+		return
+	}
+
+	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🚀 Memulai proses ubah deskripsi (template per grup) untuk %d grup...", len(state.SelectedGroups)))
+	telegramBot.Send(startMsg)
+
+	StartWithAdminPreflight(chatID, "Ubah Deskripsi", state.SelectedGroups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeDescriptions(allowed, state.DelaySeconds, state.Description, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupDescriptionStates, chatID)
 }
 
 // ProcessChangeDescriptions memproses pengubahan deskripsi grup
+// Jika description berisi template ({{.Name}} dll), deskripsi dirender per grup
 func ProcessChangeDescriptions(groups []GroupLinkInfo, delay int, description string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
 	totalGroups := len(groups)
 	successCount := 0
 	failedCount := 0
 	var failedGroups []string
 
+	var descriptionTemplate *template.Template
+	if IsGroupTemplate(description) {
+		tmpl, err := ParseGroupTemplate(description)
+		if err != nil {
+			telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ %v", err)))
+			return
+		}
+		descriptionTemplate = tmpl
+	}
+
 	var progressMsgSent *tgbotapi.Message
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Atur Deskripsi", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// HIGH FIX: Ambil active client di setiap iterasi untuk proses panjang!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeDescriptions", i, totalGroups)
 		if shouldStop {
@@ -524,9 +589,23 @@ func ProcessChangeDescriptions(groups []GroupLinkInfo, delay int, description st
 			continue
 		}
 
+		// Render deskripsi per grup jika memakai template
+		groupDescription := description
+		if descriptionTemplate != nil {
+			groupDescription, err = RenderGroupTemplate(descriptionTemplate, LoadGroupTemplateData(validClient, group, i, description))
+			if err == nil && len(groupDescription) > 1024 {
+				err = fmt.Errorf("hasil template lebih dari 1024 karakter")
+			}
+			if err != nil {
+				failedCount++
+				failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (%v)", group.Name, err))
+				continue
+			}
+		}
+
 		// Set group description dengan validClient
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		err = validClient.SetGroupDescription(ctx, jid, description)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
+		err = validClient.SetGroupDescription(ctx, jid, groupDescription)
 		cancel()
 
 		if err != nil {
@@ -564,7 +643,7 @@ func ProcessChangeDescriptions(groups []GroupLinkInfo, delay int, description st
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -575,7 +654,7 @@ func ProcessChangeDescriptions(groups []GroupLinkInfo, delay int, description st
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
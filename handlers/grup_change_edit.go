@@ -480,7 +480,9 @@ func HandleToggleInputForEdit(toggle bool, chatID int64, client *whatsmeow.Clien
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeEdit(state.SelectedGroups, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Atur Edit Grup", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeEdit(allowed, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupEditStates, chatID)
@@ -500,7 +502,15 @@ func ProcessChangeEdit(groups []GroupLinkInfo, delay int, toggleValue bool, chat
 		toggleText = "OFF"
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Atur Edit Info", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// MEDIUM FIX: Ambil active client di setiap iterasi!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeEdit", i, totalGroups)
 		if shouldStop {
@@ -523,7 +533,7 @@ func ProcessChangeEdit(groups []GroupLinkInfo, delay int, toggleValue bool, chat
 		// Set group edit settings
 		// ON = All members can edit (SetGroupLocked(false))
 		// OFF = Only admins can edit (SetGroupLocked(true))
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 		defer cancel() // FIXED: Use defer to ensure cancellation
 
 		// SetGroupLocked controls who can edit group settings
@@ -569,7 +579,7 @@ func ProcessChangeEdit(groups []GroupLinkInfo, delay int, toggleValue bool, chat
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -580,7 +590,7 @@ func ProcessChangeEdit(groups []GroupLinkInfo, delay int, toggleValue bool, chat
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
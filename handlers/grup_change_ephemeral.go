@@ -500,7 +500,9 @@ func HandleDurationInputForEphemeral(durationSeconds int64, chatID int64, client
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeEphemeral(state.SelectedGroups, state.DelaySeconds, state.DurationSeconds, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Pesan Sementara", state.SelectedGroups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeEphemeral(allowed, state.DelaySeconds, state.DurationSeconds, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupEphemeralStates, chatID)
@@ -530,7 +532,15 @@ func ProcessChangeEphemeral(groups []GroupLinkInfo, delay int, durationSeconds i
 		durationText = fmt.Sprintf("%d detik", durationSeconds)
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Pesan Sementara", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// MEDIUM FIX: Ambil active client di setiap iterasi!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeEphemeral", i, totalGroups)
 		if shouldStop {
@@ -552,7 +562,7 @@ func ProcessChangeEphemeral(groups []GroupLinkInfo, delay int, durationSeconds i
 
 		// Set group ephemeral/disappearing messages
 		// Duration: 0 = OFF, 86400 = 24h, 604800 = 7d, 7776000 = 90d
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 		defer cancel() // FIXED: Use defer to ensure cancellation
 
 		// SetDisappearingTimer requires time.Duration and settingTS
@@ -597,7 +607,7 @@ func ProcessChangeEphemeral(groups []GroupLinkInfo, delay int, durationSeconds i
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -608,7 +618,7 @@ func ProcessChangeEphemeral(groups []GroupLinkInfo, delay int, durationSeconds i
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -57,6 +57,7 @@ Fitur ini akan mengatur apakah admin perlu menyetujui anggota baru sebelum merek
 💡 **Penjelasan:**
 • **ON** - Admin harus setujui anggota baru (approval mode)
 • **OFF** - Anggota bisa langsung bergabung (auto join)
+• **Kelola Permintaan Masuk** - Setujui/tolak pemohon & aturan auto-approve
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -70,6 +71,9 @@ Fitur ini akan mengatur apakah admin perlu menyetujui anggota baru sebelum merek
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Atur Semua", "change_all_join_approval"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📥 Kelola Permintaan Masuk", "member_requests_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "join_approval_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -109,6 +113,7 @@ Fitur ini akan mengatur apakah admin perlu menyetujui anggota baru sebelum merek
 💡 **Penjelasan:**
 • **ON** - Admin harus setujui anggota baru (approval mode)
 • **OFF** - Anggota bisa langsung bergabung (auto join)
+• **Kelola Permintaan Masuk** - Setujui/tolak pemohon & aturan auto-approve
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -122,6 +127,9 @@ Fitur ini akan mengatur apakah admin perlu menyetujui anggota baru sebelum merek
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Atur Semua", "change_all_join_approval"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📥 Kelola Permintaan Masuk", "member_requests_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "join_approval_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -483,7 +491,9 @@ func HandleToggleInputForJoinApproval(toggle bool, chatID int64, client *whatsme
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeJoinApproval(state.SelectedGroups, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Persetujuan Anggota", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeJoinApproval(allowed, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupJoinApprovalStates, chatID)
@@ -503,7 +513,15 @@ func ProcessChangeJoinApproval(groups []GroupLinkInfo, delay int, toggleValue bo
 		toggleText = "OFF"
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Persetujuan Anggota", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// MEDIUM FIX: Ambil active client di setiap iterasi!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeJoinApproval", i, totalGroups)
 		if shouldStop {
@@ -526,7 +544,7 @@ func ProcessChangeJoinApproval(groups []GroupLinkInfo, delay int, toggleValue bo
 		// Set group join approval mode
 		// ON = Approval required (SetGroupJoinApprovalMode(true))
 		// OFF = Auto join (SetGroupJoinApprovalMode(false))
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 		defer cancel() // FIXED: Use defer to ensure cancellation
 		err = validClient.SetGroupJoinApprovalMode(ctx, jid, toggleValue)
 
@@ -566,7 +584,7 @@ func ProcessChangeJoinApproval(groups []GroupLinkInfo, delay int, toggleValue bo
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -577,7 +595,7 @@ func ProcessChangeJoinApproval(groups []GroupLinkInfo, delay int, toggleValue bo
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -481,7 +481,9 @@ func HandleToggleInputForMemberAdd(toggle bool, chatID int64, client *whatsmeow.
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeMemberAdd(state.SelectedGroups, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Atur Tambah Anggota", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeMemberAdd(allowed, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupMemberAddStates, chatID)
@@ -501,7 +503,15 @@ func ProcessChangeMemberAdd(groups []GroupLinkInfo, delay int, toggleValue bool,
 		toggleText = "OFF"
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Atur Tambah Anggota", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// MEDIUM FIX: Ambil active client di setiap iterasi!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeMemberAdd", i, totalGroups)
 		if shouldStop {
@@ -524,7 +534,7 @@ func ProcessChangeMemberAdd(groups []GroupLinkInfo, delay int, toggleValue bool,
 		// Set group member add mode
 		// ON = All members can add (GroupMemberAddModeAllMember)
 		// OFF = Only admins can add (GroupMemberAddModeAdmin)
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 
 		var addMode types.GroupMemberAddMode
 		if toggleValue {
@@ -574,7 +584,7 @@ func ProcessChangeMemberAdd(groups []GroupLinkInfo, delay int, toggleValue bool,
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -585,7 +595,7 @@ func ProcessChangeMemberAdd(groups []GroupLinkInfo, delay int, toggleValue bool,
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
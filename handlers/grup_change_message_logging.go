@@ -495,7 +495,9 @@ func HandleToggleInput(input string, chatID int64, client *whatsmeow.Client, tel
 	telegramBot.Send(startMsg)
 
 	// Process in goroutine
-	go ProcessChangeLogging(state.SelectedGroups, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	StartWithAdminPreflight(chatID, "Atur Kirim Pesan", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangeLogging(allowed, state.DelaySeconds, state.ToggleValue, chatID, client, telegramBot)
+	})
 
 	// Clear state
 	delete(groupMessageLoggingStates, chatID)
@@ -515,7 +517,15 @@ func ProcessChangeLogging(groups []GroupLinkInfo, delay int, toggleValue bool, c
 		toggleText = "OFF"
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Atur Kirim Pesan", groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// HIGH FIX: Ambil active client di setiap iterasi untuk proses panjang!
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangeLogging", i, totalGroups)
 		if shouldStop {
@@ -539,7 +549,7 @@ func ProcessChangeLogging(groups []GroupLinkInfo, delay int, toggleValue bool, c
 		// SetGroupAnnounce controls who can send messages in group
 		// SetGroupAnnounce(false) = ON: All members can send messages
 		// SetGroupAnnounce(true) = OFF: Only admins can send messages
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 
 		if toggleValue {
 			// ON: Allow all members to send messages
@@ -586,7 +596,7 @@ func ProcessChangeLogging(groups []GroupLinkInfo, delay int, toggleValue bool, c
 
 		// Delay
 		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -597,7 +607,7 @@ func ProcessChangeLogging(groups []GroupLinkInfo, delay int, toggleValue bool, c
 	}
 
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -2,14 +2,11 @@ package handlers
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"image"
-	"image/draw"
 	_ "image/gif" // Register GIF decoder
-	"image/jpeg"
 	_ "image/png" // Register PNG decoder
 	"io"
 	"net/http"
@@ -21,56 +18,9 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
-	xdraw "golang.org/x/image/draw"
 	_ "golang.org/x/image/webp" // Register WEBP decoder
 )
 
-// resizeImage resizes image to square format (size x size)
-func resizeImage(img image.Image, size int) image.Image {
-	bounds := img.Bounds()
-	width := bounds.Dx()
-	height := bounds.Dy()
-
-	// If already the right size, return as is
-	if width == size && height == size {
-		return img
-	}
-
-	// Calculate new dimensions to maintain aspect ratio
-	var newWidth, newHeight int
-	if width > height {
-		// Landscape: fit height
-		newHeight = size
-		newWidth = (width * size) / height
-	} else {
-		// Portrait or square: fit width
-		newWidth = size
-		newHeight = (height * size) / width
-	}
-
-	// Create resized image
-	resized := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-	xdraw.NearestNeighbor.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
-
-	// Crop to square (center crop)
-	var cropX, cropY int
-	if newWidth > newHeight {
-		// Crop width (landscape)
-		cropX = (newWidth - size) / 2
-		cropY = 0
-	} else {
-		// Crop height (portrait)
-		cropX = 0
-		cropY = (newHeight - size) / 2
-	}
-
-	// Create final cropped square image
-	cropped := image.NewRGBA(image.Rect(0, 0, size, size))
-	draw.Draw(cropped, cropped.Bounds(), resized, image.Point{cropX, cropY}, draw.Src)
-
-	return cropped
-}
-
 // parseJIDFromString parses a string to types.JID
 func parseJIDFromString(jidStr string) (types.JID, error) {
 	jid, err := types.ParseJID(jidStr)
@@ -89,6 +39,7 @@ type GroupPhotoState struct {
 	Keyword             string
 	DelaySeconds        int
 	PhotoPath           string
+	PhotoOptions        PhotoOptions // Mode crop yang dipilih (default crop tengah)
 }
 
 var groupPhotoStates = make(map[int64]*GroupPhotoState)
@@ -106,13 +57,14 @@ Fitur ini akan mengganti foto profil grup WhatsApp yang Anda pilih.
 🔍 **Cari Manual** - Ketik nama/kata kunci grup
 📋 **Lihat & Pilih** - Lihat daftar lalu pilih
 ⚡ **Ubah Semua** - Proses semua grup sekaligus
+🗂️ **Foto per Grup (ZIP)** - Setiap grup dapat foto sendiri, nama file = nama grup
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 ⚠️ **Catatan Penting:**
 • Bot harus menjadi admin grup untuk ganti foto
 • Delay membantu menghindari rate limit WhatsApp
-• Foto akan sama untuk semua grup yang dipilih
+• Foto akan sama untuk semua grup yang dipilih (kecuali kirim ZIP)
 • Proses mungkin memakan waktu untuk banyak grup
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -127,6 +79,9 @@ Fitur ini akan mengganti foto profil grup WhatsApp yang Anda pilih.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Ubah Semua", "change_all_photos"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗂️ Foto per Grup (ZIP)", "start_zip_photo"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "photo_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -152,13 +107,14 @@ Fitur ini akan mengganti foto profil grup WhatsApp yang Anda pilih.
 🔍 **Cari Manual** - Ketik nama/kata kunci grup
 📋 **Lihat & Pilih** - Lihat daftar lalu pilih
 ⚡ **Ubah Semua** - Proses semua grup sekaligus
+🗂️ **Foto per Grup (ZIP)** - Setiap grup dapat foto sendiri, nama file = nama grup
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 ⚠️ **Catatan Penting:**
 • Bot harus menjadi admin grup untuk ganti foto
 • Delay membantu menghindari rate limit WhatsApp
-• Foto akan sama untuk semua grup yang dipilih
+• Foto akan sama untuk semua grup yang dipilih (kecuali kirim ZIP)
 • Proses mungkin memakan waktu untuk banyak grup
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -173,6 +129,9 @@ Fitur ini akan mengganti foto profil grup WhatsApp yang Anda pilih.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Ubah Semua", "change_all_photos"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗂️ Foto per Grup (ZIP)", "start_zip_photo"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "photo_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -479,6 +438,7 @@ func HandleDelayInputForPhoto(input string, chatID int64, telegramBot *tgbotapi.
 🖼️ **LANGKAH TERAKHIR**
 
 Kirim foto yang ingin Anda gunakan sebagai foto profil grup.
+Atau kirim file .zip berisi gambar per grup (nama file = nama grup).
 
 **Persyaratan Foto:**
 • Format: JPG, PNG, WEBP
@@ -491,9 +451,12 @@ Kirim foto yang ingin Anda gunakan sebagai foto profil grup.
 💡 **Tips:**
 • Gunakan foto dengan resolusi tinggi
 • Pastikan foto tidak buram
-• Foto akan di-crop otomatis jadi persegi
+• Foto dibuat persegi sesuai mode crop (default: tengah)
 • Gunakan foto yang represent grup Anda
 
+✂️ **Mode Crop:** Tengah, Atas, atau Fit (foto utuh + padding warna)
+🔤 **Avatar Teks:** Tanpa kirim foto, buat avatar inisial/nama grup untuk grup yang belum punya foto
+
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 ⏳ Menunggu foto dari Anda...`, len(state.SelectedGroups), delay)
@@ -502,6 +465,19 @@ Kirim foto yang ingin Anda gunakan sebagai foto profil grup.
 	msg.ParseMode = "Markdown"
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✂️ Tengah", "photo_crop_center"),
+			tgbotapi.NewInlineKeyboardButtonData("⬆️ Atas", "photo_crop_top"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬜ Fit Putih", "photo_crop_fit_white"),
+			tgbotapi.NewInlineKeyboardButtonData("⬛ Fit Hitam", "photo_crop_fit_black"),
+			tgbotapi.NewInlineKeyboardButtonData("🎨 Fit Otomatis", "photo_crop_fit_auto"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔤 Avatar Inisial", "photo_avatar_initials"),
+			tgbotapi.NewInlineKeyboardButtonData("🔤 Avatar Nama", "photo_avatar_name"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_change_photo"),
 		),
@@ -557,34 +533,19 @@ func HandlePhotoUpload(photo *tgbotapi.PhotoSize, chatID int64, client *whatsmeo
 		return
 	}
 
-	// Decode image (supports JPG, PNG, GIF, WEBP)
-	img, format, err := image.Decode(bytes.NewReader(imgData))
-	if err != nil {
-		deleteMsg := tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID)
-		telegramBot.Request(deleteMsg)
-
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Foto tidak valid: %v\n\nFormat terdeteksi: %s\n\nGunakan foto JPG/PNG yang valid.", err, format))
-		telegramBot.Send(errorMsg)
-		return
-	}
-
-	// Resize to 640x640 (WhatsApp requirement)
-	resizedImg := resizeImage(img, 640)
-
-	// Re-encode to JPEG with quality 90 (balance between quality and compatibility)
-	var buf bytes.Buffer
-	err = jpeg.Encode(&buf, resizedImg, &jpeg.Options{Quality: 90})
+	// Decode, resize ke 640x640 sesuai mode crop dan encode ulang ke JPEG
+	photoBytes, format, err := preparePhotoWithOptions(imgData, state.PhotoOptions)
 	if err != nil {
 		deleteMsg := tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID)
 		telegramBot.Request(deleteMsg)
 
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error convert foto: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Foto tidak valid: %v\n\nGunakan foto JPG/PNG yang valid.", err))
 		telegramBot.Send(errorMsg)
 		return
 	}
 
 	// Save JPEG to temp file
-	tempFile, err := os.CreateTemp("", "group_photo_*.jpg")
+	photoPath, err := writeGroupPhotoTempFile(photoBytes)
 	if err != nil {
 		deleteMsg := tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID)
 		telegramBot.Request(deleteMsg)
@@ -594,26 +555,12 @@ func HandlePhotoUpload(photo *tgbotapi.PhotoSize, chatID int64, client *whatsmeo
 		return
 	}
 
-	_, err = tempFile.Write(buf.Bytes())
-	if err != nil {
-		tempFile.Close()
-		os.Remove(tempFile.Name())
-		deleteMsg := tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID)
-		telegramBot.Request(deleteMsg)
-
-		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error simpan foto: %v", err))
-		telegramBot.Send(errorMsg)
-		return
-	}
-
-	tempFile.Close()
-
-	state.PhotoPath = tempFile.Name()
+	state.PhotoPath = photoPath
 	state.WaitingForPhoto = false
 
 	// Log success
 	logger := utils.GetLogger()
-	logger.Info("Foto berhasil diproses: format=%s, size=%d bytes (resized to 640x640), path=%s", format, buf.Len(), tempFile.Name())
+	logger.Info("Foto berhasil diproses: format=%s, size=%d bytes (resized to 640x640), path=%s", format, len(photoBytes), photoPath)
 
 	// Delete processing message
 	deleteMsg := tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID)
@@ -623,25 +570,149 @@ func HandlePhotoUpload(photo *tgbotapi.PhotoSize, chatID int64, client *whatsmeo
 	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Foto diterima!\n\n🚀 Memulai proses ganti foto untuk %d grup...", len(state.SelectedGroups)))
 	telegramBot.Send(startMsg)
 
-	// Process in goroutine
-	go ProcessChangePhotos(state.SelectedGroups, state.DelaySeconds, state.PhotoPath, chatID, client, telegramBot)
+	// Process in goroutine; file foto dihapus jika proses batal dimulai (preflight dibatalkan/ditinggalkan)
+	StartWithAdminPreflightCleanup(chatID, "Ganti Foto", state.SelectedGroups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangePhotos(allowed, state.DelaySeconds, photoPath, chatID, client, telegramBot)
+	}, func() {
+		os.Remove(photoPath)
+	})
 
 	// Clear state
 	delete(groupPhotoStates, chatID)
 }
 
+// writeGroupPhotoTempFile menyimpan foto JPEG ke file sementara dan mengembalikan path-nya
+func writeGroupPhotoTempFile(photoBytes []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "group_photo_*.jpg")
+	if err != nil {
+		return "", err
+	}
+	defer tempFile.Close()
+
+	if _, err := tempFile.Write(photoBytes); err != nil {
+		os.Remove(tempFile.Name())
+		return "", err
+	}
+	return tempFile.Name(), nil
+}
+
 // ProcessChangePhotos memproses penggantian foto grup
 func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
-	defer os.Remove(photoPath) // Cleanup temp file
+	photoPaths := make(map[string]string, len(groups))
+	for _, group := range groups {
+		photoPaths[group.JID] = photoPath
+	}
+	ProcessChangePhotosMapped(groups, delay, photoPaths, chatID, client, telegramBot)
+}
+
+// ProcessChangePhotosMapped memproses penggantian foto grup dengan foto berbeda per grup
+// photoPaths memetakan JID grup ke path foto JPEG yang sudah di-resize; semua file dihapus setelah selesai
+func ProcessChangePhotosMapped(groups []GroupLinkInfo, delay int, photoPaths map[string]string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	// Cleanup temp files
+	defer func() {
+		removed := make(map[string]bool)
+		for _, photoPath := range photoPaths {
+			if !removed[photoPath] {
+				os.Remove(photoPath)
+				removed[photoPath] = true
+			}
+		}
+	}()
+
+	processGroupPhotoJob(groups, delay, "Ubah Foto", func(_ *whatsmeow.Client, group GroupLinkInfo, _ types.JID) ([]byte, string, error) {
+		photoBytes, err := os.ReadFile(photoPaths[group.JID])
+		if err != nil {
+			return nil, "", fmt.Errorf("error baca foto")
+		}
+		return photoBytes, "", nil
+	}, chatID, client, telegramBot)
+}
+
+// SetPhotoCropMode mengatur mode crop untuk foto yang akan dikirim
+func SetPhotoCropMode(mode string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupPhotoStates[chatID]
+	if state == nil || !state.WaitingForPhoto {
+		return
+	}
+
+	switch mode {
+	case "top":
+		state.PhotoOptions = PhotoOptions{CropMode: PhotoCropTop}
+	case "fit_white":
+		state.PhotoOptions = PhotoOptions{CropMode: PhotoCropFit, PadColor: &photoPadWhite}
+	case "fit_black":
+		state.PhotoOptions = PhotoOptions{CropMode: PhotoCropFit, PadColor: &photoPadBlack}
+	case "fit_auto":
+		state.PhotoOptions = PhotoOptions{CropMode: PhotoCropFit}
+	default:
+		state.PhotoOptions = PhotoOptions{CropMode: PhotoCropCenter}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("✂️ Mode crop: %s\n\n📸 Silakan kirim foto (atau file .zip untuk foto per grup).", state.PhotoOptions.Label()))
+	telegramBot.Send(msg)
+}
+
+// StartAvatarGeneration membuat avatar teks untuk grup terpilih yang belum punya foto
+func StartAvatarGeneration(useInitials bool, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := groupPhotoStates[chatID]
+	if state == nil || !state.WaitingForPhoto {
+		return
+	}
+	delete(groupPhotoStates, chatID)
+
+	style := "nama grup"
+	if useInitials {
+		style = "inisial"
+	}
+	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔤 Membuat avatar %s untuk %d grup...\n\nGrup yang sudah punya foto akan dilewati.", style, len(state.SelectedGroups)))
+	telegramBot.Send(startMsg)
 
+	StartWithAdminPreflight(chatID, "Generate Avatar", state.SelectedGroups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessGenerateAvatars(allowed, state.DelaySeconds, useInitials, chatID, client, telegramBot)
+	})
+}
+
+// ProcessGenerateAvatars memasang avatar teks (dirender lokal) ke grup yang belum punya foto
+func ProcessGenerateAvatars(groups []GroupLinkInfo, delay int, useInitials bool, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	processGroupPhotoJob(groups, delay, "Avatar Grup", func(validClient *whatsmeow.Client, group GroupLinkInfo, jid types.JID) ([]byte, string, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		info, err := validClient.GetProfilePictureInfo(ctx, jid, nil)
+		cancel()
+		if err == nil && info != nil {
+			return nil, "sudah punya foto", nil
+		}
+		if err != nil && !errors.Is(err, whatsmeow.ErrProfilePictureNotSet) {
+			return nil, "", fmt.Errorf("gagal cek foto: %v", err)
+		}
+
+		avatar, err := GenerateTextAvatar(group.Name, useInitials)
+		return avatar, "", err
+	}, chatID, client, telegramBot)
+}
+
+// groupPhotoSource menyiapkan foto untuk satu grup
+// Return skipReason tidak kosong jika grup sengaja dilewati (bukan gagal)
+type groupPhotoSource func(client *whatsmeow.Client, group GroupLinkInfo, jid types.JID) (photo []byte, skipReason string, err error)
+
+// processGroupPhotoJob menjalankan job ganti foto grup satu per satu dengan foto dari source
+func processGroupPhotoJob(groups []GroupLinkInfo, delay int, jobName string, source groupPhotoSource, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
 	totalGroups := len(groups)
 	successCount := 0
 	failedCount := 0
+	skippedCount := 0
 	var failedGroups []string
 
 	var progressMsgSent *tgbotapi.Message
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, jobName, groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// HIGH FIX: Ambil active client di setiap iterasi (upload foto = operasi SANGAT lambat!)
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessChangePhotos", i, totalGroups)
 		if shouldStop {
@@ -653,14 +724,6 @@ func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, ch
 			break
 		}
 
-		// Read photo file
-		photoBytes, err := os.ReadFile(photoPath)
-		if err != nil {
-			failedCount++
-			failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (error baca foto)", group.Name))
-			continue
-		}
-
 		// Parse JID
 		jid, err := parseJIDFromString(group.JID)
 		if err != nil {
@@ -669,16 +732,28 @@ func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, ch
 			continue
 		}
 
-		// Set group photo dengan validClient
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		_, err = validClient.SetGroupPhoto(ctx, jid, photoBytes)
-		cancel()
-
+		// Siapkan foto untuk grup ini
+		photoBytes, skipReason, err := source(validClient, group, jid)
 		if err != nil {
 			failedCount++
 			failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (%v)", group.Name, err))
+			continue
+		}
+
+		if skipReason != "" {
+			skippedCount++
 		} else {
-			successCount++
+			// Set group photo dengan validClient
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
+			_, err = validClient.SetGroupPhoto(ctx, jid, photoBytes)
+			cancel()
+
+			if err != nil {
+				failedCount++
+				failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (%v)", group.Name, err))
+			} else {
+				successCount++
+			}
 		}
 
 		// Show progress if more than 3 groups
@@ -707,9 +782,9 @@ func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, ch
 			}
 		}
 
-		// Delay
-		if delay > 0 && i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+		// Delay (grup yang dilewati tidak perlu delay)
+		if delay > 0 && i < len(groups)-1 && skipReason == "" {
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -719,8 +794,14 @@ func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, ch
 		telegramBot.Request(deleteMsg)
 	}
 
+	// Grup dilewati hanya terjadi di mode avatar (grup yang sudah punya foto)
+	skippedLine := ""
+	if skippedCount > 0 {
+		skippedLine = fmt.Sprintf("⏭️ **Dilewati:** %d grup (sudah punya foto)\n", skippedCount)
+	}
+
 	// Send final results
-	resultMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -729,9 +810,9 @@ func ProcessChangePhotos(groups []GroupLinkInfo, delay int, photoPath string, ch
 
 ✅ **Berhasil:** %d grup
 ❌ **Gagal:** %d grup
-⏱️ **Delay:** %d detik/grup
+%s⏱️ **Delay:** %d detik/grup
 
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, successCount, failedCount, delay)
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, successCount, failedCount, skippedLine, delay)
 
 	msg := tgbotapi.NewMessage(chatID, resultMsg)
 	msg.ParseMode = "Markdown"
@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"unicode"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+)
+
+// ZipPhotoState menyimpan state ganti foto per grup dari file ZIP
+type ZipPhotoState struct {
+	WaitingForDelay   bool
+	WaitingForZip     bool
+	WaitingForConfirm bool
+	DelaySeconds      int
+	PhotoOptions      PhotoOptions      // Mode crop untuk semua gambar di ZIP
+	Candidates        []GroupLinkInfo   // Grup yang boleh dicocokkan (nil = semua grup di database)
+	Groups            []GroupLinkInfo   // Grup yang mendapat foto (urutan proses)
+	PhotoPaths        map[string]string // JID grup -> path foto JPEG yang sudah di-resize
+}
+
+// ZipPhotoMatch adalah hasil pencocokan satu file gambar di ZIP ke grup
+type ZipPhotoMatch struct {
+	FileName   string
+	Group      GroupLinkInfo
+	Similarity int // Persentase kemiripan nama (100 = persis / JID)
+}
+
+var zipPhotoStates = make(map[int64]*ZipPhotoState)
+
+const (
+	// Kemiripan minimal (persen) agar nama file dianggap cocok dengan nama grup
+	zipPhotoMinSimilarity = 80
+	// Batas jumlah gambar dan ukuran per gambar di dalam ZIP
+	zipPhotoMaxImages    = 300
+	zipPhotoMaxImageSize = 5 * 1024 * 1024
+)
+
+// Ekstensi gambar yang didukung decoder (JPG, PNG, GIF, WEBP)
+var zipPhotoExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+// StartZipPhotoProcess memulai ganti foto per grup dari ZIP (dari menu, semua grup jadi kandidat)
+func StartZipPhotoProcess(telegramBot *tgbotapi.BotAPI, chatID int64) {
+	zipPhotoStates[chatID] = &ZipPhotoState{
+		WaitingForDelay: true,
+	}
+
+	promptMsg := `🗂️ **FOTO PER GRUP (ZIP)**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Setiap gambar di ZIP dipasang ke grup yang namanya sama dengan nama file.
+
+**📋 Contoh isi ZIP:**
+• Grup Alumni 2020.jpg
+• Grup Alumni 2021.png
+• 120363012345678901@g.us.webp
+
+Nama file boleh sedikit berbeda (huruf besar/kecil, spasi, tanda baca). Laporan pencocokan ditampilkan sebelum proses berjalan.
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏱️ Ketik delay antar grup (0-60 detik), contoh: 3`
+
+	msg := tgbotapi.NewMessage(chatID, promptMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_zip_photo"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleDelayInputForZipPhoto memproses input delay lalu meminta file ZIP
+func HandleDelayInputForZipPhoto(input string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := zipPhotoStates[chatID]
+	if state == nil || !state.WaitingForDelay {
+		return
+	}
+
+	var delay int
+	_, err := fmt.Sscanf(strings.TrimSpace(input), "%d", &delay)
+	if err != nil || delay < 0 || delay > 60 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Input tidak valid!\n\nDelay harus berupa angka antara 0-60 detik.\n\nContoh: 2, 5, 10")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	state.DelaySeconds = delay
+	state.WaitingForDelay = false
+	state.WaitingForZip = true
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("📦 **KIRIM FILE ZIP**\n\n⏱️ **Delay:** %d detik per grup\n\nKirim file `.zip` berisi gambar JPG/PNG/WEBP dengan nama file = nama grup atau JID grup.", delay))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_zip_photo"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// IsZipPhotoUpload mengecek apakah dokumen harus diproses sebagai ZIP foto per grup
+// ZIP diterima dari menu Foto per Grup, atau di langkah kirim foto pada alur ganti foto biasa
+// (grup yang dicocokkan dibatasi ke grup yang sudah dipilih)
+func IsZipPhotoUpload(chatID int64, fileName string) bool {
+	if !strings.HasSuffix(strings.ToLower(fileName), ".zip") {
+		return false
+	}
+	if state := zipPhotoStates[chatID]; state != nil && state.WaitingForZip {
+		return true
+	}
+	return GetPhotoInputType(chatID) == "photo"
+}
+
+// HandleZipPhotoUpload mengunduh ZIP, mencocokkan gambar ke grup dan menampilkan laporan pencocokan
+func HandleZipPhotoUpload(fileID string, chatID int64, telegramBot *tgbotapi.BotAPI, botToken string) {
+	state := zipPhotoStates[chatID]
+	if state == nil || !state.WaitingForZip {
+		// Dari alur ganti foto biasa: pakai grup terpilih dan delay yang sudah diatur
+		photoState := groupPhotoStates[chatID]
+		if photoState == nil || !photoState.WaitingForPhoto {
+			return
+		}
+		state = &ZipPhotoState{
+			WaitingForZip: true,
+			DelaySeconds:  photoState.DelaySeconds,
+			PhotoOptions:  photoState.PhotoOptions,
+			Candidates:    photoState.SelectedGroups,
+		}
+		zipPhotoStates[chatID] = state
+		delete(groupPhotoStates, chatID)
+	}
+
+	processingMsgSent, _ := telegramBot.Send(tgbotapi.NewMessage(chatID, "⏳ Mengunduh dan membaca file ZIP..."))
+	defer telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, processingMsgSent.MessageID))
+
+	zipData, err := downloadZipPhotoFile(fileID, botToken)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengunduh file ZIP")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	candidates := state.Candidates
+	if candidates == nil {
+		groupsMap, err := utils.GetAllGroupsFromDB()
+		if err != nil {
+			errorMsg := utils.FormatUserError(utils.ErrorDatabase, err, "Gagal membaca daftar grup")
+			msg := tgbotapi.NewMessage(chatID, errorMsg)
+			msg.ParseMode = "Markdown"
+			telegramBot.Send(msg)
+			return
+		}
+		for _, group := range utils.SortGroupsNaturally(groupsMap) {
+			candidates = append(candidates, GroupLinkInfo{JID: group.JID, Name: group.Name})
+		}
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ File ZIP tidak valid: %v", err)))
+		return
+	}
+
+	var matches []ZipPhotoMatch
+	var problems []string
+	photoPaths := make(map[string]string)
+	matchedBy := make(map[string]string) // JID -> nama file pertama yang cocok
+	imageCount := 0
+
+	for _, entry := range reader.File {
+		fileName := path.Base(entry.Name)
+		extension := strings.ToLower(path.Ext(fileName))
+		if entry.FileInfo().IsDir() || strings.HasPrefix(entry.Name, "__MACOSX/") || strings.HasPrefix(fileName, ".") {
+			continue
+		}
+		if !zipPhotoExtensions[extension] {
+			problems = append(problems, fmt.Sprintf("⚠️ %s - bukan gambar, dilewati", fileName))
+			continue
+		}
+
+		imageCount++
+		if imageCount > zipPhotoMaxImages {
+			problems = append(problems, fmt.Sprintf("⚠️ Maksimal %d gambar per ZIP, sisanya dilewati", zipPhotoMaxImages))
+			break
+		}
+
+		match, ok := MatchPhotoFileToGroup(strings.TrimSuffix(fileName, path.Ext(fileName)), candidates)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("❓ %s - tidak ada grup yang cocok", fileName))
+			continue
+		}
+		match.FileName = fileName
+		if firstFile, duplicate := matchedBy[match.Group.JID]; duplicate {
+			problems = append(problems, fmt.Sprintf("⚠️ %s - grup %s sudah dapat foto dari %s", fileName, match.Group.Name, firstFile))
+			continue
+		}
+		if entry.UncompressedSize64 > zipPhotoMaxImageSize {
+			problems = append(problems, fmt.Sprintf("⚠️ %s - ukuran lebih dari 5MB", fileName))
+			continue
+		}
+
+		photoPath, err := extractZipPhoto(entry, state.PhotoOptions)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("❌ %s - %v", fileName, err))
+			continue
+		}
+
+		matchedBy[match.Group.JID] = fileName
+		photoPaths[match.Group.JID] = photoPath
+		matches = append(matches, match)
+	}
+
+	cleanupZipPhotoState(state)
+	state.WaitingForZip = false
+	state.PhotoPaths = photoPaths
+	state.Groups = nil
+	for _, match := range matches {
+		state.Groups = append(state.Groups, match.Group)
+	}
+
+	sendZipPhotoReport(chatID, state, matches, problems, candidates, telegramBot)
+}
+
+// downloadZipPhotoFile mengunduh file ZIP dari server Telegram
+func downloadZipPhotoFile(fileID, botToken string) ([]byte, error) {
+	fileURL := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", botToken, fileID)
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var fileResp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			FilePath string `json:"file_path"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&fileResp); err != nil || !fileResp.OK {
+		return nil, fmt.Errorf("gagal mengambil informasi file (maksimal 20MB)")
+	}
+
+	downloadURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", botToken, fileResp.Result.FilePath)
+	contentResp, err := http.Get(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	defer contentResp.Body.Close()
+
+	return io.ReadAll(contentResp.Body)
+}
+
+// extractZipPhoto membaca gambar dari ZIP, memprosesnya dengan pipeline resize yang sama
+// dengan ganti foto biasa, lalu menyimpannya ke file sementara
+func extractZipPhoto(entry *zip.File, opts PhotoOptions) (string, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return "", fmt.Errorf("gagal membuka file: %v", err)
+	}
+	defer rc.Close()
+
+	imgData, err := io.ReadAll(io.LimitReader(rc, zipPhotoMaxImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("gagal membaca file: %v", err)
+	}
+	if len(imgData) > zipPhotoMaxImageSize {
+		return "", fmt.Errorf("ukuran lebih dari 5MB")
+	}
+
+	photoBytes, _, err := preparePhotoWithOptions(imgData, opts)
+	if err != nil {
+		return "", fmt.Errorf("gambar tidak valid: %v", err)
+	}
+	return writeGroupPhotoTempFile(photoBytes)
+}
+
+// MatchPhotoFileToGroup mencocokkan nama file (tanpa ekstensi) ke grup
+// Urutan: JID persis, nama persis (tanpa beda huruf/tanda baca), lalu kemiripan nama tertinggi
+// Kecocokan yang ambigu (dua grup dengan kemiripan sama) dianggap tidak cocok
+func MatchPhotoFileToGroup(baseName string, groups []GroupLinkInfo) (ZipPhotoMatch, bool) {
+	trimmed := strings.TrimSpace(baseName)
+	for _, group := range groups {
+		if trimmed == group.JID || trimmed+"@g.us" == group.JID {
+			return ZipPhotoMatch{Group: group, Similarity: 100}, true
+		}
+	}
+
+	normalizedFile := normalizePhotoMatchName(trimmed)
+	if normalizedFile == "" {
+		return ZipPhotoMatch{}, false
+	}
+
+	var best ZipPhotoMatch
+	ambiguous := false
+	for _, group := range groups {
+		similarity := nameSimilarity(normalizedFile, normalizePhotoMatchName(group.Name))
+		if similarity > best.Similarity {
+			best = ZipPhotoMatch{Group: group, Similarity: similarity}
+			ambiguous = false
+		} else if similarity == best.Similarity && similarity > 0 {
+			ambiguous = true
+		}
+	}
+
+	if best.Similarity < zipPhotoMinSimilarity || ambiguous {
+		return ZipPhotoMatch{}, false
+	}
+	return best, true
+}
+
+// normalizePhotoMatchName menyamakan huruf, spasi dan tanda baca agar "grup_alumni-2020" = "Grup Alumni 2020"
+func normalizePhotoMatchName(name string) string {
+	var builder strings.Builder
+	lastSpace := true
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			lastSpace = false
+		} else if !lastSpace {
+			builder.WriteRune(' ')
+			lastSpace = true
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// nameSimilarity menghitung kemiripan dua nama (0-100) berdasarkan jarak Levenshtein
+func nameSimilarity(a, b string) int {
+	if a == b {
+		return 100
+	}
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(min(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return (longest - previous[len(rb)]) * 100 / longest
+}
+
+// sendZipPhotoReport mengirim laporan pencocokan file ke grup dan tombol konfirmasi
+func sendZipPhotoReport(chatID int64, state *ZipPhotoState, matches []ZipPhotoMatch, problems []string, candidates []GroupLinkInfo, telegramBot *tgbotapi.BotAPI) {
+	var report strings.Builder
+	report.WriteString("🗂️ LAPORAN PENCOCOKAN FOTO\n\n")
+	report.WriteString(fmt.Sprintf("✅ Cocok: %d grup\n", len(matches)))
+	report.WriteString(fmt.Sprintf("⚠️ Bermasalah: %d file\n", len(problems)))
+	report.WriteString(fmt.Sprintf("⏱️ Delay: %d detik/grup\n\n", state.DelaySeconds))
+
+	// Tampilkan kecocokan tidak persis lebih dulu agar mudah dicek
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Similarity < matches[j].Similarity
+	})
+	for _, match := range matches {
+		if match.Similarity < 100 {
+			report.WriteString(fmt.Sprintf("🔸 %s → %s (mirip %d%%)\n", match.FileName, match.Group.Name, match.Similarity))
+		} else {
+			report.WriteString(fmt.Sprintf("✅ %s → %s\n", match.FileName, match.Group.Name))
+		}
+	}
+	for _, problem := range problems {
+		report.WriteString(problem + "\n")
+	}
+
+	// Grup terpilih yang tidak mendapat foto (hanya relevan jika kandidat dibatasi)
+	if state.Candidates != nil {
+		var missing []string
+		for _, group := range candidates {
+			if _, ok := state.PhotoPaths[group.JID]; !ok {
+				missing = append(missing, group.Name)
+			}
+		}
+		if len(missing) > 0 {
+			report.WriteString(fmt.Sprintf("\n📭 %d grup terpilih tanpa foto: %s\n", len(missing), strings.Join(missing, ", ")))
+		}
+	}
+
+	reportText := report.String()
+	if len([]rune(reportText)) > 3500 {
+		// Laporan panjang dikirim sebagai file agar tidak melebihi batas pesan Telegram
+		doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: "laporan_foto_zip.txt", Bytes: []byte(reportText)})
+		doc.Caption = fmt.Sprintf("🗂️ Laporan pencocokan: %d grup cocok, %d file bermasalah", len(matches), len(problems))
+		telegramBot.Send(doc)
+		reportText = fmt.Sprintf("🗂️ Laporan lengkap ada di file.\n\n✅ Cocok: %d grup\n⚠️ Bermasalah: %d file\n", len(matches), len(problems))
+	}
+
+	if len(matches) == 0 {
+		delete(zipPhotoStates, chatID)
+		telegramBot.Send(tgbotapi.NewMessage(chatID, reportText+"\n❌ Tidak ada gambar yang cocok dengan grup. Periksa nama file lalu coba lagi."))
+		return
+	}
+
+	state.WaitingForConfirm = true
+	msg := tgbotapi.NewMessage(chatID, reportText+fmt.Sprintf("\nLanjutkan ganti foto untuk %d grup?", len(matches)))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Proses", "zip_photo_apply"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_zip_photo"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// ApplyZipPhotos menjalankan job ganti foto per grup setelah laporan dikonfirmasi
+func ApplyZipPhotos(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := zipPhotoStates[chatID]
+	if state == nil || !state.WaitingForConfirm {
+		return
+	}
+	delete(zipPhotoStates, chatID)
+
+	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🚀 Memulai proses ganti foto untuk %d grup (foto berbeda per grup)...", len(state.Groups)))
+	telegramBot.Send(startMsg)
+
+	StartWithAdminPreflightCleanup(chatID, "Ganti Foto (ZIP)", state.Groups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessChangePhotosMapped(allowed, state.DelaySeconds, state.PhotoPaths, chatID, client, telegramBot)
+	}, func() {
+		// Preflight dibatalkan/ditinggalkan: hapus semua foto hasil ekstrak ZIP
+		cleanupZipPhotoState(state)
+	})
+}
+
+// cleanupZipPhotoState menghapus file sementara hasil ekstrak ZIP sebelumnya
+func cleanupZipPhotoState(state *ZipPhotoState) {
+	for _, photoPath := range state.PhotoPaths {
+		os.Remove(photoPath)
+	}
+	state.PhotoPaths = nil
+}
+
+// CancelZipPhoto membatalkan proses foto per grup dari ZIP
+func CancelZipPhoto(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	if state := zipPhotoStates[chatID]; state != nil {
+		cleanupZipPhotoState(state)
+		delete(zipPhotoStates, chatID)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, "❌ Proses foto per grup (ZIP) dibatalkan.")
+	telegramBot.Send(msg)
+}
+
+// IsWaitingForZipPhotoInput mengecek apakah user sedang di proses foto per grup dari ZIP
+func IsWaitingForZipPhotoInput(chatID int64) bool {
+	state := zipPhotoStates[chatID]
+	return state != nil && (state.WaitingForDelay || state.WaitingForZip)
+}
+
+// GetZipPhotoInputType mengembalikan jenis input yang sedang ditunggu
+func GetZipPhotoInputType(chatID int64) string {
+	state := zipPhotoStates[chatID]
+	if state == nil {
+		return ""
+	}
+	if state.WaitingForDelay {
+		return "delay"
+	}
+	if state.WaitingForZip {
+		return "zip"
+	}
+	return ""
+}
@@ -0,0 +1,666 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// CommunityState menyimpan state menu komunitas per chat
+// Daftar komunitas disimpan agar callback cukup memakai index; callback subgrup memakai JID agar
+// tombol dari pesan lama tidak salah sasaran setelah daftar subgrup berubah
+type CommunityState struct {
+	WaitingForCreate bool
+	WaitingForLink   bool
+	Communities      []GroupLinkInfo // Daftar komunitas di menu terakhir
+	Current          GroupLinkInfo   // Komunitas yang sedang dibuka
+	SubGroups        []GroupLinkInfo // Subgrup komunitas yang sedang dibuka (tanpa grup pengumuman)
+}
+
+var communityStates = make(map[int64]*CommunityState)
+
+const (
+	// Jeda antar grup saat menautkan beberapa grup sekaligus
+	communityLinkDelay = 2 * time.Second
+	// Maksimal grup yang ditautkan dalam satu input
+	communityLinkMaxGroups = 50
+)
+
+// getCommunityState mengambil (atau membuat) state komunitas untuk chat
+func getCommunityState(chatID int64) *CommunityState {
+	state := communityStates[chatID]
+	if state == nil {
+		state = &CommunityState{}
+		communityStates[chatID] = state
+	}
+	return state
+}
+
+// groupCommunitiesFromJoined mengambil data komunitas dari hasil GetJoinedGroups
+func groupCommunitiesFromJoined(joinedGroups []*types.GroupInfo) []utils.GroupCommunity {
+	var communities []utils.GroupCommunity
+	for _, group := range joinedGroups {
+		if group == nil {
+			continue
+		}
+		if !group.IsParent && group.LinkedParentJID.IsEmpty() {
+			continue
+		}
+		communities = append(communities, utils.GroupCommunity{
+			GroupJID:       group.JID.String(),
+			IsCommunity:    group.IsParent,
+			ParentJID:      linkedParentString(group.LinkedParentJID),
+			IsAnnouncement: group.IsDefaultSubGroup,
+		})
+	}
+	return communities
+}
+
+// linkedParentString mengubah JID komunitas induk ke string ("" jika tidak tertaut)
+func linkedParentString(jid types.JID) string {
+	if jid.IsEmpty() {
+		return ""
+	}
+	return jid.String()
+}
+
+// cacheGroupCommunities menyimpan keanggotaan komunitas semua grup hasil GetJoinedGroups
+func cacheGroupCommunities(joinedGroups []*types.GroupInfo) {
+	if err := utils.ReplaceGroupCommunities(groupCommunitiesFromJoined(joinedGroups)); err != nil {
+		utils.GetGrupLogger().Warn("cacheGroupCommunities: Gagal menyimpan data komunitas: %v", err)
+	}
+}
+
+// refreshGroupCommunities memperbarui data komunitas (dan nama grup) langsung dari WhatsApp
+func refreshGroupCommunities(client *whatsmeow.Client) error {
+	if client == nil || !client.IsConnected() {
+		return fmt.Errorf("client WhatsApp tidak terhubung")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	joinedGroups, err := client.GetJoinedGroups(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	groupsToSave := make(map[string]string)
+	for _, group := range joinedGroups {
+		if group != nil && group.Name != "" {
+			groupsToSave[group.JID.String()] = group.Name
+		}
+	}
+	if len(groupsToSave) > 0 {
+		if err := utils.BatchSaveGroupsToDB(groupsToSave); err != nil {
+			utils.GetGrupLogger().Warn("refreshGroupCommunities: Gagal menyimpan nama grup: %v", err)
+		}
+	}
+
+	cacheGroupCapabilities(client, joinedGroups)
+	cacheGroupCommunities(joinedGroups)
+	return nil
+}
+
+// communityGroupLabel mengembalikan peran grup di komunitas (komunitas/pengumuman/subgrup) dan nama komunitasnya
+// Return role "" jika grup tidak terkait komunitas
+func communityGroupLabel(groupJID string, communities map[string]utils.GroupCommunity, names map[string]string) (role, community string) {
+	c, ok := communities[groupJID]
+	if !ok {
+		return "", ""
+	}
+	if c.IsCommunity {
+		return "komunitas", names[groupJID]
+	}
+	if c.ParentJID == "" {
+		return "", ""
+	}
+	community = names[c.ParentJID]
+	if community == "" {
+		community = c.ParentJID
+	}
+	if c.IsAnnouncement {
+		return "pengumuman", community
+	}
+	return "subgrup", community
+}
+
+// ShowCommunityMenu menampilkan daftar komunitas akun (messageID 0 = kirim pesan baru)
+func ShowCommunityMenu(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := getCommunityState(chatID)
+	state.WaitingForCreate = false
+	state.WaitingForLink = false
+
+	refreshErr := refreshGroupCommunities(client)
+	communities, err := utils.GetGroupCommunities()
+	if err != nil {
+		refreshErr = err
+	}
+	names, _ := utils.GetAllGroupsFromDB()
+
+	subCounts := make(map[string]int)
+	state.Communities = nil
+	for jid, c := range communities {
+		if c.IsCommunity {
+			name := names[jid]
+			if name == "" {
+				name = jid
+			}
+			state.Communities = append(state.Communities, GroupLinkInfo{JID: jid, Name: name})
+		} else if c.ParentJID != "" && !c.IsAnnouncement {
+			subCounts[c.ParentJID]++
+		}
+	}
+	sort.Slice(state.Communities, func(i, j int) bool {
+		return utils.NaturalLess(state.Communities[i].Name, state.Communities[j].Name)
+	})
+
+	var text strings.Builder
+	text.WriteString("🏘️ KOMUNITAS WHATSAPP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Buat komunitas, tautkan grup yang sudah ada ke komunitas, lihat subgrup dan grup pengumuman, atau lepas grup dari komunitas.\n\n")
+	if refreshErr != nil {
+		text.WriteString(fmt.Sprintf("⚠️ Data mungkin belum terbaru: %v\n\n", refreshErr))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(state.Communities) == 0 {
+		text.WriteString("Akun ini belum tergabung di komunitas mana pun.")
+	}
+	for i, community := range state.Communities {
+		text.WriteString(fmt.Sprintf("%d. %s\n   👥 %d subgrup\n", i+1, community.Name, subCounts[community.JID]))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(truncateButtonLabel(fmt.Sprintf("🏘️ %d. %s", i+1, community.Name)), fmt.Sprintf("community_view_%d", i)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Buat Komunitas", "community_create"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text.String())
+		msg.ReplyMarkup = keyboard
+		telegramBot.Send(msg)
+		return
+	}
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartCommunityCreate meminta input nama (dan deskripsi opsional) komunitas baru
+func StartCommunityCreate(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := getCommunityState(chatID)
+	state.WaitingForCreate = true
+	state.WaitingForLink = false
+
+	promptMsg := `🏘️ **BUAT KOMUNITAS**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Format:**
+Baris 1: nama komunitas
+Baris berikutnya (opsional): deskripsi
+
+**Contoh:**
+` + "`Komunitas Alumni\nWadah informasi alumni angkatan 2020`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Grup pengumuman komunitas dibuat otomatis oleh WhatsApp.
+
+⏳ Menunggu input...`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "community_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartCommunityLink meminta input daftar grup yang akan ditautkan ke komunitas yang sedang dibuka
+func StartCommunityLink(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	if state == nil || state.Current.JID == "" {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Komunitas tidak ditemukan. Buka ulang menu komunitas."))
+		return
+	}
+	state.WaitingForLink = true
+	state.WaitingForCreate = false
+
+	promptMsg := fmt.Sprintf(`🔗 TAUTKAN GRUP KE KOMUNITAS
+
+🏘️ Komunitas: %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Kirim nama grup yang akan ditautkan (satu per baris, nama persis).
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⚠️ Akun ini harus admin di komunitas dan di grup yang ditautkan. Grup yang sudah tertaut ke komunitas lain dilewati.
+💡 Maksimal %d grup per input.
+
+⏳ Menunggu input...`, state.Current.Name, communityLinkMaxGroups)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "community_back"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleCommunityInput memproses input teks menu komunitas (buat komunitas / tautkan grup)
+func HandleCommunityInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	if state == nil {
+		return
+	}
+	if client == nil || client.Store.ID == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	if state.WaitingForCreate {
+		handleCommunityCreateInput(input, chatID, client, telegramBot)
+	} else if state.WaitingForLink {
+		handleCommunityLinkInput(input, state, chatID, client, telegramBot)
+	}
+}
+
+// handleCommunityCreateInput membuat komunitas baru dari input nama + deskripsi
+func handleCommunityCreateInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	name, description, _ := strings.Cut(strings.TrimSpace(input), "\n")
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+	if name == "" {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Nama komunitas tidak boleh kosong!"))
+		return
+	}
+	if utf8.RuneCountInString(name) > maxGroupNameLength {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Nama komunitas maksimal %d karakter!", maxGroupNameLength)))
+		return
+	}
+	communityStates[chatID].WaitingForCreate = false
+
+	go createCommunity(name, description, chatID, client, telegramBot)
+}
+
+// createCommunity membuat komunitas (grup induk) lalu mengatur deskripsi dan membuka detailnya
+func createCommunity(name, description string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	loadingMsg, _ := telegramBot.Send(tgbotapi.NewMessage(chatID, "⏳ Membuat komunitas..."))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	info, err := client.CreateGroup(ctx, whatsmeow.ReqCreateGroup{
+		Name:        name,
+		GroupParent: types.GroupParent{IsParent: true},
+	})
+	cancel()
+	telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, loadingMsg.MessageID))
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorUnknown, err, "Gagal membuat komunitas"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	communityJID := info.JID.String()
+	utils.SaveGroupToDB(communityJID, name)
+	utils.SaveGroupCommunity(utils.GroupCommunity{GroupJID: communityJID, IsCommunity: true})
+
+	result := fmt.Sprintf("✅ Komunitas \"%s\" berhasil dibuat!", name)
+	if description != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := client.SetGroupDescription(ctx, info.JID, description)
+		cancel()
+		if err != nil {
+			result += fmt.Sprintf("\n⚠️ Deskripsi gagal diatur: %v", err)
+		}
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, result))
+	utils.LogActivity("community_create", fmt.Sprintf("Created community %s (%s)", name, communityJID), chatID)
+
+	state := getCommunityState(chatID)
+	state.Current = GroupLinkInfo{JID: communityJID, Name: name}
+	ShowCommunityDetail(chatID, 0, client, telegramBot)
+}
+
+// resolveCommunityLinkTargets mencari grup dari input nama (satu per baris, nama persis)
+func resolveCommunityLinkTargets(input string) ([]GroupLinkInfo, []string) {
+	var groups []GroupLinkInfo
+	var notFound []string
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		matches, err := utils.SearchGroupsExact(line)
+		if err != nil || len(matches) != 1 {
+			notFound = append(notFound, line)
+			continue
+		}
+		for jid, name := range matches {
+			if !seen[jid] {
+				seen[jid] = true
+				groups = append(groups, GroupLinkInfo{JID: jid, Name: name})
+			}
+		}
+	}
+	return groups, notFound
+}
+
+// handleCommunityLinkInput menautkan grup dari input ke komunitas yang sedang dibuka
+func handleCommunityLinkInput(input string, state *CommunityState, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	groups, notFound := resolveCommunityLinkTargets(input)
+	if len(groups) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tidak ada grup yang ditemukan. Gunakan nama grup yang persis, satu per baris."))
+		return
+	}
+	if len(groups) > communityLinkMaxGroups {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Maksimal %d grup per input!", communityLinkMaxGroups)))
+		return
+	}
+	state.WaitingForLink = false
+
+	community := state.Current
+	go processCommunityLink(community, groups, notFound, chatID, client, telegramBot)
+}
+
+// processCommunityLink menjalankan LinkGroup satu per satu lalu mengirim laporan
+func processCommunityLink(community GroupLinkInfo, groups []GroupLinkInfo, notFound []string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	communityJID, err := types.ParseJID(community.JID)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ JID komunitas tidak valid: %v", err)))
+		return
+	}
+
+	communities, _ := utils.GetGroupCommunities()
+	progressMsg, _ := telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("⏳ Menautkan %d grup ke %s...", len(groups), community.Name)))
+
+	var linked, skipped, failed []string
+	for i, group := range groups {
+		if c, ok := communities[group.JID]; ok {
+			if c.IsCommunity {
+				skipped = append(skipped, fmt.Sprintf("%s (grup ini adalah komunitas)", group.Name))
+				continue
+			}
+			if c.ParentJID == community.JID {
+				skipped = append(skipped, fmt.Sprintf("%s (sudah tertaut)", group.Name))
+				continue
+			}
+			if c.ParentJID != "" {
+				skipped = append(skipped, fmt.Sprintf("%s (tertaut ke komunitas lain)", group.Name))
+				continue
+			}
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "processCommunityLink", i, len(groups))
+		if shouldStop {
+			failed = append(failed, fmt.Sprintf("%s (client terputus)", group.Name))
+			break
+		}
+
+		groupJID, err := types.ParseJID(group.JID)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err = validClient.LinkGroup(ctx, communityJID, groupJID)
+			cancel()
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", group.Name, err))
+		} else {
+			linked = append(linked, group.Name)
+			utils.SaveGroupCommunity(utils.GroupCommunity{GroupJID: group.JID, ParentJID: community.JID})
+		}
+
+		if i < len(groups)-1 {
+			time.Sleep(communityLinkDelay)
+		}
+	}
+
+	telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, progressMsg.MessageID))
+
+	var report strings.Builder
+	report.WriteString(fmt.Sprintf("🔗 HASIL TAUTKAN GRUP\n\n🏘️ Komunitas: %s\n\n", community.Name))
+	report.WriteString(fmt.Sprintf("✅ Ditautkan: %d\n⏭️ Dilewati: %d\n❌ Gagal: %d\n", len(linked), len(skipped), len(failed)))
+	if len(notFound) > 0 {
+		report.WriteString(fmt.Sprintf("🔍 Tidak ditemukan: %d\n", len(notFound)))
+	}
+	for _, section := range []struct {
+		title string
+		items []string
+	}{
+		{"⏭️ Dilewati", skipped},
+		{"❌ Gagal", failed},
+		{"🔍 Tidak ditemukan / nama tidak unik", notFound},
+	} {
+		if len(section.items) == 0 {
+			continue
+		}
+		report.WriteString(fmt.Sprintf("\n%s:\n", section.title))
+		for _, item := range section.items {
+			report.WriteString("• " + item + "\n")
+		}
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, report.String()))
+
+	utils.LogActivity("community_link", fmt.Sprintf("Linked %d groups to community %s: %d skipped, %d failed", len(linked), community.JID, len(skipped), len(failed)), chatID)
+
+	ShowCommunityDetail(chatID, 0, client, telegramBot)
+}
+
+// OpenCommunity membuka detail komunitas dari index daftar di menu
+func OpenCommunity(index int, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	if state == nil || index < 0 || index >= len(state.Communities) {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Daftar komunitas sudah berubah. Buka ulang menu komunitas."))
+		return
+	}
+	state.Current = state.Communities[index]
+	ShowCommunityDetail(chatID, messageID, client, telegramBot)
+}
+
+// ShowCommunityDetail menampilkan subgrup dan grup pengumuman komunitas yang sedang dibuka (messageID 0 = pesan baru)
+func ShowCommunityDetail(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	if state == nil || state.Current.JID == "" {
+		return
+	}
+	state.WaitingForLink = false
+	community := state.Current
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🏘️ KOMUNITAS: %s\n\n", community.Name))
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	state.SubGroups = nil
+	var announcement []string
+	subGroups, err := fetchCommunitySubGroups(client, community.JID)
+	if err != nil {
+		text.WriteString(fmt.Sprintf("❌ Gagal mengambil subgrup: %v\n", err))
+	} else {
+		for _, sub := range subGroups {
+			name := sub.Name
+			if name == "" {
+				name = sub.JID.String()
+			}
+			if sub.IsDefaultSubGroup {
+				announcement = append(announcement, name)
+			} else {
+				state.SubGroups = append(state.SubGroups, GroupLinkInfo{JID: sub.JID.String(), Name: name})
+			}
+		}
+		sort.Slice(state.SubGroups, func(i, j int) bool {
+			return utils.NaturalLess(state.SubGroups[i].Name, state.SubGroups[j].Name)
+		})
+
+		if len(announcement) > 0 {
+			text.WriteString(fmt.Sprintf("📢 Grup pengumuman: %s\n\n", strings.Join(announcement, ", ")))
+		} else {
+			text.WriteString("📢 Grup pengumuman: -\n\n")
+		}
+		text.WriteString(fmt.Sprintf("👥 Subgrup (%d):\n", len(state.SubGroups)))
+		if len(state.SubGroups) == 0 {
+			text.WriteString("Belum ada grup yang ditautkan.\n")
+		}
+		for i, sub := range state.SubGroups {
+			text.WriteString(fmt.Sprintf("%d. %s\n", i+1, sub.Name))
+		}
+		text.WriteString("\n💡 Klik ✂️ untuk melepas grup dari komunitas.")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, sub := range state.SubGroups {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(truncateButtonLabel(fmt.Sprintf("✂️ Lepas: %s", sub.Name)), "community_unlink_"+sub.JID),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔗 Tautkan Grup", "community_link"),
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Refresh", "community_back"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Daftar Komunitas", "community_menu"),
+		),
+	)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+
+	if messageID == 0 {
+		msg := tgbotapi.NewMessage(chatID, text.String())
+		msg.ReplyMarkup = keyboard
+		telegramBot.Send(msg)
+		return
+	}
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// fetchCommunitySubGroups mengambil subgrup komunitas dan memperbarui data komunitas di database
+func fetchCommunitySubGroups(client *whatsmeow.Client, communityJID string) ([]*types.GroupLinkTarget, error) {
+	if client == nil || !client.IsConnected() {
+		return nil, fmt.Errorf("client WhatsApp tidak terhubung")
+	}
+	jid, err := types.ParseJID(communityJID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	subGroups, err := client.GetSubGroups(ctx, jid)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	// Hanya tabel komunitas yang diperbarui: subgrup bisa berisi grup yang tidak diikuti akun,
+	// jadi tidak boleh masuk tabel groups (target operasi massal)
+	for _, sub := range subGroups {
+		utils.SaveGroupCommunity(utils.GroupCommunity{
+			GroupJID:       sub.JID.String(),
+			ParentJID:      communityJID,
+			IsAnnouncement: sub.IsDefaultSubGroup,
+		})
+	}
+	return subGroups, nil
+}
+
+// findCommunitySubGroup mencari subgrup berdasarkan JID di komunitas yang sedang dibuka
+func findCommunitySubGroup(state *CommunityState, subJID string) (GroupLinkInfo, bool) {
+	if state == nil {
+		return GroupLinkInfo{}, false
+	}
+	for _, sub := range state.SubGroups {
+		if sub.JID == subJID {
+			return sub, true
+		}
+	}
+	return GroupLinkInfo{}, false
+}
+
+// ConfirmCommunityUnlink meminta konfirmasi sebelum subgrup dilepas dari komunitas yang sedang dibuka
+func ConfirmCommunityUnlink(subJID string, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	sub, found := findCommunitySubGroup(state, subJID)
+	if !found {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Daftar subgrup sudah berubah. Buka ulang komunitas."))
+		return
+	}
+
+	text := fmt.Sprintf("⚠️ KONFIRMASI LEPAS SUBGRUP\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n👥 Grup: %s\n🏘️ Komunitas: %s\n\nGrup akan dilepas dari komunitas. Anggota grup tetap ada, tapi grup tidak lagi menerima pengumuman komunitas.\n\nLanjutkan?", sub.Name, state.Current.Name)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✂️ Ya, Lepas", "community_unlink_ok_"+sub.JID),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "community_back"),
+		),
+	)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// UnlinkCommunitySubGroup melepas satu subgrup (berdasarkan JID) dari komunitas yang sedang dibuka setelah dikonfirmasi
+func UnlinkCommunitySubGroup(subJID string, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := communityStates[chatID]
+	sub, found := findCommunitySubGroup(state, subJID)
+	if !found {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Daftar subgrup sudah berubah. Buka ulang komunitas."))
+		return
+	}
+	if client == nil || client.Store.ID == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	communityJID, err := types.ParseJID(state.Current.JID)
+	var childJID types.JID
+	if err == nil {
+		childJID, err = types.ParseJID(sub.JID)
+	}
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err = client.UnlinkGroup(ctx, communityJID, childJID)
+		cancel()
+	}
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal melepas %s dari komunitas: %v", sub.Name, err)))
+		return
+	}
+
+	utils.DeleteGroupCommunity(sub.JID)
+	utils.LogActivity("community_unlink", fmt.Sprintf("Unlinked %s from community %s", sub.JID, state.Current.JID), chatID)
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("✂️ %s dilepas dari komunitas %s.", sub.Name, state.Current.Name)))
+	ShowCommunityDetail(chatID, messageID, client, telegramBot)
+}
+
+// IsWaitingForCommunityInput mengecek apakah user sedang mengisi input komunitas
+func IsWaitingForCommunityInput(chatID int64) bool {
+	state := communityStates[chatID]
+	return state != nil && (state.WaitingForCreate || state.WaitingForLink)
+}
@@ -71,6 +71,10 @@ Fitur ini akan membuat grup WhatsApp secara otomatis dengan pengaturan yang Anda
   GRUP
   GRUP
 
+**Provisioning dari CSV/JSON**
+• Satu baris = satu grup dengan deskripsi, foto, pengaturan, anggota dan admin sendiri
+• Hasil (JID + link) dikirim sebagai CSV
+
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 **📝 Alur Proses:**
@@ -92,6 +96,9 @@ Fitur ini akan membuat grup WhatsApp secara otomatis dengan pengaturan yang Anda
 			tgbotapi.NewInlineKeyboardButtonData("1️⃣ Opsi 1: Nama + Jumlah", "create_group_mode_single"),
 			tgbotapi.NewInlineKeyboardButtonData("2️⃣ Opsi 2: Multi-line", "create_group_mode_multiline"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📦 Provisioning dari CSV/JSON", "provision_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
 		),
@@ -124,6 +131,10 @@ Fitur ini akan membuat grup WhatsApp secara otomatis dengan pengaturan yang Anda
   GRUP
   GRUP
 
+**Provisioning dari CSV/JSON**
+• Satu baris = satu grup dengan deskripsi, foto, pengaturan, anggota dan admin sendiri
+• Hasil (JID + link) dikirim sebagai CSV
+
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 **📝 Alur Proses:**
@@ -145,6 +156,9 @@ Fitur ini akan membuat grup WhatsApp secara otomatis dengan pengaturan yang Anda
 			tgbotapi.NewInlineKeyboardButtonData("1️⃣ Opsi 1: Nama + Jumlah", "create_group_mode_single"),
 			tgbotapi.NewInlineKeyboardButtonData("2️⃣ Opsi 2: Multi-line", "create_group_mode_multiline"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📦 Provisioning dari CSV/JSON", "provision_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
 		),
@@ -863,7 +877,15 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 		}
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Buat Grup", state.GroupNames)
+	defer job.Finish()
+
 	for i, groupName := range state.GroupNames {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// HIGH FIX: Ambil active client di setiap iterasi (create grup = operasi BERAT!)
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessCreateGroups", i, totalGroups)
 		if shouldStop {
@@ -876,7 +898,7 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 		}
 
 		// Create group WITH settings applied at creation time
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 60*time.Second)
 
 		// Build ReqCreateGroup with settings embedded (applied BEFORE group is created)
 		req := buildCreateGroupRequest(groupName, participants, state)
@@ -915,7 +937,7 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 				}
 			}
 
-			time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 			continue
 		}
 
@@ -937,7 +959,7 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 				addMode = types.GroupMemberAddModeAdmin
 			}
 			// Use very short timeout context to apply as fast as possible
-			ctxMember, cancelMember := context.WithTimeout(context.Background(), 5*time.Second)
+			ctxMember, cancelMember := context.WithTimeout(job.Context(), 5*time.Second)
 			validClient.SetGroupMemberAddMode(ctxMember, jid, addMode)
 			cancelMember()
 		}
@@ -946,7 +968,7 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 		// IMPORTANT: Gunakan validClient untuk mencegah client stale
 		jid := groupInfo.JID
 		link := ""
-		ctxLink, cancelLink := context.WithTimeout(context.Background(), 10*time.Second)
+		ctxLink, cancelLink := context.WithTimeout(job.Context(), 10*time.Second)
 		inviteLink, errLink := validClient.GetGroupInviteLink(ctxLink, jid, false)
 		cancelLink()
 		if errLink == nil {
@@ -996,12 +1018,12 @@ func ProcessCreateGroups(state *GroupCreateState, chatID int64, client *whatsmeo
 
 		// Delay between groups
 		if i < totalGroups-1 {
-			time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 		}
 	}
 
 	// Final summary
-	summaryMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	summaryMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 📊 **RINGKASAN**
@@ -0,0 +1,650 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+)
+
+// CSVImportState menyimpan state import mapping CSV (satu baris = satu grup dengan nilai sendiri)
+type CSVImportState struct {
+	WaitingForFile  bool
+	WaitingForDelay bool
+	Rows            []CSVImportRow
+	DelaySeconds    int
+}
+
+// CSVImportRow adalah perubahan yang akan diterapkan ke satu grup
+// Field nil = kolom kosong/tidak ada, pengaturan tersebut tidak diubah
+type CSVImportRow struct {
+	Line         int
+	Group        GroupLinkInfo
+	Name         *string
+	Description  *string
+	Announce     *bool  // true = hanya admin yang bisa kirim pesan
+	Locked       *bool  // true = hanya admin yang bisa edit info grup
+	JoinApproval *bool  // true = anggota baru harus disetujui admin
+	Ephemeral    *int64 // detik: 0 = OFF, 86400 = 24h, 604800 = 7d, 7776000 = 90d
+}
+
+var csvImportStates = make(map[int64]*CSVImportState)
+
+// Kolom yang dikenali (nama kolom tidak case-sensitive)
+var csvImportColumnAliases = map[string]string{
+	"jid":           "jid",
+	"group_jid":     "jid",
+	"grup":          "group",
+	"group":         "group",
+	"nama_grup":     "group",
+	"name":          "name",
+	"nama":          "name",
+	"nama_baru":     "name",
+	"description":   "description",
+	"deskripsi":     "description",
+	"announce":      "announce",
+	"locked":        "locked",
+	"join_approval": "join_approval",
+	"ephemeral":     "ephemeral",
+}
+
+// Durasi pesan sementara yang didukung WhatsApp
+var csvEphemeralValues = map[string]int64{
+	"off": 0,
+	"0":   0,
+	"24h": 86400,
+	"1d":  86400,
+	"7d":  604800,
+	"90d": 7776000,
+}
+
+// Maksimal error validasi yang ditampilkan di laporan
+const csvImportMaxReportErrors = 20
+
+// ShowCSVImportMenuEdit menampilkan penjelasan format CSV dengan EDIT message
+func ShowCSVImportMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	menuMsg := "📥 **IMPORT CSV PER GRUP**\n\n" +
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+		"Setiap baris CSV = satu grup dengan nilai barunya sendiri. Semua baris diproses dalam satu job.\n\n" +
+		"**📋 Kolom:**\n" +
+		"• `jid` atau `grup` - JID grup atau nama grup persis (wajib salah satu)\n" +
+		"• `name` - nama baru grup\n" +
+		"• `description` - deskripsi baru\n" +
+		"• `announce` - on = hanya admin kirim pesan\n" +
+		"• `locked` - on = hanya admin edit info grup\n" +
+		"• `join_approval` - on = anggota baru harus disetujui\n" +
+		"• `ephemeral` - off / 24h / 7d / 90d\n\n" +
+		"Nilai on/off juga bisa ditulis ya/tidak, true/false, 1/0. Sel kosong = tidak diubah.\n\n" +
+		"**📝 Contoh:**\n" +
+		"```\n" +
+		"grup,name,description,announce,ephemeral\n" +
+		"Grup A,Grup A - Jakarta,Info wilayah Jakarta,on,7d\n" +
+		"Grup B,,Info wilayah Bandung,off,\n" +
+		"```\n\n" +
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+		"⚠️ File divalidasi dulu. Grup tidak dikenal dan nilai salah dilaporkan sebelum proses berjalan.\n\n" +
+		"💡 File .csv juga bisa dikirim langsung saat bot meminta file daftar grup di menu atur deskripsi, ganti nama, atau pengaturan grup."
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📤 Kirim File CSV", "start_csv_import"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, menuMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartCSVImportProcess memulai proses import CSV (menunggu file)
+func StartCSVImportProcess(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	csvImportStates[chatID] = &CSVImportState{
+		WaitingForFile: true,
+	}
+
+	msg := tgbotapi.NewMessage(chatID, "📤 **KIRIM FILE CSV**\n\nKirim file `.csv` berisi mapping grup dan nilai barunya.\n\n💡 Lihat format kolom di menu sebelumnya.")
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_csv_import"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// IsCSVImportUpload mengecek apakah file yang dikirim harus diproses sebagai import CSV
+// File .csv diterima saat menu import CSV aktif atau saat handler upload daftar grup (atur deskripsi,
+// ganti nama, pengaturan grup) sedang menunggu file
+func IsCSVImportUpload(chatID int64, fileName string) bool {
+	if !strings.HasSuffix(strings.ToLower(fileName), ".csv") {
+		return false
+	}
+
+	if state := csvImportStates[chatID]; state != nil && state.WaitingForFile {
+		return true
+	}
+
+	return GetDescriptionInputType(chatID) == "group_name" ||
+		GetRenameInputType(chatID) == "group_name" ||
+		GetAllSettingsInputType(chatID) == "group_name" ||
+		GetLoggingInputType(chatID) == "group_name" ||
+		GetJoinApprovalInputType(chatID) == "group_name" ||
+		GetEphemeralInputType(chatID) == "group_name" ||
+		GetEditInputType(chatID) == "group_name"
+}
+
+// clearBulkChangeStates menghapus state fitur atur grup lain saat user beralih ke import CSV
+func clearBulkChangeStates(chatID int64) {
+	delete(groupDescriptionStates, chatID)
+	delete(groupRenameStates, chatID)
+	delete(groupAllSettingsStates, chatID)
+	delete(groupMessageLoggingStates, chatID)
+	delete(groupJoinApprovalStates, chatID)
+	delete(groupEphemeralStates, chatID)
+	delete(groupEditStates, chatID)
+}
+
+// HandleCSVImportFile mengunduh, mem-parse dan memvalidasi file CSV lalu menampilkan laporan validasi
+func HandleCSVImportFile(fileID string, chatID int64, telegramBot *tgbotapi.BotAPI, botToken string) {
+	clearBulkChangeStates(chatID)
+	state := &CSVImportState{WaitingForFile: true}
+	csvImportStates[chatID] = state
+
+	content, err := downloadCSVImportFile(fileID, botToken)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengunduh file CSV")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	groupsMap, err := utils.GetAllGroupsFromDB()
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorDatabase, err, "Gagal membaca daftar grup")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	rows, validationErrors, err := ParseCSVImport(content, groupsMap)
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ FILE CSV TIDAK VALID\n\n%v\n\nPerbaiki file lalu kirim ulang.", err))
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	state.Rows = rows
+	sendCSVImportReport(chatID, state, validationErrors, telegramBot)
+}
+
+// downloadCSVImportFile mengunduh isi dokumen dari server Telegram
+func downloadCSVImportFile(fileID, botToken string) ([]byte, error) {
+	fileURL := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", botToken, fileID)
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var fileResp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			FilePath string `json:"file_path"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&fileResp); err != nil || !fileResp.OK {
+		return nil, fmt.Errorf("gagal mengambil informasi file")
+	}
+
+	downloadURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", botToken, fileResp.Result.FilePath)
+	contentResp, err := http.Get(downloadURL)
+	if err != nil {
+		return nil, err
+	}
+	defer contentResp.Body.Close()
+
+	return io.ReadAll(contentResp.Body)
+}
+
+// ParseCSVImport mem-parse isi CSV menjadi daftar perubahan per grup
+// Return baris valid, daftar error validasi per baris, dan error fatal (header/format file)
+func ParseCSVImport(content []byte, groupsMap map[string]string) ([]CSVImportRow, []string, error) {
+	columns, records, err := readCSVTable(content, csvImportColumnAliases)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, hasJID := columns["jid"]
+	_, hasGroup := columns["group"]
+	if !hasJID && !hasGroup {
+		return nil, nil, fmt.Errorf("kolom jid atau grup wajib ada")
+	}
+
+	// Index nama grup (lowercase) untuk pencarian nama persis
+	nameIndex := make(map[string][]string)
+	for jid, name := range groupsMap {
+		key := strings.ToLower(strings.TrimSpace(name))
+		nameIndex[key] = append(nameIndex[key], jid)
+	}
+
+	var rows []CSVImportRow
+	var validationErrors []string
+	seen := make(map[string]int)
+
+	for _, tableRow := range records {
+		line, record := tableRow.Line, tableRow.Cells
+		cell := func(column string) (string, bool) {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return "", false
+			}
+			value := strings.TrimSpace(record[index])
+			return value, value != ""
+		}
+
+		row := CSVImportRow{Line: line}
+		var rowErrors []string
+
+		// Cari grup berdasarkan JID atau nama persis
+		if jid, ok := cell("jid"); ok {
+			if name, exists := groupsMap[jid]; exists {
+				row.Group = GroupLinkInfo{JID: jid, Name: name}
+			} else {
+				rowErrors = append(rowErrors, fmt.Sprintf("grup dengan JID %s tidak dikenal", jid))
+			}
+		} else if groupName, ok := cell("group"); ok {
+			matches := nameIndex[strings.ToLower(groupName)]
+			switch len(matches) {
+			case 0:
+				rowErrors = append(rowErrors, fmt.Sprintf("grup \"%s\" tidak dikenal", groupName))
+			case 1:
+				row.Group = GroupLinkInfo{JID: matches[0], Name: groupsMap[matches[0]]}
+			default:
+				rowErrors = append(rowErrors, fmt.Sprintf("nama \"%s\" dipakai %d grup, gunakan kolom jid", groupName, len(matches)))
+			}
+		} else {
+			rowErrors = append(rowErrors, "jid/grup kosong")
+		}
+
+		if row.Group.JID != "" {
+			if firstLine, duplicate := seen[row.Group.JID]; duplicate {
+				rowErrors = append(rowErrors, fmt.Sprintf("grup sama dengan baris %d", firstLine))
+			} else {
+				seen[row.Group.JID] = line
+			}
+		}
+
+		if value, ok := cell("name"); ok {
+			if utf8.RuneCountInString(value) > maxGroupNameLength {
+				rowErrors = append(rowErrors, fmt.Sprintf("name lebih dari %d karakter", maxGroupNameLength))
+			} else {
+				row.Name = &value
+			}
+		}
+
+		if value, ok := cell("description"); ok {
+			if utf8.RuneCountInString(value) > 1024 {
+				rowErrors = append(rowErrors, "description lebih dari 1024 karakter")
+			} else {
+				row.Description = &value
+			}
+		}
+
+		for _, column := range []string{"announce", "locked", "join_approval"} {
+			value, ok := cell(column)
+			if !ok {
+				continue
+			}
+			parsed, err := parseCSVToggle(value)
+			if err != nil {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s: %v", column, err))
+				continue
+			}
+			switch column {
+			case "announce":
+				row.Announce = &parsed
+			case "locked":
+				row.Locked = &parsed
+			case "join_approval":
+				row.JoinApproval = &parsed
+			}
+		}
+
+		if value, ok := cell("ephemeral"); ok {
+			seconds, valid := csvEphemeralValues[strings.ToLower(value)]
+			if !valid {
+				rowErrors = append(rowErrors, fmt.Sprintf("ephemeral \"%s\" tidak valid (off/24h/7d/90d)", value))
+			} else {
+				row.Ephemeral = &seconds
+			}
+		}
+
+		if len(rowErrors) == 0 && row.changeCount() == 0 {
+			rowErrors = append(rowErrors, "tidak ada perubahan (semua kolom kosong)")
+		}
+
+		if len(rowErrors) > 0 {
+			validationErrors = append(validationErrors, fmt.Sprintf("Baris %d: %s", line, strings.Join(rowErrors, "; ")))
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, validationErrors, nil
+}
+
+// parseCSVToggle mengubah nilai on/off dari CSV menjadi bool
+func parseCSVToggle(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "ya", "yes", "true", "1":
+		return true, nil
+	case "off", "tidak", "no", "false", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("nilai \"%s\" tidak valid (on/off)", value)
+}
+
+// changeCount menghitung jumlah pengaturan yang diubah oleh baris ini
+func (row CSVImportRow) changeCount() int {
+	count := 0
+	for _, set := range []bool{row.Name != nil, row.Description != nil, row.Announce != nil, row.Locked != nil, row.JoinApproval != nil, row.Ephemeral != nil} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// sendCSVImportReport mengirim laporan validasi dan meminta delay jika ada baris valid
+func sendCSVImportReport(chatID int64, state *CSVImportState, validationErrors []string, telegramBot *tgbotapi.BotAPI) {
+	totalOps := 0
+	for _, row := range state.Rows {
+		totalOps += row.changeCount()
+	}
+
+	var report strings.Builder
+	report.WriteString("📋 LAPORAN VALIDASI CSV\n\n")
+	report.WriteString(fmt.Sprintf("✅ Baris valid: %d (%d perubahan)\n", len(state.Rows), totalOps))
+	report.WriteString(fmt.Sprintf("❌ Baris bermasalah: %d\n", len(validationErrors)))
+
+	if len(validationErrors) > 0 {
+		report.WriteString("\n")
+		for i, validationError := range validationErrors {
+			if i >= csvImportMaxReportErrors {
+				report.WriteString(fmt.Sprintf("... dan %d baris bermasalah lainnya\n", len(validationErrors)-csvImportMaxReportErrors))
+				break
+			}
+			report.WriteString("• " + validationError + "\n")
+		}
+	}
+
+	if len(state.Rows) == 0 {
+		delete(csvImportStates, chatID)
+		report.WriteString("\n⚠️ Tidak ada baris yang bisa diproses. Perbaiki file lalu mulai ulang import.")
+		telegramBot.Send(tgbotapi.NewMessage(chatID, report.String()))
+		return
+	}
+
+	state.WaitingForFile = false
+	state.WaitingForDelay = true
+
+	if len(validationErrors) > 0 {
+		report.WriteString("\nBaris bermasalah akan dilewati.\n")
+	}
+	report.WriteString("\n⏱️ Ketik delay antar grup (detik) untuk mulai, contoh: 3")
+
+	msg := tgbotapi.NewMessage(chatID, report.String())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_csv_import"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleDelayInputForCSVImport memproses input delay lalu menjalankan job import
+func HandleDelayInputForCSVImport(input string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := csvImportStates[chatID]
+	if state == nil || !state.WaitingForDelay {
+		return
+	}
+
+	delay, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || delay < 0 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Delay harus berupa angka positif (dalam detik)!")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	client := GetWhatsAppClient()
+	if client == nil || client.Store.ID == nil {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+		telegramBot.Send(errorMsg)
+		delete(csvImportStates, chatID)
+		return
+	}
+
+	state.DelaySeconds = delay
+	state.WaitingForDelay = false
+
+	startMsg := fmt.Sprintf("🚀 **MEMULAI IMPORT CSV**\n\n📊 **Total Grup:** %d grup\n⏱️ **Delay:** %d detik/grup\n\n⏳ Mohon tunggu, proses sedang berjalan...", len(state.Rows), delay)
+	msg := tgbotapi.NewMessage(chatID, startMsg)
+	msg.ParseMode = "Markdown"
+	telegramBot.Send(msg)
+
+	// Nama/deskripsi cukup izin edit info; pengaturan grup hanya bisa diubah admin
+	requirement := requireEditInfo
+	groups := make([]GroupLinkInfo, len(state.Rows))
+	for i, row := range state.Rows {
+		groups[i] = row.Group
+		if row.Announce != nil || row.Locked != nil || row.JoinApproval != nil {
+			requirement = requireAdmin
+		}
+	}
+	StartWithAdminPreflightCleanup(chatID, "Import CSV", groups, requirement, client, telegramBot, func(allowed []GroupLinkInfo) {
+		allowedJIDs := make(map[string]bool)
+		for _, group := range allowed {
+			allowedJIDs[group.JID] = true
+		}
+		filtered := []CSVImportRow{}
+		for _, row := range state.Rows {
+			if allowedJIDs[row.Group.JID] {
+				filtered = append(filtered, row)
+			}
+		}
+		state.Rows = filtered
+		ProcessCSVImport(state, chatID, client, telegramBot)
+	}, func() {
+		delete(csvImportStates, chatID)
+	})
+}
+
+// ProcessCSVImport menerapkan nilai setiap baris CSV ke grupnya dalam satu job
+func ProcessCSVImport(state *CSVImportState, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	defer delete(csvImportStates, chatID)
+
+	groups := make([]GroupLinkInfo, len(state.Rows))
+	for i, row := range state.Rows {
+		groups[i] = row.Group
+	}
+	totalGroups := len(groups)
+
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Import CSV", groupJobItems(groups))
+	defer job.Finish()
+
+	successCount := 0
+	failedCount := 0
+	var failedGroups []string
+	var progressMsgSent *tgbotapi.Message
+
+	for i, row := range state.Rows {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessCSVImport", i, totalGroups)
+		if shouldStop {
+			disconnectMsg := fmt.Sprintf("⚠️ **PROSES DIHENTIKAN**\n\nClient WhatsApp terputus pada grup %d/%d\n\n✅ Berhasil: %d operasi\n❌ Gagal: %d operasi", i+1, totalGroups, successCount, failedCount)
+			notifMsg := tgbotapi.NewMessage(chatID, disconnectMsg)
+			notifMsg.ParseMode = "Markdown"
+			telegramBot.Send(notifMsg)
+			break
+		}
+
+		jid, err := parseJIDFromString(row.Group.JID)
+		if err != nil {
+			failedCount += row.changeCount()
+			failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (invalid JID)", row.Group.Name))
+			continue
+		}
+
+		// apply menjalankan satu perubahan dengan timeout sendiri dan mencatat hasilnya
+		apply := func(label string, change func(ctx context.Context) error) {
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
+			defer cancel()
+			if err := change(ctx); err != nil {
+				failedCount++
+				failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (%s: %v)", row.Group.Name, label, err))
+				return
+			}
+			successCount++
+		}
+
+		if row.Name != nil {
+			apply("Nama", func(ctx context.Context) error {
+				if err := validClient.SetGroupName(ctx, jid, *row.Name); err != nil {
+					return err
+				}
+				if err := utils.SaveGroupToDB(row.Group.JID, *row.Name); err != nil {
+					utils.GetGrupLogger().Warn("ProcessCSVImport: Gagal update nama grup di database %s: %v", row.Group.JID, err)
+				}
+				return nil
+			})
+		}
+		if row.Description != nil {
+			apply("Deskripsi", func(ctx context.Context) error {
+				return validClient.SetGroupDescription(ctx, jid, *row.Description)
+			})
+		}
+		if row.Announce != nil {
+			apply("Pesan", func(ctx context.Context) error {
+				return validClient.SetGroupAnnounce(ctx, jid, *row.Announce)
+			})
+		}
+		if row.Locked != nil {
+			apply("Edit", func(ctx context.Context) error {
+				return validClient.SetGroupLocked(ctx, jid, *row.Locked)
+			})
+		}
+		if row.JoinApproval != nil {
+			apply("Persetujuan", func(ctx context.Context) error {
+				return validClient.SetGroupJoinApprovalMode(ctx, jid, *row.JoinApproval)
+			})
+		}
+		if row.Ephemeral != nil {
+			apply("Pesan Sementara", func(ctx context.Context) error {
+				return validClient.SetDisappearingTimer(ctx, jid, time.Duration(*row.Ephemeral)*time.Second, time.Now())
+			})
+		}
+
+		// Delay antar grup
+		if i < totalGroups-1 && state.DelaySeconds > 0 {
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+		}
+
+		if totalGroups > 1 {
+			progressPercent := ((i + 1) * 100) / totalGroups
+			progressMsg := fmt.Sprintf(`⏳ **PROGRESS**
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+%s **%d%%**
+📊 **Grup:** %d/%d grup
+✅ **Berhasil:** %d operasi
+❌ **Gagal:** %d operasi
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, generateProgressBar(progressPercent), progressPercent, i+1, totalGroups, successCount, failedCount)
+
+			if progressMsgSent == nil {
+				updateMsg := tgbotapi.NewMessage(chatID, progressMsg)
+				updateMsg.ParseMode = "Markdown"
+				sent, _ := telegramBot.Send(updateMsg)
+				progressMsgSent = &sent
+			} else {
+				editMsg := tgbotapi.NewEditMessageText(chatID, progressMsgSent.MessageID, progressMsg)
+				editMsg.ParseMode = "Markdown"
+				telegramBot.Send(editMsg)
+			}
+		}
+	}
+
+	if progressMsgSent != nil {
+		telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, progressMsgSent.MessageID))
+	}
+
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **IMPORT CSV SELESAI!**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📊 **Total Grup:** %d grup
+✅ **Berhasil:** %d operasi
+❌ **Gagal:** %d operasi
+⏱️ **Delay:** %d detik/grup
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, totalGroups, successCount, failedCount, state.DelaySeconds)
+
+	msg := tgbotapi.NewMessage(chatID, resultMsg)
+	msg.ParseMode = "Markdown"
+	telegramBot.Send(msg)
+
+	// Kirim detail kegagalan per 10 baris (tanpa Markdown karena berisi nama grup)
+	batchSize := 10
+	for i := 0; i < len(failedGroups); i += batchSize {
+		end := i + batchSize
+		if end > len(failedGroups) {
+			end = len(failedGroups)
+		}
+		failedMsg := tgbotapi.NewMessage(chatID, "❌ GAGAL:\n\n"+strings.Join(failedGroups[i:end], "\n"))
+		telegramBot.Send(failedMsg)
+	}
+}
+
+// CancelCSVImport membatalkan proses import CSV
+func CancelCSVImport(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	delete(csvImportStates, chatID)
+	msg := tgbotapi.NewMessage(chatID, "❌ Import CSV dibatalkan.")
+	telegramBot.Send(msg)
+}
+
+// IsWaitingForCSVImportInput mengecek apakah user sedang di proses import CSV
+func IsWaitingForCSVImportInput(chatID int64) bool {
+	state := csvImportStates[chatID]
+	return state != nil && (state.WaitingForFile || state.WaitingForDelay)
+}
+
+// GetCSVImportInputType mengembalikan jenis input yang sedang ditunggu
+func GetCSVImportInputType(chatID int64) string {
+	state := csvImportStates[chatID]
+	if state == nil {
+		return ""
+	}
+	if state.WaitingForFile {
+		return "file"
+	}
+	if state.WaitingForDelay {
+		return "delay"
+	}
+	return ""
+}
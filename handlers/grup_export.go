@@ -63,6 +63,15 @@ func ExportGroupList(telegramBot *tgbotapi.BotAPI, chatID int64, format string)
 	// Sort groups naturally before export
 	sortedGroups := utils.SortGroupsNaturally(groups)
 
+	// Keanggotaan komunitas (kolom tambahan di export)
+	communities, _ := utils.GetGroupCommunities()
+
+	// Saluran (newsletter) ikut di-export; refresh dari WhatsApp jika memungkinkan, jika gagal pakai cache
+	if err := refreshChannels(GetWhatsAppClient()); err != nil {
+		utils.GetLogger().Debug("ExportGroupList: Gagal refresh saluran, memakai cache: %v", err)
+	}
+	channels, _ := utils.GetChannels()
+
 	// Generate filename
 	timestamp := time.Now().Format("20060102_150405")
 	var filename string
@@ -70,16 +79,16 @@ func ExportGroupList(telegramBot *tgbotapi.BotAPI, chatID int64, format string)
 
 	if format == "csv" {
 		filename = fmt.Sprintf("whatsapp_groups_%s.csv", timestamp)
-		content.WriteString("No,Nama Grup,JID\n")
+		content.WriteString("No,Tipe,Nama,JID,Peran Komunitas,Komunitas,Peran Saluran,Pengikut,Link Saluran\n")
 
 		count := 1
 		for _, group := range sortedGroups {
-			// Escape CSV special characters
-			escapedName := strings.ReplaceAll(group.Name, "\"", "\"\"")
-			if strings.Contains(escapedName, ",") || strings.Contains(escapedName, "\"") {
-				escapedName = fmt.Sprintf("\"%s\"", escapedName)
-			}
-			content.WriteString(fmt.Sprintf("%d,%s,%s\n", count, escapedName, group.JID))
+			role, community := communityGroupLabel(group.JID, communities, groups)
+			content.WriteString(fmt.Sprintf("%d,grup,%s,%s,%s,%s,,,\n", count, escapeExportCSV(group.Name), group.JID, role, escapeExportCSV(community)))
+			count++
+		}
+		for _, channel := range channels {
+			content.WriteString(fmt.Sprintf("%d,saluran,%s,%s,,,%s,%d,%s\n", count, escapeExportCSV(channel.Name), channel.JID, channel.Role, channel.SubscriberCount, channelInviteLink(&channel)))
 			count++
 		}
 	} else {
@@ -94,10 +103,35 @@ func ExportGroupList(telegramBot *tgbotapi.BotAPI, chatID int64, format string)
 		count := 1
 		for _, group := range sortedGroups {
 			content.WriteString(fmt.Sprintf("%d. %s\n", count, group.Name))
-			content.WriteString(fmt.Sprintf("   JID: %s\n\n", group.JID))
+			content.WriteString(fmt.Sprintf("   JID: %s\n", group.JID))
+			switch role, community := communityGroupLabel(group.JID, communities, groups); role {
+			case "komunitas":
+				content.WriteString("   Komunitas: ya (grup induk komunitas)\n")
+			case "pengumuman":
+				content.WriteString(fmt.Sprintf("   Komunitas: %s (grup pengumuman)\n", community))
+			case "subgrup":
+				content.WriteString(fmt.Sprintf("   Komunitas: %s\n", community))
+			}
+			content.WriteString("\n")
 			count++
 		}
 
+		if len(channels) > 0 {
+			content.WriteString("=" + strings.Repeat("=", 60) + "\n")
+			content.WriteString("          DAFTAR SALURAN WHATSAPP\n")
+			content.WriteString("=" + strings.Repeat("=", 60) + "\n\n")
+
+			for i, channel := range channels {
+				content.WriteString(fmt.Sprintf("%d. %s\n", i+1, channel.Name))
+				content.WriteString(fmt.Sprintf("   JID: %s\n", channel.JID))
+				content.WriteString(fmt.Sprintf("   Peran: %s | Pengikut: %d\n", channel.Role, channel.SubscriberCount))
+				if link := channelInviteLink(&channel); link != "" {
+					content.WriteString(fmt.Sprintf("   Link: %s\n", link))
+				}
+				content.WriteString("\n")
+			}
+		}
+
 		content.WriteString("=" + strings.Repeat("=", 60) + "\n")
 		content.WriteString("Export by WhatsApp Bot\n")
 	}
@@ -121,10 +155,11 @@ func ExportGroupList(telegramBot *tgbotapi.BotAPI, chatID int64, format string)
 	// Send file
 	fileMsg := tgbotapi.NewDocument(chatID, tgbotapi.FilePath(filename))
 	fileMsg.Caption = fmt.Sprintf("📥 **Export Grup WhatsApp**\n\n"+
-		"📊 Total: %d grup\n"+
+		"📊 Total: %d grup, %d saluran\n"+
 		"📅 %s\n"+
 		"📄 Format: %s",
 		len(groups),
+		len(channels),
 		time.Now().Format("02 Jan 2006 15:04"),
 		strings.ToUpper(format))
 	fileMsg.ParseMode = "Markdown"
@@ -154,6 +189,15 @@ func ExportGroupList(telegramBot *tgbotapi.BotAPI, chatID int64, format string)
 	telegramBot.Send(successMsg)
 }
 
+// escapeExportCSV meng-escape nilai kolom CSV export (koma / tanda kutip)
+func escapeExportCSV(value string) string {
+	escaped := strings.ReplaceAll(value, "\"", "\"\"")
+	if strings.Contains(escaped, ",") || strings.Contains(escaped, "\"") {
+		escaped = fmt.Sprintf("\"%s\"", escaped)
+	}
+	return escaped
+}
+
 // ShowExportMenu menampilkan menu export dengan pilihan format
 func ShowExportMenu(telegramBot *tgbotapi.BotAPI, chatID int64) {
 	exportMsg := `📥 **EXPORT DAFTAR GRUP**
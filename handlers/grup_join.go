@@ -20,6 +20,11 @@ type JoinGroupState struct {
 	WaitingForDelay bool
 	GroupLinks      []string
 	DelaySeconds    int
+
+	// Preview link sebelum join (lihat grup_join_preview.go)
+	WaitingForPreview bool
+	Previews          []JoinLinkPreview
+	PreviewPage       int
 }
 
 var joinGroupStates = make(map[int64]*JoinGroupState)
@@ -51,6 +56,7 @@ https://chat.whatsapp.com/XYZ789
 • Bot harus belum bergabung ke grup tersebut
 • Delay membantu menghindari rate limit
 • Proses mungkin memakan waktu untuk banyak link
+• Grup dengan persetujuan admin dicatat di menu Permintaan Join
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -63,6 +69,9 @@ https://chat.whatsapp.com/XYZ789
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Mulai", "start_join_group"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📨 Permintaan Join (Pending)", "join_requests_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_join_group"),
 		),
@@ -102,6 +111,7 @@ https://chat.whatsapp.com/XYZ789
 • Bot harus belum bergabung ke grup tersebut
 • Delay membantu menghindari rate limit
 • Proses mungkin memakan waktu untuk banyak link
+• Grup dengan persetujuan admin dicatat di menu Permintaan Join
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -114,6 +124,9 @@ https://chat.whatsapp.com/XYZ789
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🚀 Mulai", "start_join_group"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📨 Permintaan Join (Pending)", "join_requests_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_join_group"),
 		),
@@ -200,11 +213,9 @@ func HandleLinkInputForJoin(input string, chatID int64, telegramBot *tgbotapi.Bo
 	}
 
 	state.GroupLinks = links
-	state.WaitingForLink = false
-	state.WaitingForDelay = true
 
-	// Ask for delay
-	askForDelayInput(chatID, telegramBot, state)
+	// Preview link dulu (nama, anggota, status member/approval) sebelum minta delay
+	StartJoinLinkPreview(chatID, telegramBot)
 }
 
 // HandleFileInputForJoin memproses input file .txt
@@ -270,10 +281,8 @@ func HandleFileInputForJoin(fileID string, chatID int64, telegramBot *tgbotapi.B
 	}
 
 	state.GroupLinks = links
-	state.WaitingForLink = false
-	state.WaitingForDelay = true
 
-	// Confirm and ask for delay
+	// Confirm and preview links
 	confirmMsg := fmt.Sprintf(`✅ **FILE DITERIMA**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -286,8 +295,8 @@ func HandleFileInputForJoin(fileID string, chatID int64, telegramBot *tgbotapi.B
 	confirm.ParseMode = "Markdown"
 	telegramBot.Send(confirm)
 
-	// Ask for delay
-	askForDelayInput(chatID, telegramBot, state)
+	// Preview link dulu (nama, anggota, status member/approval) sebelum minta delay
+	StartJoinLinkPreview(chatID, telegramBot)
 }
 
 // askForDelayInput meminta input delay
@@ -377,12 +386,21 @@ func ProcessJoinGroups(state *JoinGroupState, chatID int64, client *whatsmeow.Cl
 	totalLinks := len(state.GroupLinks)
 	successCount := 0
 	failedCount := 0
+	pendingCount := 0
 	var failedLinks []string
 	var successLinks []string
 
 	var progressMsgSent *tgbotapi.Message
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Join Grup", state.GroupLinks)
+	defer job.Finish()
+
 	for i, link := range state.GroupLinks {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// MEDIUM FIX: Ambil active client di setiap iterasi (join bisa timeout!)
 		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessJoinGroups", i, len(state.GroupLinks))
 		if shouldStop {
@@ -395,7 +413,7 @@ func ProcessJoinGroups(state *JoinGroupState, chatID int64, client *whatsmeow.Cl
 		}
 
 		// Join group using link dengan validClient
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 		defer cancel() // FIXED: Use defer to ensure cancellation
 		jid, err := validClient.JoinGroupWithLink(ctx, link)
 
@@ -412,7 +430,14 @@ func ProcessJoinGroups(state *JoinGroupState, chatID int64, client *whatsmeow.Cl
 			failedLinks = append(failedLinks, fmt.Sprintf("❌ %s\n   💡 %s", link, errorDetail))
 		} else {
 			successCount++
-			successLinks = append(successLinks, fmt.Sprintf("✅ %s\n   🆔 %s", link, jid.String()))
+			// Grup dengan persetujuan admin: akun masih pending, catat untuk dipantau lewat event grup
+			if pending, groupName := detectPendingJoin(validClient, state, link, jid); pending {
+				pendingCount++
+				recordPendingJoin(validClient, jid, groupName, link)
+				successLinks = append(successLinks, fmt.Sprintf("⏳ %s\n   🆔 %s\n   💡 Menunggu persetujuan admin", link, jid.String()))
+			} else {
+				successLinks = append(successLinks, fmt.Sprintf("✅ %s\n   🆔 %s", link, jid.String()))
+			}
 		}
 
 		// Show progress
@@ -444,21 +469,22 @@ func ProcessJoinGroups(state *JoinGroupState, chatID int64, client *whatsmeow.Cl
 
 		// Delay between joins
 		if i < totalLinks-1 {
-			time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 		}
 	}
 
 	// Final summary
-	summaryMsg := fmt.Sprintf(`🎉 **SELESAI!**
+	summaryMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 📊 **RINGKASAN**
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 📋 **Total Link:** %d link
 ✅ **Berhasil:** %d link
+⏳ **Menunggu Persetujuan:** %d link
 ❌ **Gagal:** %d link
 ⏱️ **Delay:** %d detik/link
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, totalLinks, successCount, failedCount, state.DelaySeconds)
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, totalLinks, successCount-pendingCount, pendingCount, failedCount, state.DelaySeconds)
 
 	if successCount > 0 {
 		summaryMsg += fmt.Sprintf("\n\n**✅ Link yang Berhasil:**\n\n")
@@ -486,6 +512,13 @@ func ProcessJoinGroups(state *JoinGroupState, chatID int64, client *whatsmeow.Cl
 
 	finalMsg := tgbotapi.NewMessage(chatID, summaryMsg)
 	finalMsg.ParseMode = "Markdown"
+	if pendingCount > 0 {
+		finalMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("📨 Lihat Permintaan Join", "join_requests_menu"),
+			),
+		)
+	}
 	telegramBot.Send(finalMsg)
 
 	// Clear state
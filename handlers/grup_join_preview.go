@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// JoinLinkPreview menyimpan hasil resolve satu link undangan sebelum join
+type JoinLinkPreview struct {
+	Link          string
+	Name          string
+	JID           string
+	Size          int
+	AlreadyMember bool   // Grup sudah ada di tabel groups akun ini / akun sudah jadi anggota
+	NeedsApproval bool   // Join perlu persetujuan admin
+	InvalidReason string // Tidak kosong jika link tidak valid/dicabut
+	Selected      bool
+}
+
+const (
+	// Jumlah entri preview per halaman
+	joinPreviewPerPage = 10
+	// Jeda antar resolve link agar tidak kena rate limit
+	joinPreviewResolveDelay = 1 * time.Second
+)
+
+// Selectable mengembalikan true jika entri boleh dipilih untuk join
+func (p JoinLinkPreview) Selectable() bool {
+	return p.InvalidReason == "" && !p.AlreadyMember
+}
+
+// StartJoinLinkPreview me-resolve semua link dengan GetGroupInfoFromLink lalu menampilkan tabel preview
+// Dipanggil setelah link diterima (text atau file), sebelum user memasukkan delay
+func StartJoinLinkPreview(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := joinGroupStates[chatID]
+	if state == nil {
+		return
+	}
+
+	client := GetWhatsAppClient()
+	if client == nil || client.Store.ID == nil || !client.IsConnected() {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		delete(joinGroupStates, chatID)
+		return
+	}
+
+	state.WaitingForLink = false
+	state.WaitingForDelay = false
+	state.WaitingForPreview = true
+	state.Previews = nil
+	state.PreviewPage = 1
+
+	go resolveJoinLinks(state, chatID, client, telegramBot)
+}
+
+// resolveJoinLinks me-resolve setiap link dan menandai link tidak valid, grup yang sudah diikuti dan grup dengan approval
+func resolveJoinLinks(state *JoinGroupState, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	// Grup yang sudah ada di tabel groups akun ini dilewati
+	knownGroups, err := utils.GetAllGroupsFromDB()
+	if err != nil {
+		utils.GetGrupLogger().Warn("resolveJoinLinks: Gagal memuat grup dari database: %v", err)
+		knownGroups = map[string]string{}
+	}
+
+	loadingMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔎 Memeriksa %d link grup...", len(state.GroupLinks)))
+	loadingSent, _ := telegramBot.Send(loadingMsg)
+
+	previews := make([]JoinLinkPreview, 0, len(state.GroupLinks))
+	seenGroups := make(map[string]bool)
+
+	for i, link := range state.GroupLinks {
+		if joinGroupStates[chatID] != state {
+			return // Dibatalkan user
+		}
+
+		preview := JoinLinkPreview{Link: link}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		info, err := client.GetGroupInfoFromLink(ctx, link)
+		cancel()
+
+		switch {
+		case errors.Is(err, whatsmeow.ErrInviteLinkRevoked):
+			preview.InvalidReason = "Link sudah dicabut"
+		case errors.Is(err, whatsmeow.ErrInviteLinkInvalid):
+			preview.InvalidReason = "Link tidak valid"
+		case err != nil:
+			preview.InvalidReason = "Gagal memeriksa link"
+			utils.GetGrupLogger().Debug("resolveJoinLinks: Gagal resolve %s: %v", link, err)
+		case info == nil:
+			preview.InvalidReason = "Link tidak valid"
+		default:
+			preview.JID = info.JID.String()
+			preview.Name = info.Name
+			preview.Size = len(info.Participants)
+			preview.NeedsApproval = info.IsJoinApprovalRequired
+			_, known := knownGroups[preview.JID]
+			preview.AlreadyMember = known || isOwnAccountInGroup(client, info)
+
+			// Dua link berbeda ke grup yang sama cukup di-join sekali
+			if seenGroups[preview.JID] {
+				preview.InvalidReason = "Duplikat (grup sama dengan link lain)"
+			}
+			seenGroups[preview.JID] = true
+		}
+		preview.Selected = preview.Selectable()
+		previews = append(previews, preview)
+
+		if loadingSent.MessageID != 0 && ((i+1)%5 == 0 || i == len(state.GroupLinks)-1) {
+			percent := (i + 1) * 100 / len(state.GroupLinks)
+			progress := tgbotapi.NewEditMessageText(chatID, loadingSent.MessageID,
+				fmt.Sprintf("🔎 Memeriksa link grup...\n\n%s %d%%\n%d / %d link", generateProgressBar(percent), percent, i+1, len(state.GroupLinks)))
+			telegramBot.Send(progress)
+		}
+
+		if i < len(state.GroupLinks)-1 {
+			time.Sleep(joinPreviewResolveDelay)
+		}
+	}
+
+	if loadingSent.MessageID != 0 {
+		telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, loadingSent.MessageID))
+	}
+
+	state.Previews = previews
+	text, keyboard := renderJoinPreview(state)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// isOwnAccountInGroup mengecek apakah akun ini sudah ada di daftar peserta grup (jika WhatsApp mengirim daftar peserta)
+func isOwnAccountInGroup(client *whatsmeow.Client, info *types.GroupInfo) bool {
+	for _, participant := range info.Participants {
+		if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderJoinPreview membuat teks tabel preview (plain text) dan keyboard toggle untuk halaman aktif
+func renderJoinPreview(state *JoinGroupState) (string, tgbotapi.InlineKeyboardMarkup) {
+	previews := state.Previews
+	totalPages := (len(previews) + joinPreviewPerPage - 1) / joinPreviewPerPage
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	if state.PreviewPage < 1 {
+		state.PreviewPage = 1
+	}
+	if state.PreviewPage > totalPages {
+		state.PreviewPage = totalPages
+	}
+
+	invalid, member, approval, selected := 0, 0, 0, 0
+	for _, p := range previews {
+		switch {
+		case p.InvalidReason != "":
+			invalid++
+		case p.AlreadyMember:
+			member++
+		}
+		if p.NeedsApproval && p.Selectable() {
+			approval++
+		}
+		if p.Selected {
+			selected++
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString("🔎 PREVIEW LINK GRUP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("📋 Total link: %d\n", len(previews)))
+	text.WriteString(fmt.Sprintf("✅ Dipilih: %d\n", selected))
+	text.WriteString(fmt.Sprintf("👥 Sudah member (dilewati): %d\n", member))
+	text.WriteString(fmt.Sprintf("🚫 Tidak valid: %d\n", invalid))
+	text.WriteString(fmt.Sprintf("🔐 Perlu persetujuan: %d\n\n", approval))
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	start := (state.PreviewPage - 1) * joinPreviewPerPage
+	end := min(start+joinPreviewPerPage, len(previews))
+
+	var toggleRow []tgbotapi.InlineKeyboardButton
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	for i := start; i < end; i++ {
+		p := previews[i]
+		icon := "⬜"
+		switch {
+		case p.InvalidReason != "":
+			icon = "🚫"
+		case p.AlreadyMember:
+			icon = "👥"
+		case p.Selected:
+			icon = "✅"
+		}
+
+		if p.InvalidReason != "" {
+			text.WriteString(fmt.Sprintf("%d. %s %s\n   %s\n   💡 %s\n\n", i+1, icon, p.Link, displayJoinPreviewName(p), p.InvalidReason))
+		} else {
+			details := []string{}
+			if p.Size > 0 {
+				details = append(details, fmt.Sprintf("%d anggota", p.Size))
+			}
+			if p.AlreadyMember {
+				details = append(details, "sudah member")
+			}
+			if p.NeedsApproval {
+				details = append(details, "🔐 perlu persetujuan")
+			}
+			if len(details) == 0 {
+				details = append(details, "siap join")
+			}
+			text.WriteString(fmt.Sprintf("%d. %s %s\n   %s\n\n", i+1, icon, displayJoinPreviewName(p), strings.Join(details, " • ")))
+		}
+
+		if p.Selectable() {
+			toggleRow = append(toggleRow, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s %d", icon, i+1), fmt.Sprintf("join_toggle_%d", i)))
+			if len(toggleRow) == 5 {
+				rows = append(rows, toggleRow)
+				toggleRow = nil
+			}
+		}
+	}
+	if len(toggleRow) > 0 {
+		rows = append(rows, toggleRow)
+	}
+
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("💡 Tekan nomor untuk memilih/membatalkan grup, lalu tekan Lanjut.")
+
+	if totalPages > 1 {
+		var navRow []tgbotapi.InlineKeyboardButton
+		if state.PreviewPage > 1 {
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️ Prev", fmt.Sprintf("join_preview_page_%d", state.PreviewPage-1)))
+		}
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📄 %d/%d", state.PreviewPage, totalPages), "noop"))
+		if state.PreviewPage < totalPages {
+			navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("➡️ Next", fmt.Sprintf("join_preview_page_%d", state.PreviewPage+1)))
+		}
+		rows = append(rows, navRow)
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("☑️ Pilih Semua", "join_select_all"),
+			tgbotapi.NewInlineKeyboardButtonData("⬜ Kosongkan", "join_select_none"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🚀 Lanjut (%d grup)", selected), "join_preview_confirm"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_join_group"),
+		),
+	)
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// displayJoinPreviewName mengembalikan nama grup untuk ditampilkan (fallback ke JID/tanda tanya)
+func displayJoinPreviewName(p JoinLinkPreview) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if p.JID != "" {
+		return p.JID
+	}
+	return "(nama tidak diketahui)"
+}
+
+// refreshJoinPreview mengedit pesan preview setelah pilihan berubah
+func refreshJoinPreview(state *JoinGroupState, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	text, keyboard := renderJoinPreview(state)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// ToggleJoinPreviewEntry memilih/membatalkan satu link di preview
+func ToggleJoinPreviewEntry(index int, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := joinGroupStates[chatID]
+	if state == nil || !state.WaitingForPreview || index < 0 || index >= len(state.Previews) {
+		return
+	}
+	if !state.Previews[index].Selectable() {
+		return
+	}
+
+	state.Previews[index].Selected = !state.Previews[index].Selected
+	refreshJoinPreview(state, chatID, messageID, telegramBot)
+}
+
+// SetAllJoinPreviewSelection memilih semua (selected=true) atau mengosongkan pilihan preview
+func SetAllJoinPreviewSelection(selected bool, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := joinGroupStates[chatID]
+	if state == nil || !state.WaitingForPreview {
+		return
+	}
+
+	for i := range state.Previews {
+		state.Previews[i].Selected = selected && state.Previews[i].Selectable()
+	}
+	refreshJoinPreview(state, chatID, messageID, telegramBot)
+}
+
+// ShowJoinPreviewPage pindah halaman preview
+func ShowJoinPreviewPage(page int, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := joinGroupStates[chatID]
+	if state == nil || !state.WaitingForPreview {
+		return
+	}
+
+	state.PreviewPage = page
+	refreshJoinPreview(state, chatID, messageID, telegramBot)
+}
+
+// ConfirmJoinPreview memakai link yang dipilih sebagai daftar join lalu meminta delay
+func ConfirmJoinPreview(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := joinGroupStates[chatID]
+	if state == nil || !state.WaitingForPreview {
+		return
+	}
+
+	var links []string
+	for _, p := range state.Previews {
+		if p.Selected {
+			links = append(links, p.Link)
+		}
+	}
+
+	if len(links) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "⚠️ Belum ada grup yang dipilih.\n\nPilih minimal 1 grup atau tekan Batalkan."))
+		return
+	}
+
+	state.GroupLinks = links
+	state.WaitingForPreview = false
+	state.WaitingForDelay = true
+
+	askForDelayInput(chatID, telegramBot, state)
+}
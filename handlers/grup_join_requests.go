@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Maksimal entri per status yang ditampilkan di daftar permintaan join
+const joinRequestsDisplayLimit = 15
+
+// detectPendingJoin menentukan apakah join yang baru berhasil masih menunggu persetujuan admin
+// Memakai hasil preview jika ada; jika tidak, cek apakah akun sudah bisa membaca info grup (sudah member)
+func detectPendingJoin(client *whatsmeow.Client, state *JoinGroupState, link string, jid types.JID) (bool, string) {
+	for _, preview := range state.Previews {
+		if preview.Link == link {
+			return preview.NeedsApproval, preview.Name
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	info, err := client.GetGroupInfo(ctx, jid)
+	if err != nil {
+		return true, ""
+	}
+	return false, info.Name
+}
+
+// recordPendingJoin mencatat permintaan join yang menunggu persetujuan untuk akun client
+func recordPendingJoin(client *whatsmeow.Client, jid types.JID, groupName, link string) {
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		return
+	}
+	if err := utils.SaveJoinRequest(accountID, jid.String(), groupName, link); err != nil {
+		utils.GetGrupLogger().Warn("recordPendingJoin: Gagal menyimpan permintaan join %s: %v", jid.String(), err)
+	}
+}
+
+// JoinRequestStatusFromGroupInfo membaca event perubahan grup untuk mendeteksi permintaan join akun ini
+// disetujui (akun masuk daftar Join) atau ditolak (akun ada di Leave / permintaan dicabut). Return "" jika tidak relevan
+func JoinRequestStatusFromGroupInfo(client *whatsmeow.Client, evt *events.GroupInfo) string {
+	for _, jid := range evt.Join {
+		if isOwnJID(client, jid) {
+			return utils.JoinRequestApproved
+		}
+	}
+	for _, jid := range evt.Leave {
+		if isOwnJID(client, jid) {
+			return utils.JoinRequestRejected
+		}
+	}
+
+	// Penolakan tidak punya field khusus di whatsmeow; WhatsApp mengirimnya sebagai node permintaan yang dicabut
+	for _, node := range evt.UnknownChanges {
+		if node == nil || node.Tag != "revoked_membership_requests" {
+			continue
+		}
+		for _, child := range node.GetChildren() {
+			if jid, ok := child.Attrs["jid"].(types.JID); ok && isOwnJID(client, jid) {
+				return utils.JoinRequestRejected
+			}
+		}
+	}
+	return ""
+}
+
+// ShowJoinRequestsEdit menampilkan daftar permintaan join (pending/disetujui/ditolak) beserta umurnya
+func ShowJoinRequestsEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildJoinRequestsView()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildJoinRequestsView membuat teks daftar permintaan join (plain text karena nama grup bebas)
+func buildJoinRequestsView() (string, tgbotapi.InlineKeyboardMarkup) {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Cek Status Pending", "join_requests_refresh"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "join_group_menu"),
+		),
+	)
+
+	client := GetWhatsAppClient()
+	records, err := utils.GetJoinRequests(clientAccountID(client))
+	if err != nil {
+		return fmt.Sprintf("❌ Gagal memuat permintaan join: %v", err), keyboard
+	}
+
+	grouped := map[string][]utils.JoinRequestRecord{}
+	for _, record := range records {
+		grouped[record.Status] = append(grouped[record.Status], record)
+	}
+
+	var text strings.Builder
+	text.WriteString("📨 PERMINTAAN JOIN GRUP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("⏳ Menunggu: %d\n", len(grouped[utils.JoinRequestPending])))
+	text.WriteString(fmt.Sprintf("✅ Disetujui: %d\n", len(grouped[utils.JoinRequestApproved])))
+	text.WriteString(fmt.Sprintf("❌ Ditolak: %d\n", len(grouped[utils.JoinRequestRejected])))
+
+	if len(records) == 0 {
+		text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		text.WriteString("Belum ada permintaan join yang menunggu persetujuan admin.")
+		return text.String(), keyboard
+	}
+
+	sections := []struct {
+		status string
+		title  string
+	}{
+		{utils.JoinRequestPending, "⏳ MENUNGGU PERSETUJUAN"},
+		{utils.JoinRequestApproved, "✅ DISETUJUI"},
+		{utils.JoinRequestRejected, "❌ DITOLAK"},
+	}
+
+	for _, section := range sections {
+		list := grouped[section.status]
+		if len(list) == 0 {
+			continue
+		}
+
+		text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+		text.WriteString(section.title + "\n\n")
+		for i, record := range list {
+			if i >= joinRequestsDisplayLimit {
+				text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(list)-joinRequestsDisplayLimit))
+				break
+			}
+
+			name := record.GroupName
+			if name == "" {
+				name = record.GroupJID
+			}
+			text.WriteString(fmt.Sprintf("%d. %s\n", i+1, name))
+			if record.Status == utils.JoinRequestPending {
+				text.WriteString(fmt.Sprintf("   🕒 Menunggu %s\n", formatStateDuration(time.Since(record.RequestedAt))))
+			} else {
+				text.WriteString(fmt.Sprintf("   🕒 %s lalu (menunggu %s)\n",
+					formatStateDuration(time.Since(record.ResolvedAt)),
+					formatStateDuration(record.ResolvedAt.Sub(record.RequestedAt))))
+			}
+			if record.InviteLink != "" {
+				text.WriteString(fmt.Sprintf("   🔗 %s\n", record.InviteLink))
+			}
+		}
+	}
+
+	return text.String(), keyboard
+}
+
+// RefreshPendingJoinRequests mengecek ulang permintaan pending: jika info grup sudah bisa dibaca berarti sudah disetujui
+// Berguna jika event persetujuan terlewat saat bot offline
+func RefreshPendingJoinRequests(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	accountID := clientAccountID(client)
+	records, err := utils.GetJoinRequests(accountID)
+	if err != nil {
+		ShowJoinRequestsEdit(telegramBot, chatID, messageID)
+		return
+	}
+
+	loading := tgbotapi.NewEditMessageText(chatID, messageID, "🔄 Mengecek status permintaan join...")
+	telegramBot.Send(loading)
+
+	checked := 0
+	for _, record := range records {
+		if record.Status != utils.JoinRequestPending || !client.IsConnected() {
+			continue
+		}
+		jid, err := types.ParseJID(record.GroupJID)
+		if err != nil {
+			continue
+		}
+
+		if checked > 0 {
+			time.Sleep(inviteLinkRequestDelay)
+		}
+		checked++
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		info, err := client.GetGroupInfo(ctx, jid)
+		cancel()
+		if err != nil || info == nil {
+			continue // Belum member: masih pending (atau ditolak tanpa notifikasi)
+		}
+
+		if _, err := utils.ResolveJoinRequestAt("", accountID, record.GroupJID, utils.JoinRequestApproved); err == nil {
+			utils.SaveGroupToDB(record.GroupJID, info.Name)
+		}
+	}
+
+	ShowJoinRequestsEdit(telegramBot, chatID, messageID)
+}
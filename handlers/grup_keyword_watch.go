@@ -0,0 +1,461 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// KeywordWatchState menyimpan state input aturan pantau per chat
+type KeywordWatchState struct {
+	WaitingForInput bool
+}
+
+// KeywordWatchAlert adalah satu kecocokan aturan pantau yang siap dikirim ke Telegram
+type KeywordWatchAlert struct {
+	Watch      utils.KeywordWatch
+	GroupName  string
+	SenderName string
+	Match      string
+	Snippet    string
+	Suppressed int // Kecocokan yang tidak dikirim selama jeda sebelumnya
+}
+
+// keywordWatchThrottle mencatat alert terakhir satu aturan
+type keywordWatchThrottle struct {
+	LastSent   time.Time
+	Suppressed int
+}
+
+var (
+	keywordWatchStates = make(map[int64]*KeywordWatchState)
+
+	keywordWatchMutex     sync.Mutex
+	keywordWatchThrottles = make(map[string]*keywordWatchThrottle) // key: akun|ID aturan
+	keywordWatchPatterns  = make(map[string]*regexp.Regexp)        // key: akun|ID aturan
+)
+
+const (
+	// Jeda default antar alert satu aturan jika tidak diisi
+	defaultKeywordWatchThrottle = 5 * time.Minute
+	// Jumlah karakter di sekitar kecocokan yang ditampilkan di alert
+	keywordWatchSnippetRadius = 80
+	// Maksimal aturan pantau per akun
+	maxKeywordWatches = 50
+)
+
+// ShowKeywordWatchMenuEdit menampilkan daftar aturan pantau kata kunci akun
+func ShowKeywordWatchMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildKeywordWatchMenu()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildKeywordWatchMenu membuat teks daftar aturan pantau (plain text karena kata kunci / regex bebas)
+func buildKeywordWatchMenu() (string, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var text strings.Builder
+	text.WriteString("🔔 PANTAU KATA KUNCI\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Dapatkan alert di sini saat kata kunci atau regex muncul di grup yang dipilih (contoh: nama klien atau \"urgent\").\n\n")
+	text.WriteString("⏱️ Setiap aturan punya jeda agar alert tidak membanjiri chat; kecocokan selama jeda dihitung di alert berikutnya.\n")
+
+	watches, err := utils.GetKeywordWatchesAt("", clientAccountID(GetWhatsAppClient()))
+	if err != nil {
+		text.WriteString(fmt.Sprintf("\n❌ Gagal memuat aturan: %v\n", err))
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(watches) == 0 && err == nil {
+		text.WriteString("Belum ada aturan. Klik ➕ untuk menambahkan.")
+	}
+
+	for i, watch := range watches {
+		text.WriteString(fmt.Sprintf("%d. %s\n", i+1, keywordWatchTargetLabel(&watch)))
+		throttleLabel := "tanpa jeda"
+		if watch.ThrottleSeconds > 0 {
+			throttleLabel = formatStateDuration(keywordWatchThrottleDuration(&watch))
+		}
+		text.WriteString(fmt.Sprintf("   %s • ⏱️ %s\n", keywordWatchPatternLabel(&watch), throttleLabel))
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🗑️ Hapus Aturan %d", i+1), "watch_delete_"+strconv.FormatInt(watch.ID, 10)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah Aturan", "watch_add"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+		),
+	)
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// keywordWatchTargetLabel memformat target aturan pantau untuk ditampilkan
+func keywordWatchTargetLabel(watch *utils.KeywordWatch) string {
+	switch {
+	case watch.Target == utils.KeywordWatchAllGroups:
+		return "🌐 Semua grup"
+	case watch.IsTagTarget():
+		return "🏷️ " + watch.TargetName
+	case watch.TargetName != "":
+		return "👥 " + watch.TargetName
+	default:
+		return "👥 " + watch.Target
+	}
+}
+
+// keywordWatchPatternLabel memformat kata kunci / regex aturan pantau
+func keywordWatchPatternLabel(watch *utils.KeywordWatch) string {
+	if watch.Regex != "" {
+		return "🧩 /" + watch.Regex + "/"
+	}
+	return "🔑 " + strings.Join(watch.Keywords, ", ")
+}
+
+// keywordWatchThrottleDuration mengembalikan jeda antar alert aturan
+func keywordWatchThrottleDuration(watch *utils.KeywordWatch) time.Duration {
+	return time.Duration(watch.ThrottleSeconds) * time.Second
+}
+
+// StartKeywordWatchInput meminta input aturan pantau baru (target + kata kunci / regex + jeda)
+func StartKeywordWatchInput(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	keywordWatchStates[chatID] = &KeywordWatchState{WaitingForInput: true}
+
+	promptMsg := `🔔 **TAMBAH ATURAN PANTAU**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Format:**
+Baris 1: nama grup (persis), koleksi tag, atau ` + "`semua`" + `
+Baris berikutnya: opsi ` + "`kunci: nilai`" + `
+
+**Contoh kata kunci:**
+` + "`semua\nkata: urgent, PT Maju Jaya\njeda: 10`" + `
+
+**Contoh regex:**
+` + "`tag:klien=vip\nregex: invoice\\s*#?\\d+`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**⚙️ Opsi:**
+• ` + "`kata`" + ` - kata kunci, pisahkan dengan koma
+• ` + "`regex`" + ` - pola regex (pengganti kata)
+• ` + "`jeda`" + ` - menit minimal antar alert (default 5, 0 = tanpa jeda)
+
+💡 Huruf besar/kecil tidak dibedakan.
+
+⏳ Menunggu input...`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "watch_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleKeywordWatchInput menyimpan aturan pantau dari input user lalu menampilkan daftar aturan
+func HandleKeywordWatchInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := keywordWatchStates[chatID]
+	if state == nil || !state.WaitingForInput {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	target := strings.TrimSpace(lines[0])
+	if target == "" || len(lines) < 2 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Format salah. Baris 1 berisi nama grup / tag:key=value / semua, baris berikutnya kata: atau regex:"))
+		return
+	}
+
+	accountID := clientAccountID(client)
+	if existing, err := utils.GetKeywordWatchesAt("", accountID); err == nil && len(existing) >= maxKeywordWatches {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Maksimal %d aturan pantau. Hapus aturan lama terlebih dahulu.", maxKeywordWatches)))
+		return
+	}
+
+	watch := &utils.KeywordWatch{
+		AccountID:       accountID,
+		ThrottleSeconds: int(defaultKeywordWatchThrottle.Seconds()),
+	}
+	if strings.EqualFold(target, "semua") {
+		watch.Target, watch.TargetName = utils.KeywordWatchAllGroups, "Semua grup"
+	} else {
+		var err error
+		watch.Target, watch.TargetName, err = resolveGroupOrTagTarget(target)
+		if err != nil {
+			telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+			return
+		}
+	}
+
+	if err := parseKeywordWatchOptions(lines[1:], watch); err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+
+	if _, err := utils.SaveKeywordWatch(watch); err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan aturan pantau"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+	delete(keywordWatchStates, chatID)
+	utils.LogActivity("keyword_watch_add", fmt.Sprintf("Keyword watch added for %s", watch.TargetName), chatID)
+
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Aturan pantau disimpan\n%s\n%s", keywordWatchTargetLabel(watch), keywordWatchPatternLabel(watch))))
+
+	text, keyboard := buildKeywordWatchMenu()
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// parseKeywordWatchOptions membaca baris "kunci: nilai" ke dalam aturan pantau
+func parseKeywordWatchOptions(lines []string, watch *utils.KeywordWatch) error {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("Baris tidak dikenali: %s (gunakan format kunci: nilai)", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "kata", "keyword", "keywords":
+			watch.Keywords = nil
+			seen := make(map[string]bool)
+			for _, word := range strings.Split(value, ",") {
+				word = strings.TrimSpace(word)
+				if word != "" && !seen[strings.ToLower(word)] {
+					seen[strings.ToLower(word)] = true
+					watch.Keywords = append(watch.Keywords, word)
+				}
+			}
+		case "regex":
+			if _, err := regexp.Compile("(?i)" + value); err != nil || value == "" {
+				return fmt.Errorf("Regex tidak valid: %v", err)
+			}
+			watch.Regex = value
+		case "jeda", "throttle":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 || minutes > 1440 {
+				return fmt.Errorf("Nilai jeda harus 0-1440 menit")
+			}
+			watch.ThrottleSeconds = minutes * 60
+		default:
+			return fmt.Errorf("Opsi tidak dikenal: %s", key)
+		}
+	}
+
+	if len(watch.Keywords) == 0 && watch.Regex == "" {
+		return fmt.Errorf("Isi minimal satu kata kunci (kata:) atau regex (regex:)")
+	}
+	if len(watch.Keywords) > 0 && watch.Regex != "" {
+		return fmt.Errorf("Pilih salah satu: kata atau regex, tidak keduanya")
+	}
+	return nil
+}
+
+// DeleteKeywordWatchEntry menghapus aturan pantau lalu menampilkan ulang daftar
+func DeleteKeywordWatchEntry(watchID int64, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	accountID := clientAccountID(GetWhatsAppClient())
+	if err := utils.DeleteKeywordWatch(accountID, watchID); err == nil {
+		key := keywordWatchKey(accountID, watchID)
+		keywordWatchMutex.Lock()
+		delete(keywordWatchThrottles, key)
+		delete(keywordWatchPatterns, key)
+		keywordWatchMutex.Unlock()
+	}
+	ShowKeywordWatchMenuEdit(telegramBot, chatID, messageID)
+}
+
+// IsWaitingForKeywordWatchInput mengecek apakah user sedang mengisi aturan pantau
+func IsWaitingForKeywordWatchInput(chatID int64) bool {
+	state := keywordWatchStates[chatID]
+	return state != nil && state.WaitingForInput
+}
+
+// CancelKeywordWatchInput membatalkan input aturan pantau
+func CancelKeywordWatchInput(chatID int64) {
+	delete(keywordWatchStates, chatID)
+}
+
+// keywordWatchKey membuat key cache jeda dan regex satu aturan (akun|ID aturan)
+func keywordWatchKey(accountID string, watchID int64) string {
+	return accountID + "|" + strconv.FormatInt(watchID, 10)
+}
+
+// keywordWatchPattern mengembalikan regex aturan (kata kunci digabung jadi satu pola), di-cache per aturan
+// Harus dipanggil dengan keywordWatchMutex terkunci
+func keywordWatchPattern(key string, watch *utils.KeywordWatch) *regexp.Regexp {
+	source := watch.Regex
+	if source == "" {
+		quoted := make([]string, len(watch.Keywords))
+		for i, keyword := range watch.Keywords {
+			quoted[i] = regexp.QuoteMeta(keyword)
+		}
+		source = strings.Join(quoted, "|")
+	}
+	source = "(?i)" + source
+
+	if pattern, ok := keywordWatchPatterns[key]; ok && pattern.String() == source {
+		return pattern
+	}
+	pattern, err := regexp.Compile(source)
+	if err != nil {
+		return nil
+	}
+	keywordWatchPatterns[key] = pattern
+	return pattern
+}
+
+// keywordWatchSnippet memotong teks di sekitar kecocokan (aman untuk karakter multi-byte)
+func keywordWatchSnippet(text string, start, end int) string {
+	before := []rune(text[:start])
+	after := []rune(text[end:])
+
+	snippet := text[start:end]
+	if len(before) > keywordWatchSnippetRadius {
+		snippet = "..." + string(before[len(before)-keywordWatchSnippetRadius:]) + snippet
+	} else {
+		snippet = string(before) + snippet
+	}
+	if len(after) > keywordWatchSnippetRadius {
+		snippet += string(after[:keywordWatchSnippetRadius]) + "..."
+	} else {
+		snippet += string(after)
+	}
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// MatchKeywordWatches mencocokkan pesan grup dengan aturan pantau akun (dbPath = database akun sumber event)
+// Return alert yang lolos jeda; kecocokan selama jeda hanya dihitung
+func MatchKeywordWatches(client *whatsmeow.Client, dbPath string, evt *events.Message) ([]KeywordWatchAlert, error) {
+	if client == nil || client.Store.ID == nil || !evt.Info.IsGroup || evt.Info.IsFromMe {
+		return nil, nil
+	}
+
+	text := groupMessageText(evt.Message)
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+
+	accountID := client.Store.ID.User
+	watches, err := utils.GetKeywordWatchesAt(dbPath, accountID)
+	if err != nil || len(watches) == 0 {
+		return nil, err
+	}
+
+	groupJID := evt.Info.Chat.String()
+	var tags map[string]string
+	for _, watch := range watches {
+		if watch.IsTagTarget() {
+			tags, _ = utils.GetGroupTagsAt(dbPath, groupJID)
+			break
+		}
+	}
+
+	var alerts []KeywordWatchAlert
+	now := time.Now()
+
+	keywordWatchMutex.Lock()
+	for _, watch := range watches {
+		if !watch.AppliesTo(groupJID, tags) {
+			continue
+		}
+
+		key := keywordWatchKey(accountID, watch.ID)
+		pattern := keywordWatchPattern(key, &watch)
+		if pattern == nil {
+			continue
+		}
+		loc := pattern.FindStringIndex(text)
+		if loc == nil {
+			continue
+		}
+
+		throttle := keywordWatchThrottles[key]
+		if throttle == nil {
+			throttle = &keywordWatchThrottle{}
+			keywordWatchThrottles[key] = throttle
+		}
+		if !throttle.LastSent.IsZero() && now.Sub(throttle.LastSent) < keywordWatchThrottleDuration(&watch) {
+			throttle.Suppressed++
+			continue
+		}
+
+		alerts = append(alerts, KeywordWatchAlert{
+			Watch:      watch,
+			Match:      text[loc[0]:loc[1]],
+			Snippet:    keywordWatchSnippet(text, loc[0], loc[1]),
+			Suppressed: throttle.Suppressed,
+		})
+		throttle.LastSent = now
+		throttle.Suppressed = 0
+	}
+	keywordWatchMutex.Unlock()
+
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+
+	groupName, _ := utils.GetGroupNameAt(dbPath, groupJID)
+	if groupName == "" {
+		groupName = groupJID
+	}
+	senderName := moderationSenderLabel(client, evt.Info.Sender)
+	if evt.Info.PushName != "" {
+		senderName = fmt.Sprintf("%s (%s)", evt.Info.PushName, senderName)
+	}
+	for i := range alerts {
+		alerts[i].GroupName = groupName
+		alerts[i].SenderName = senderName
+	}
+	return alerts, nil
+}
+
+// SendKeywordWatchAlert mengirim alert pantau kata kunci ke pemilik akun dengan tombol ke menu grup
+func SendKeywordWatchAlert(accountID int, alert KeywordWatchAlert) {
+	var text strings.Builder
+	text.WriteString("🔔 KATA KUNCI TERDETEKSI\n\n")
+	text.WriteString(fmt.Sprintf("👥 Grup: %s\n", alert.GroupName))
+	text.WriteString(fmt.Sprintf("👤 Pengirim: %s\n", alert.SenderName))
+	text.WriteString(fmt.Sprintf("🔑 Cocok: \"%s\" (%s)\n", alert.Match, keywordWatchTargetLabel(&alert.Watch)))
+	text.WriteString(fmt.Sprintf("\n💬 %s\n", alert.Snippet))
+	if alert.Suppressed > 0 {
+		text.WriteString(fmt.Sprintf("\nℹ️ %d kecocokan lain tidak dikirim selama jeda sebelumnya.\n", alert.Suppressed))
+	}
+	text.WriteString(fmt.Sprintf("\n🕐 %s", time.Now().Format("02 Jan 2006 15:04")))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👥 Menu Grup", "menu_grup"),
+			tgbotapi.NewInlineKeyboardButtonData("🔔 Aturan Pantau", "watch_menu"),
+		),
+	)
+	SendToAccountOwnerWithKeyboard(accountID, text.String(), keyboard)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	texttemplate "text/template"
 	"time"
 	"whatsapp-bot/utils"
 
@@ -24,6 +25,7 @@ type LeaveGroupState struct {
 	WaitingForDelay        bool
 	WaitingForMode         bool
 	WaitingForNotification bool
+	WaitingForConfirm      bool // Menunggu konfirmasi preview notifikasi template
 	SelectedGroups         []GroupLinkInfo
 	DelaySeconds           int
 	LeaveMode              string // "one_by_one" or "batch"
@@ -61,6 +63,7 @@ GRUP C
 • Delay membantu menghindari rate limit
 • Proses mungkin memakan waktu untuk banyak grup
 • Setelah keluar, bot tidak bisa menerima pesan dari grup tersebut
+• Grup tempat bot admin: link disimpan, bisa 🔁 Gabung Kembali selama masa tenggang
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -76,6 +79,9 @@ GRUP C
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_leave_group"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Gabung Kembali", "rejoin_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
 		),
@@ -113,6 +119,7 @@ GRUP C
 • Delay membantu menghindari rate limit
 • Proses mungkin memakan waktu untuk banyak grup
 • Setelah keluar, bot tidak bisa menerima pesan dari grup tersebut
+• Grup tempat bot admin: link disimpan, bisa 🔁 Gabung Kembali selama masa tenggang
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -125,6 +132,9 @@ GRUP C
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_leave_group"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Gabung Kembali", "rejoin_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
 		),
@@ -565,7 +575,8 @@ func HandleNotificationChoiceForLeave(sendNotification bool, chatID int64, teleg
 	}
 
 	state.SendNotification = sendNotification
-	state.WaitingForNotification = false
+	// Tetap menunggu input pesan jika notifikasi diaktifkan
+	state.WaitingForNotification = sendNotification
 
 	if sendNotification {
 		// Ask for notification message
@@ -580,10 +591,12 @@ Bot akan keluar dari grup ini. Terima kasih!
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
+%s
+
 💡 Ketik pesan notifikasi...`
 
-		msg := tgbotapi.NewMessage(chatID, msgPrompt)
-		msg.ParseMode = "Markdown"
+		// Tanpa Markdown agar placeholder {{.Name}} dll tampil apa adanya
+		msg := tgbotapi.NewMessage(chatID, strings.ReplaceAll(fmt.Sprintf(msgPrompt, groupTemplateHelp), "**", ""))
 
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
 			tgbotapi.NewInlineKeyboardRow(
@@ -615,9 +628,60 @@ func HandleNotificationMessageInputForLeave(message string, chatID int64, telegr
 	}
 
 	state.NotificationMessage = message
+
+	// Template per grup: tampilkan preview dulu sebelum proses dimulai
+	if IsGroupTemplate(message) {
+		preview, err := BuildGroupTemplatePreview(GetWhatsAppClient(), state.SelectedGroups, message, 3)
+		if err != nil {
+			errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ %v\n\nSilakan ketik ulang pesan notifikasi.", err))
+			telegramBot.Send(errorMsg)
+			return
+		}
+
+		state.WaitingForNotification = false
+		state.WaitingForConfirm = true
+
+		previewMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("👀 PREVIEW PESAN NOTIFIKASI\n\n%s\nLanjutkan keluar dari %d grup dengan pesan di atas?", preview, len(state.SelectedGroups)))
+		previewMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✅ Lanjutkan", "leave_template_apply"),
+				tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_leave_group"),
+			),
+		)
+		telegramBot.Send(previewMsg)
+		return
+	}
+
+	state.WaitingForNotification = false
+	startProcessing(chatID, telegramBot)
+}
+
+// ApplyLeaveNotificationTemplate memulai proses keluar grup setelah preview notifikasi template dikonfirmasi
+func ApplyLeaveNotificationTemplate(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := leaveGroupStates[chatID]
+	if state == nil || !state.WaitingForConfirm {
+		return
+	}
+
+	state.WaitingForConfirm = false
 	startProcessing(chatID, telegramBot)
 }
 
+// renderLeaveNotification menyiapkan pesan notifikasi untuk satu grup
+// Pesan biasa dikirim apa adanya, template dirender dengan metadata grup
+func renderLeaveNotification(state *LeaveGroupState, tmpl *texttemplate.Template, client *whatsmeow.Client, group GroupLinkInfo, index int) string {
+	if tmpl == nil {
+		return state.NotificationMessage
+	}
+
+	rendered, err := RenderGroupTemplate(tmpl, LoadGroupTemplateData(client, group, index, state.NotificationMessage))
+	if err != nil {
+		utils.GetGrupLogger().Warn("ProcessLeaveGroups: %v", err)
+		return ""
+	}
+	return rendered
+}
+
 // startProcessing memulai proses keluar grup
 func startProcessing(chatID int64, telegramBot *tgbotapi.BotAPI) {
 	state := leaveGroupStates[chatID]
@@ -643,6 +707,9 @@ func startProcessing(chatID int64, telegramBot *tgbotapi.BotAPI) {
 	notificationText := "Tidak"
 	if state.SendNotification {
 		notificationText = fmt.Sprintf("Ya: \"%s\"", state.NotificationMessage)
+		if IsGroupTemplate(state.NotificationMessage) {
+			notificationText = "Ya (template per grup)"
+		}
 	}
 
 	startMsg := fmt.Sprintf(`✅ **KONFIGURASI SELESAI**
@@ -691,14 +758,27 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 	failedCount := 0
 	var failedGroups []string
 	var successGroups []string
+	rejoinableCount := 0 // Grup yang link-nya tersimpan untuk gabung kembali
+	pausedNote := ""     // Terisi jika mode satu per satu dijeda karena shutdown
 
 	var progressMsgSent *tgbotapi.Message
 
+	// Parse template notifikasi sekali di awal (nil jika pesan biasa)
+	var notificationTemplate *texttemplate.Template
+	if state.SendNotification && IsGroupTemplate(state.NotificationMessage) {
+		tmpl, err := ParseGroupTemplate(state.NotificationMessage)
+		if err != nil {
+			utils.GetGrupLogger().Warn("ProcessLeaveGroups: %v", err)
+		}
+		notificationTemplate = tmpl
+	}
+
 	if state.LeaveMode == "batch" {
 		// Batch mode: Leave all groups at once
 		// Collect all group JIDs and own JID
 		groupJIDs := []types.JID{}
 		groupNames := []string{}
+		validGroups := []GroupLinkInfo{}
 
 		for _, group := range state.SelectedGroups {
 			groupJID, err := parseJIDFromString(group.JID)
@@ -709,6 +789,7 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 			}
 			groupJIDs = append(groupJIDs, groupJID)
 			groupNames = append(groupNames, group.Name)
+			validGroups = append(validGroups, group)
 		}
 
 		if len(groupJIDs) == 0 {
@@ -739,10 +820,14 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 		// Send notification messages if enabled (one by one before batch leave)
 		if state.SendNotification && state.NotificationMessage != "" {
 			for i, groupJID := range groupJIDs {
+				notification := renderLeaveNotification(state, notificationTemplate, validClient, validGroups[i], i)
+				if notification == "" {
+					continue
+				}
 				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 				defer cancel() // FIXED: Use defer to ensure cancellation
 				_, err := validClient.SendMessage(ctx, groupJID, &waProto.Message{
-					Conversation: proto.String(state.NotificationMessage),
+					Conversation: proto.String(notification),
 				})
 
 				if err == nil {
@@ -760,6 +845,9 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 		// Leave all groups at once (batch operation)
 		// Process each group individually but without delay
 		for i, groupJID := range groupJIDs {
+			// Simpan link + metadata grup (jika admin) agar bisa gabung kembali jika salah pilih
+			snapshot := snapshotGroupBeforeLeave(validClient, groupJID, validGroups[i])
+
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 			_, err := validClient.UpdateGroupParticipants(ctx, groupJID, []types.JID{currentOwnJID}, whatsmeow.ParticipantChangeRemove)
 			cancel()
@@ -777,11 +865,22 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 			} else {
 				successCount++
 				successGroups = append(successGroups, fmt.Sprintf("✅ %s", groupName))
+				if saveLeftGroupSnapshot(snapshot) {
+					rejoinableCount++
+				}
 			}
 		}
 	} else {
 		// One by one mode: Leave groups one by one with delay
+		// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+		job := StartGroupJob(chatID, "Keluar Grup", groupJobItems(state.SelectedGroups))
+		defer job.Finish()
+
 		for i, group := range state.SelectedGroups {
+			if !job.Next(i) {
+				break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+			}
+
 			// MEDIUM FIX: Ambil active client di setiap iterasi!
 			validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessLeaveGroups", i, totalGroups)
 			if shouldStop {
@@ -810,21 +909,24 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 			}
 
 			// Send notification message if enabled
-			if state.SendNotification && state.NotificationMessage != "" {
-				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if notification := renderLeaveNotification(state, notificationTemplate, validClient, group, i); state.SendNotification && notification != "" {
+				ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 				defer cancel() // FIXED: Use defer to ensure cancellation
 				// FIXED: err tidak digunakan, tapi tidak perlu di-handle karena hanya notification
 				_, _ = validClient.SendMessage(ctx, groupJID, &waProto.Message{
-					Conversation: proto.String(state.NotificationMessage),
+					Conversation: proto.String(notification),
 				})
 
 				// Small delay after sending notification before leaving
 				time.Sleep(1 * time.Second)
 			}
 
+			// Simpan link + metadata grup (jika admin) agar bisa gabung kembali jika salah pilih
+			snapshot := snapshotGroupBeforeLeave(validClient, groupJID, group)
+
 			// Leave group using UpdateGroupParticipants with ParticipantChangeRemove
 			// Use own JID to leave the group
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
 			defer cancel() // FIXED: Use defer to ensure cancellation
 			_, err = validClient.UpdateGroupParticipants(ctx, groupJID, []types.JID{currentOwnJID}, whatsmeow.ParticipantChangeRemove)
 			// FIXED: Handle error untuk leave group operation
@@ -844,6 +946,9 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 			} else {
 				successCount++
 				successGroups = append(successGroups, fmt.Sprintf("✅ %s", group.Name))
+				if saveLeftGroupSnapshot(snapshot) {
+					rejoinableCount++
+				}
 			}
 
 			// Show progress
@@ -875,9 +980,10 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 
 			// Delay between leaves (only for one_by_one mode)
 			if state.LeaveMode == "one_by_one" && i < totalGroups-1 {
-				time.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+				job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
 			}
 		}
+		pausedNote = job.PausedNote()
 	}
 
 	// Delete progress message if exists
@@ -887,7 +993,7 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 	}
 
 	// Final summary
-	summaryMsg := fmt.Sprintf(`�� **SELESAI!**
+	summaryMsg := pausedNote + fmt.Sprintf(`�� **SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 �� **RINGKASAN**
@@ -922,8 +1028,19 @@ func ProcessLeaveGroups(state *LeaveGroupState, chatID int64, client *whatsmeow.
 		}
 	}
 
+	if rejoinableCount > 0 {
+		summaryMsg += fmt.Sprintf("\n\n🔁 **Link %d grup disimpan.** Salah pilih? Gabung kembali dalam %s.", rejoinableCount, formatStateDuration(rejoinGracePeriod()))
+	}
+
 	msg := tgbotapi.NewMessage(chatID, summaryMsg)
 	msg.ParseMode = "Markdown"
+	if rejoinableCount > 0 {
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🔁 Gabung Kembali (Pilih Grup)", "rejoin_menu_new"),
+			),
+		)
+	}
 	telegramBot.Send(msg)
 
 	// Log activity
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// RejoinState menyimpan daftar grup yang bisa digabungi kembali beserta pilihan user
+type RejoinState struct {
+	Records  []utils.LeftGroupRecord
+	Selected map[int64]bool // Key: ID snapshot
+}
+
+var rejoinStates = make(map[int64]*RejoinState)
+
+const (
+	// Masa tenggang default jika rejoin_grace_hours tidak diatur di config
+	defaultRejoinGraceHours = 72
+	// Jeda antar JoinGroupWithLink saat gabung kembali
+	rejoinDelay = 3 * time.Second
+	// Maksimal grup yang ditampilkan (dan diberi tombol) di menu gabung kembali
+	rejoinDisplayLimit = 20
+)
+
+// rejoinGracePeriod membaca masa tenggang gabung kembali dari config (settings.rejoin_grace_hours)
+func rejoinGracePeriod() time.Duration {
+	hours := defaultRejoinGraceHours
+	if config, err := utils.LoadTelegramConfig(); err == nil && config.Settings != nil && config.Settings.RejoinGraceHours > 0 {
+		hours = config.Settings.RejoinGraceHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// snapshotGroupBeforeLeave mengambil link undangan dan metadata grup sebelum keluar
+// Hanya grup di mana akun ini admin (link hanya bisa diambil admin); return nil jika tidak bisa di-snapshot
+func snapshotGroupBeforeLeave(client *whatsmeow.Client, groupJID types.JID, group GroupLinkInfo) *utils.LeftGroupRecord {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	cancel()
+	if err != nil || info == nil {
+		return nil
+	}
+
+	isAdmin := false
+	for _, participant := range info.Participants {
+		if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) {
+			isAdmin = participant.IsAdmin || participant.IsSuperAdmin
+			break
+		}
+	}
+	if !isAdmin {
+		return nil
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 15*time.Second)
+	link, err := client.GetGroupInviteLink(ctx, groupJID, false)
+	cancel()
+	if err != nil || link == "" {
+		utils.GetGrupLogger().Warn("snapshotGroupBeforeLeave: Gagal mengambil link %s: %v", group.Name, err)
+		return nil
+	}
+
+	name := info.Name
+	if name == "" {
+		name = group.Name
+	}
+	return &utils.LeftGroupRecord{
+		AccountID:   clientAccountID(client),
+		GroupJID:    group.JID,
+		GroupName:   name,
+		Topic:       info.Topic,
+		MemberCount: len(info.Participants),
+		InviteLink:  link,
+	}
+}
+
+// saveLeftGroupSnapshot menyimpan snapshot setelah keluar grup berhasil
+func saveLeftGroupSnapshot(snapshot *utils.LeftGroupRecord) bool {
+	if snapshot == nil {
+		return false
+	}
+	snapshot.LeftAt = time.Now()
+	if err := utils.SaveLeftGroup(snapshot); err != nil {
+		utils.GetGrupLogger().Warn("saveLeftGroupSnapshot: Gagal menyimpan snapshot %s: %v", snapshot.GroupName, err)
+		return false
+	}
+	return true
+}
+
+// ShowRejoinMenu menampilkan grup yang ditinggalkan dalam masa tenggang untuk dipilih dan digabungi kembali
+func ShowRejoinMenu(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	grace := rejoinGracePeriod()
+	records, err := utils.GetRejoinableGroups(clientAccountID(GetWhatsAppClient()), time.Now().Add(-grace))
+	if err != nil {
+		sendOrEditRejoin(chatID, messageID, fmt.Sprintf("❌ Gagal memuat grup yang ditinggalkan: %v", err), nil, telegramBot)
+		return
+	}
+
+	state := rejoinStates[chatID]
+	if state == nil {
+		state = &RejoinState{Selected: make(map[int64]bool)}
+		rejoinStates[chatID] = state
+	}
+	state.Records = records
+
+	text, keyboard := buildRejoinMenu(state, grace)
+	sendOrEditRejoin(chatID, messageID, text, &keyboard, telegramBot)
+}
+
+// buildRejoinMenu membuat teks daftar grup yang bisa digabungi kembali (plain text karena nama grup bebas)
+func buildRejoinMenu(state *RejoinState, grace time.Duration) (string, tgbotapi.InlineKeyboardMarkup) {
+	var text strings.Builder
+	text.WriteString("🔁 GABUNG KEMBALI KE GRUP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("Link grup (tempat akun ini admin) disimpan saat keluar dan bisa dipakai gabung kembali selama %s.\n", formatStateDuration(grace)))
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(state.Records) == 0 {
+		text.WriteString("\n📭 Tidak ada grup yang bisa digabungi kembali.")
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "leave_group_menu"),
+		))
+		return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	selectedCount := 0
+	for _, record := range state.Records {
+		if state.Selected[record.ID] {
+			selectedCount++
+		}
+	}
+	text.WriteString(fmt.Sprintf("\n📋 Grup: %d • ✅ Dipilih: %d\n", len(state.Records), selectedCount))
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for i, record := range state.Records {
+		if i >= rejoinDisplayLimit {
+			text.WriteString(fmt.Sprintf("... dan %d grup lainnya (gunakan Pilih Semua)\n", len(state.Records)-rejoinDisplayLimit))
+			break
+		}
+		mark := "⬜"
+		if state.Selected[record.ID] {
+			mark = "✅"
+		}
+		text.WriteString(fmt.Sprintf("%s %d. %s\n", mark, i+1, record.GroupName))
+		text.WriteString(fmt.Sprintf("   👥 %d anggota • 🕒 keluar %s lalu\n", record.MemberCount, formatStateDuration(time.Since(record.LeftAt))))
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s %s", mark, truncateButtonLabel(record.GroupName)), fmt.Sprintf("rejoin_toggle_%d", record.ID)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("☑️ Pilih Semua", "rejoin_select_all"),
+			tgbotapi.NewInlineKeyboardButtonData("⬜ Kosongkan", "rejoin_select_none"),
+		),
+	)
+	if selectedCount > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🔁 Gabung Kembali (%d grup)", selectedCount), "rejoin_confirm"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "leave_group_menu"),
+	))
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// truncateButtonLabel memotong nama grup agar muat di tombol inline
+func truncateButtonLabel(name string) string {
+	runes := []rune(name)
+	if len(runes) > 30 {
+		return string(runes[:30]) + "…"
+	}
+	return name
+}
+
+// sendOrEditRejoin mengedit pesan menu (messageID > 0) atau mengirim pesan baru
+func sendOrEditRejoin(chatID int64, messageID int, text string, keyboard *tgbotapi.InlineKeyboardMarkup, telegramBot *tgbotapi.BotAPI) {
+	if messageID > 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		editMsg.ReplyMarkup = keyboard
+		telegramBot.Send(editMsg)
+		return
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	telegramBot.Send(msg)
+}
+
+// ToggleRejoinSelection memilih / membatalkan pilihan satu grup
+func ToggleRejoinSelection(recordID int64, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := rejoinStates[chatID]
+	if state == nil {
+		ShowRejoinMenu(chatID, messageID, telegramBot)
+		return
+	}
+	state.Selected[recordID] = !state.Selected[recordID]
+
+	text, keyboard := buildRejoinMenu(state, rejoinGracePeriod())
+	sendOrEditRejoin(chatID, messageID, text, &keyboard, telegramBot)
+}
+
+// SetAllRejoinSelection memilih atau mengosongkan semua grup
+func SetAllRejoinSelection(selected bool, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	state := rejoinStates[chatID]
+	if state == nil {
+		ShowRejoinMenu(chatID, messageID, telegramBot)
+		return
+	}
+	state.Selected = make(map[int64]bool)
+	if selected {
+		for _, record := range state.Records {
+			state.Selected[record.ID] = true
+		}
+	}
+
+	text, keyboard := buildRejoinMenu(state, rejoinGracePeriod())
+	sendOrEditRejoin(chatID, messageID, text, &keyboard, telegramBot)
+}
+
+// ProcessRejoinGroups bergabung kembali ke grup terpilih memakai link yang disimpan saat keluar
+func ProcessRejoinGroups(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := rejoinStates[chatID]
+	if state == nil {
+		ShowRejoinMenu(chatID, messageID, telegramBot)
+		return
+	}
+	delete(rejoinStates, chatID)
+
+	var selected []utils.LeftGroupRecord
+	var groups []GroupLinkInfo
+	for _, record := range state.Records {
+		if state.Selected[record.ID] {
+			selected = append(selected, record)
+			groups = append(groups, GroupLinkInfo{JID: record.GroupJID, Name: record.GroupName})
+		}
+	}
+	if len(selected) == 0 {
+		ShowRejoinMenu(chatID, messageID, telegramBot)
+		return
+	}
+
+	job := StartGroupJob(chatID, "Gabung Kembali", groupJobItems(groups))
+	defer job.Finish()
+
+	var joined, pending, failed []string
+	for i, record := range selected {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessRejoinGroups", i, len(selected))
+		if shouldStop {
+			failed = append(failed, fmt.Sprintf("⚠️ Client terputus pada grup %d/%d", i+1, len(selected)))
+			break
+		}
+
+		progress := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("⏳ Gabung kembali ke grup...\n\n%s\n📊 %d/%d grup\n👥 %s",
+			generateProgressBar((i*100)/len(selected)), i+1, len(selected), record.GroupName))
+		telegramBot.Send(progress)
+
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
+		jid, err := validClient.JoinGroupWithLink(ctx, record.InviteLink)
+		cancel()
+
+		if err != nil {
+			reason := err.Error()
+			if errors.Is(err, whatsmeow.ErrInviteLinkRevoked) {
+				reason = "link sudah direset"
+			} else if errors.Is(err, whatsmeow.ErrInviteLinkInvalid) {
+				reason = "link tidak valid"
+			}
+			failed = append(failed, fmt.Sprintf("❌ %s (%s)", record.GroupName, reason))
+		} else {
+			utils.MarkLeftGroupRejoined(record.ID)
+			if isPending, _ := detectPendingJoin(validClient, &JoinGroupState{}, record.InviteLink, jid); isPending {
+				recordPendingJoin(validClient, jid, record.GroupName, record.InviteLink)
+				pending = append(pending, fmt.Sprintf("⏳ %s", record.GroupName))
+			} else {
+				utils.SaveGroupToDB(jid.String(), record.GroupName)
+				joined = append(joined, fmt.Sprintf("✅ %s", record.GroupName))
+			}
+		}
+
+		if i < len(selected)-1 {
+			job.Sleep(rejoinDelay)
+		}
+	}
+
+	var text strings.Builder
+	text.WriteString(job.PausedNote())
+	text.WriteString("🔁 GABUNG KEMBALI SELESAI\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString(fmt.Sprintf("✅ Bergabung: %d\n", len(joined)))
+	text.WriteString(fmt.Sprintf("⏳ Menunggu persetujuan: %d\n", len(pending)))
+	text.WriteString(fmt.Sprintf("❌ Gagal: %d\n", len(failed)))
+
+	for _, section := range [][]string{joined, pending, failed} {
+		if len(section) == 0 {
+			continue
+		}
+		text.WriteString("\n")
+		for i, line := range section {
+			if i >= rejoinDisplayLimit {
+				text.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(section)-rejoinDisplayLimit))
+				break
+			}
+			text.WriteString(line + "\n")
+		}
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Grup Lainnya", "rejoin_menu"),
+		),
+	)
+	sendOrEditRejoin(chatID, messageID, text.String(), &keyboard, telegramBot)
+
+	utils.LogActivity("rejoin_group", fmt.Sprintf("Rejoin %d groups: %d joined, %d pending, %d failed", len(selected), len(joined), len(pending), len(failed)), chatID)
+}
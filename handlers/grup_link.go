@@ -23,6 +23,10 @@ type LinkGrupState struct {
 	WaitingForDelay     bool
 	SelectedGroups      []GroupLinkInfo
 	Keyword             string
+
+	// Mode reset link (lihat grup_link_reset.go)
+	WaitingForResetConfirm bool
+	DelaySeconds           int
 }
 
 // GroupLinkInfo stores group information for link extraction
@@ -35,6 +39,9 @@ var linkGrupStates = make(map[int64]*LinkGrupState)
 
 // ShowGetLinkMenu menampilkan menu untuk ambil link grup
 func ShowGetLinkMenu(telegramBot *tgbotapi.BotAPI, chatID int64) {
+	// Kembali ke mode ambil link biasa
+	delete(linkResetModes, chatID)
+
 	menuMsg := `🔗 **AMBIL LINK GRUP**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -46,6 +53,8 @@ Fitur ini akan mengambil link undangan dari grup WhatsApp yang Anda pilih.
 🔍 **Cari Manual** - Ketik nama/kata kunci grup
 📋 **Lihat & Pilih** - Lihat daftar lalu pilih
 ⚡ **Ambil Semua** - Proses semua grup sekaligus
+📦 **Export Link Valid** - Link tersimpan yang masih berlaku (hanya yang lama dicek ulang)
+♻️ **Reset Link** - Buat ulang link jika link bocor
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -69,6 +78,10 @@ Fitur ini akan mengambil link undangan dari grup WhatsApp yang Anda pilih.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Ambil Semua", "get_all_links"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📦 Export Link Valid", "export_valid_links"),
+			tgbotapi.NewInlineKeyboardButtonData("♻️ Reset Link", "reset_link_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "link_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -81,6 +94,9 @@ Fitur ini akan mengambil link undangan dari grup WhatsApp yang Anda pilih.
 
 // ShowGetLinkMenuEdit menampilkan menu ambil link dengan EDIT message (no spam!)
 func ShowGetLinkMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	// Kembali ke mode ambil link biasa
+	delete(linkResetModes, chatID)
+
 	menuMsg := `🔗 **AMBIL LINK GRUP**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -92,6 +108,8 @@ Fitur ini akan mengambil link undangan dari grup WhatsApp yang Anda pilih.
 🔍 **Cari Manual** - Ketik nama/kata kunci grup
 📋 **Lihat & Pilih** - Lihat daftar lalu pilih
 ⚡ **Ambil Semua** - Proses semua grup sekaligus
+📦 **Export Link Valid** - Link tersimpan yang masih berlaku (hanya yang lama dicek ulang)
+♻️ **Reset Link** - Buat ulang link jika link bocor
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -112,6 +130,10 @@ Fitur ini akan mengambil link undangan dari grup WhatsApp yang Anda pilih.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("⚡ Ambil Semua", "get_all_links"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📦 Export Link Valid", "export_valid_links"),
+			tgbotapi.NewInlineKeyboardButtonData("♻️ Reset Link", "reset_link_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "link_example"),
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
@@ -332,8 +354,9 @@ func HandleGroupNameInput(keyword string, chatID int64, telegramBot *tgbotapi.Bo
 		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
 		telegramBot.Send(errorMsg)
 
-		// Reset state
+		// Reset state (termasuk mode reset link agar alur berikutnya kembali ke ambil link biasa)
 		delete(linkGrupStates, chatID)
+		delete(linkResetModes, chatID)
 		return
 	}
 
@@ -386,8 +409,9 @@ Kata kunci: "%s"
 
 		telegramBot.Send(msg)
 
-		// Reset state
+		// Reset state (termasuk mode reset link agar alur berikutnya kembali ke ambil link biasa)
 		delete(linkGrupStates, chatID)
+		delete(linkResetModes, chatID)
 		return
 	}
 
@@ -479,8 +503,18 @@ func HandleDelayInput(delayStr string, chatID int64, client *whatsmeow.Client, t
 	// Reset state
 	state.WaitingForDelay = false
 
+	// Mode reset link: minta konfirmasi dulu karena link lama akan langsung tidak berlaku
+	if linkResetModes[chatID] {
+		state.WaitingForResetConfirm = true
+		state.DelaySeconds = delay
+		ShowResetLinkConfirm(chatID, telegramBot)
+		return
+	}
+
 	// Start processing
-	go ProcessGetLinks(state.SelectedGroups, delay, chatID, client, telegramBot, state.Keyword)
+	StartWithAdminPreflight(chatID, "Ambil Link", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessGetLinks(allowed, delay, chatID, client, telegramBot, state.Keyword)
+	})
 
 	// Clear state
 	delete(linkGrupStates, chatID)
@@ -488,7 +522,14 @@ func HandleDelayInput(delayStr string, chatID int64, client *whatsmeow.Client, t
 
 // ProcessGetLinks processes link extraction with delay
 func ProcessGetLinks(groups []GroupLinkInfo, delay int, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, keyword string) {
+	processGroupLinks(groups, delay, chatID, client, telegramBot, keyword, false)
+}
+
+// processGroupLinks menjalankan ambil link (reset=false) atau reset link (reset=true) dengan format hasil yang sama
+// Saat reset, link lama & baru dicatat ke riwayat dan dikirim tambahan file CSV
+func processGroupLinks(groups []GroupLinkInfo, delay int, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, keyword string, reset bool) {
 	totalGroups := len(groups)
+	resetRecords := []inviteLinkResetRecord{}
 
 	startMsg := fmt.Sprintf(`🚀 **MEMULAI PROSES**
 
@@ -558,6 +599,9 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 		// Create temporary file for large results
 		timestamp := time.Now().Format("20060102_150405")
 		tempFileName = fmt.Sprintf("group_links_%s.txt", timestamp)
+		if reset {
+			tempFileName = fmt.Sprintf("group_links_reset_%s.txt", timestamp)
+		}
 		var err error
 		tempFile, err = os.Create(tempFileName)
 		if err != nil {
@@ -568,7 +612,19 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 		// Note: Tidak perlu write header, langsung mulai dengan grup pertama
 	}
 
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	jobName := "Ambil Link"
+	if reset {
+		jobName = "Reset Link"
+	}
+	job := StartGroupJob(chatID, jobName, groupJobItems(groups))
+	defer job.Finish()
+
 	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
 		// IMPORTANT: Ambil active client di setiap iterasi untuk proses panjang!
 		// Ini mencegah masalah client stale setelah berjam-jam
 		activeClient := GetWhatsAppClient()
@@ -616,9 +672,13 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 		}
 
 		// Get invite link dengan active client
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		ctx, cancel := context.WithTimeout(job.Context(), 15*time.Second)
 		defer cancel() // FIXED: Use defer to ensure cancellation
-		link, err := activeClient.GetGroupInviteLink(ctx, jid, false)
+		oldLink := ""
+		if reset {
+			oldLink = currentGroupInviteLink(ctx, activeClient, group.JID, jid)
+		}
+		link, err := activeClient.GetGroupInviteLink(ctx, jid, reset)
 
 		if err != nil {
 			failedCount++
@@ -640,6 +700,25 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 			}
 		} else {
 			successCount++
+			// Simpan ke registry link per akun (dipakai Export Link Valid)
+			recordInviteLink(activeClient, group.JID, link)
+			if reset {
+				// Catat link lama & baru ke riwayat (sekaligus update cache link grup)
+				resetAt := time.Now()
+				if err := utils.SaveGroupInviteLinkReset(group.JID, oldLink, link, resetAt); err != nil {
+					utils.GetGrupLogger().Warn("processGroupLinks: Gagal menyimpan riwayat reset link %s: %v", group.JID, err)
+				}
+				resetRecords = append(resetRecords, inviteLinkResetRecord{
+					Name:    group.Name,
+					JID:     group.JID,
+					OldLink: oldLink,
+					NewLink: link,
+					ResetAt: resetAt,
+				})
+			} else {
+				// Simpan ke cache metadata agar bisa dipakai template {{.InviteLink}}
+				utils.SaveGroupInviteLink(group.JID, link)
+			}
 			successMsg := fmt.Sprintf("✅ **%s**\n   🔗 %s", group.Name, link)
 			if useFileExport && tempFile != nil {
 				// Format sederhana: Nama Grup, lalu link di bawahnya (sesuai permintaan user)
@@ -788,12 +867,12 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 
 		// Delay before next request
 		if i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 
 		// Delay before next request
 		if i < len(groups)-1 {
-			time.Sleep(time.Duration(delay) * time.Second)
+			job.Sleep(time.Duration(delay) * time.Second)
 		}
 	}
 
@@ -830,7 +909,7 @@ Proses dimulai... Harap tunggu!`, delay, totalGroups*delay)
 	// Send final result (smart batching or file export)
 	if useFileExport && tempFile != nil {
 		// For large results, send as file
-		summaryMsg := fmt.Sprintf(`🎉 **PROSES SELESAI!**
+		summaryMsg := job.PausedNote() + fmt.Sprintf(`🎉 **PROSES SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -904,7 +983,7 @@ File akan dikirim sebentar lagi...`, totalGroups, successCount, failedCount, tim
 		}
 	} else if totalGroups > 10 {
 		// Send summary first
-		summaryMsg := fmt.Sprintf(`🎉 **PROSES SELESAI!**
+		summaryMsg := job.PausedNote() + fmt.Sprintf(`🎉 **PROSES SELESAI!**
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -1024,11 +1103,17 @@ File akan dikirim sebentar lagi...`, totalGroups, successCount, failedCount, tim
 
 		telegramBot.Send(msg)
 	}
+
+	// Reset link: kirim juga CSV berisi link lama, link baru dan waktu reset
+	if reset && len(resetRecords) > 0 {
+		sendInviteLinkResetCSV(chatID, resetRecords, telegramBot)
+	}
 }
 
 // CancelGetLink cancels the get link process
 func CancelGetLink(chatID int64, telegramBot *tgbotapi.BotAPI) {
 	delete(linkGrupStates, chatID)
+	delete(linkResetModes, chatID)
 
 	msg := tgbotapi.NewMessage(chatID, "❌ Proses ambil link dibatalkan.")
 	telegramBot.Send(msg)
@@ -1237,8 +1322,9 @@ func HandleFileInputForGetLink(fileID string, chatID int64, telegramBot *tgbotap
 
 		telegramBot.Send(msg)
 
-		// Reset state
+		// Reset state (termasuk mode reset link agar alur berikutnya kembali ke ambil link biasa)
 		delete(linkGrupStates, chatID)
+		delete(linkResetModes, chatID)
 		return
 	}
 
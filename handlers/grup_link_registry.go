@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+const (
+	// Link yang dicek lebih lama dari ini dianggap perlu divalidasi ulang
+	inviteLinkStaleAfter = 24 * time.Hour
+	// Maksimal link yang divalidasi per putaran background (agar tidak kena rate limit)
+	inviteLinkCheckBatch = 50
+	// Jeda antar request ke WhatsApp saat validasi/refresh link
+	inviteLinkRequestDelay = 2 * time.Second
+)
+
+// recordInviteLink menyimpan link yang berhasil diambil ke registry akun yang sedang aktif
+func recordInviteLink(client *whatsmeow.Client, groupJID, link string) {
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		return
+	}
+	if err := utils.SaveInviteLinkRecord(accountID, groupJID, link); err != nil {
+		utils.GetGrupLogger().Warn("recordInviteLink: Gagal menyimpan link %s ke registry: %v", groupJID, err)
+	}
+}
+
+// checkInviteLink memvalidasi link dengan GetGroupInfoFromLink (tanpa join grup)
+// Return status registry, atau error jika pengecekan gagal (timeout, koneksi, dll)
+func checkInviteLink(ctx context.Context, client *whatsmeow.Client, groupJID, link string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	info, err := client.GetGroupInfoFromLink(ctx, link)
+	if errors.Is(err, whatsmeow.ErrInviteLinkRevoked) || errors.Is(err, whatsmeow.ErrInviteLinkInvalid) {
+		return utils.InviteLinkStatusRevoked, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Link valid tapi mengarah ke grup lain (data registry salah) dianggap tidak valid
+	if info == nil || info.JID.String() != groupJID {
+		return utils.InviteLinkStatusRevoked, nil
+	}
+	return utils.InviteLinkStatusValid, nil
+}
+
+// StartPeriodicInviteLinkValidation memulai validasi berkala link di registry untuk mendeteksi link yang sudah dicabut
+// Setiap putaran hanya memeriksa link valid yang paling lama belum dicek (maksimal inviteLinkCheckBatch)
+func StartPeriodicInviteLinkValidation(intervalTime time.Duration) {
+	go func() {
+		logger := utils.GetGrupLogger()
+		logger.Info("🚀 Periodic invite link validation started (interval: %v)", intervalTime)
+
+		ticker := time.NewTicker(intervalTime)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			performInviteLinkValidation(intervalTime)
+		}
+	}()
+}
+
+// performInviteLinkValidation memeriksa link registry semua akun yang terhubung, bukan hanya akun aktif
+func performInviteLinkValidation(maxAge time.Duration) {
+	am := GetAccountManager()
+	for _, account := range am.GetAllAccounts() {
+		// Ambil langsung dari map: GetClient ikut memperbarui waktu terakhir dipakai akun
+		am.mutex.RLock()
+		client := am.clients[account.ID]
+		am.mutex.RUnlock()
+
+		validateAccountInviteLinks(client, account.BotDataDBPath, maxAge)
+	}
+}
+
+// validateAccountInviteLinks memeriksa link registry satu akun (dbPath = database akun) yang sudah lebih lama
+// dari maxAge sejak dicek terakhir
+func validateAccountInviteLinks(client *whatsmeow.Client, dbPath string, maxAge time.Duration) {
+	if client == nil || client.Store.ID == nil || !client.IsConnected() {
+		return
+	}
+
+	logger := utils.GetGrupLogger()
+	records, err := utils.GetInviteLinkRecordsAt(dbPath, clientAccountID(client))
+	if err != nil {
+		logger.Debug("performInviteLinkValidation: Gagal membaca registry %s: %v", dbPath, err)
+		return
+	}
+
+	checked, revoked := 0, 0
+	for _, record := range records {
+		if checked >= inviteLinkCheckBatch {
+			break
+		}
+		// Link yang sudah dicabut tidak akan aktif lagi, tidak perlu dicek ulang
+		if record.Status != utils.InviteLinkStatusValid || time.Since(record.CheckedAt) < maxAge {
+			continue
+		}
+		if !client.IsConnected() {
+			break
+		}
+
+		if checked > 0 {
+			time.Sleep(inviteLinkRequestDelay)
+		}
+		checked++
+
+		status, err := checkInviteLink(context.Background(), client, record.GroupJID, record.InviteLink)
+		if err != nil {
+			logger.Debug("performInviteLinkValidation: Gagal cek link %s: %v", record.GroupJID, err)
+			continue
+		}
+		if status == utils.InviteLinkStatusRevoked {
+			revoked++
+		}
+		utils.UpdateInviteLinkStatusAt(dbPath, record.AccountID, record.GroupJID, status)
+	}
+
+	if checked > 0 {
+		logger.Info("performInviteLinkValidation: %d link akun %s dicek, %d sudah tidak berlaku", checked, clientAccountID(client), revoked)
+	}
+}
+
+// ExportValidInviteLinks mengirim file link undangan yang masih berlaku untuk semua grup
+// Link dari registry yang masih baru dipakai langsung; hanya entri lama yang divalidasi ulang,
+// dan hanya grup tanpa link valid yang diambil ulang dari WhatsApp
+func ExportValidInviteLinks(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	groupsMap, err := utils.GetAllGroupsFromDB()
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal memuat daftar grup"))
+		errorMsg.ParseMode = "Markdown"
+		telegramBot.Send(errorMsg)
+		return
+	}
+	if len(groupsMap) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tidak ada grup yang terdeteksi."))
+		return
+	}
+
+	accountID := clientAccountID(client)
+	records, err := utils.GetInviteLinkRecordsAt("", accountID)
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal memuat registry link"))
+		errorMsg.ParseMode = "Markdown"
+		telegramBot.Send(errorMsg)
+		return
+	}
+	registry := make(map[string]utils.InviteLinkRecord, len(records))
+	for _, record := range records {
+		registry[record.GroupJID] = record
+	}
+
+	sortedGroups := utils.SortGroupsNaturally(groupsMap)
+	groups := make([]GroupLinkInfo, 0, len(sortedGroups))
+	for _, group := range sortedGroups {
+		groups = append(groups, GroupLinkInfo{JID: group.JID, Name: group.Name})
+	}
+
+	loadingMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("⏳ Menyiapkan link valid untuk %d grup...", len(groups)))
+	loadingSent, _ := telegramBot.Send(loadingMsg)
+
+	job := StartGroupJob(chatID, "Export Link Valid", groupJobItems(groups))
+	defer job.Finish()
+
+	var content strings.Builder
+	var failed []string
+	fromRegistry, revalidated, refetched := 0, 0, 0
+	requested := false
+
+	for i, group := range groups {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan
+		}
+
+		activeClient := GetWhatsAppClient()
+		if activeClient == nil {
+			activeClient = client
+		}
+
+		// Jeda hanya jika grup sebelumnya mengirim request ke WhatsApp
+		if requested {
+			job.Sleep(inviteLinkRequestDelay)
+			requested = false
+		}
+
+		link := ""
+		record, ok := registry[group.JID]
+		if ok && record.Status == utils.InviteLinkStatusValid {
+			if time.Since(record.CheckedAt) < inviteLinkStaleAfter {
+				link = record.InviteLink
+				fromRegistry++
+			} else if activeClient != nil && activeClient.IsConnected() {
+				requested = true
+				status, err := checkInviteLink(job.Context(), activeClient, group.JID, record.InviteLink)
+				if err == nil {
+					utils.UpdateInviteLinkStatusAt("", accountID, group.JID, status)
+					if status == utils.InviteLinkStatusValid {
+						link = record.InviteLink
+						revalidated++
+					}
+				}
+			}
+		}
+
+		// Belum ada link valid: ambil ulang dari WhatsApp
+		if link == "" {
+			if activeClient == nil || !activeClient.IsConnected() {
+				failed = append(failed, fmt.Sprintf("%s (client tidak terhubung)", group.Name))
+				continue
+			}
+			if requested {
+				job.Sleep(inviteLinkRequestDelay)
+			}
+			requested = true
+
+			newLink, err := fetchInviteLink(job.Context(), activeClient, group.JID)
+			if err != nil {
+				failed = append(failed, fmt.Sprintf("%s (%v)", group.Name, err))
+				continue
+			}
+			link = newLink
+			refetched++
+		}
+
+		content.WriteString(fmt.Sprintf("%s\n\n%s\n\n", group.Name, link))
+
+		if (i+1)%10 == 0 && loadingSent.MessageID != 0 {
+			percent := (i + 1) * 100 / len(groups)
+			progress := tgbotapi.NewEditMessageText(chatID, loadingSent.MessageID,
+				fmt.Sprintf("⏳ Menyiapkan link valid...\n\n%s %d%%\n%d / %d grup", generateProgressBar(percent), percent, i+1, len(groups)))
+			telegramBot.Send(progress)
+		}
+	}
+
+	if loadingSent.MessageID != 0 {
+		telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, loadingSent.MessageID))
+	}
+
+	successCount := fromRegistry + revalidated + refetched
+	summary := job.PausedNote() + fmt.Sprintf(`🎉 **EXPORT LINK VALID SELESAI**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📊 **Ringkasan:**
+• Total: %d grup
+• ✅ Berhasil: %d
+  • 🗂️ Dari registry: %d
+  • 🔎 Divalidasi ulang: %d
+  • 🔄 Diambil ulang: %d
+• ❌ Gagal: %d`, len(groups), successCount, fromRegistry, revalidated, refetched, len(failed))
+
+	if len(failed) > 0 {
+		summary += "\n\n**Gagal:**\n"
+		for i, name := range failed {
+			if i >= 10 {
+				summary += fmt.Sprintf("... dan %d grup lainnya\n", len(failed)-10)
+				break
+			}
+			summary += fmt.Sprintf("• %s\n", name)
+		}
+	}
+
+	summaryMsg := tgbotapi.NewMessage(chatID, summary)
+	summaryMsg.ParseMode = "Markdown"
+	if _, err := telegramBot.Send(summaryMsg); err != nil {
+		// Nama grup bisa merusak Markdown, kirim ulang sebagai teks biasa
+		summaryMsg.ParseMode = ""
+		telegramBot.Send(summaryMsg)
+	}
+
+	if successCount == 0 {
+		return
+	}
+
+	fileName := fmt.Sprintf("group_links_valid_%s.txt", time.Now().Format("20060102_150405"))
+	fileMsg := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: []byte(content.String())})
+	fileMsg.Caption = fmt.Sprintf("🔗 Link Grup WhatsApp (Valid)\n\n📊 %d grup\n📅 %s", successCount, time.Now().Format("02 Jan 2006 15:04"))
+	fileMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔗 Menu Link", "get_link_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Menu Grup", "grup"),
+		),
+	)
+	if _, err := telegramBot.Send(fileMsg); err != nil {
+		utils.GetGrupLogger().Error("ExportValidInviteLinks: Gagal mengirim file: %v", err)
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal mengirim file: %v", err)))
+	}
+}
+
+// fetchInviteLink mengambil link undangan grup dari WhatsApp lalu menyimpannya ke registry dan cache metadata
+func fetchInviteLink(ctx context.Context, client *whatsmeow.Client, groupJID string) (string, error) {
+	jid, err := types.ParseJID(groupJID)
+	if err != nil {
+		return "", fmt.Errorf("JID tidak valid")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	link, err := client.GetGroupInviteLink(ctx, jid, false)
+	if err != nil {
+		if errors.Is(err, whatsmeow.ErrGroupInviteLinkUnauthorized) {
+			return "", fmt.Errorf("bot bukan admin")
+		}
+		return "", err
+	}
+
+	recordInviteLink(client, groupJID, link)
+	utils.SaveGroupInviteLink(groupJID, link)
+	return link, nil
+}
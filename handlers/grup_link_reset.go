@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// inviteLinkResetRecord menyimpan hasil reset link satu grup untuk export CSV
+type inviteLinkResetRecord struct {
+	Name    string
+	JID     string
+	OldLink string
+	NewLink string
+	ResetAt time.Time
+}
+
+// linkResetModes menandai chat yang sedang memilih grup untuk reset link (bukan ambil link biasa)
+// Disimpan terpisah dari LinkGrupState karena alur pilih grup membuat ulang state tersebut
+var linkResetModes = make(map[int64]bool)
+
+// ShowResetLinkMenuEdit menampilkan menu reset link undangan grup (EDIT message)
+func ShowResetLinkMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	linkResetModes[chatID] = true
+	delete(linkGrupStates, chatID)
+
+	menuMsg := `♻️ **RESET LINK GRUP**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Fitur ini membuat ulang link undangan grup. Gunakan saat link grup bocor/tersebar ke orang yang tidak diinginkan.
+
+**📋 Pilihan Metode:**
+
+🔍 **Cari Manual** - Ketik nama/kata kunci grup
+📋 **Lihat & Pilih** - Lihat daftar lalu pilih
+⚡ **Reset Semua** - Proses semua grup sekaligus
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⚠️ **Catatan Penting:**
+• Link lama langsung TIDAK BERLAKU setelah direset
+• Bot harus menjadi admin grup
+• Link lama & baru dicatat ke riwayat beserta waktunya
+• Hasil dikirim dalam format yang sama seperti Ambil Link + file CSV
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Pilih metode yang Anda inginkan`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Lihat & Pilih", "show_group_list_link"),
+			tgbotapi.NewInlineKeyboardButtonData("🔍 Cari Manual", "start_get_link"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚡ Reset Semua", "select_all_link"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "get_link_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, menuMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// ShowResetLinkConfirm menampilkan konfirmasi terakhir sebelum link grup direset
+func ShowResetLinkConfirm(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := linkGrupStates[chatID]
+	if state == nil {
+		return
+	}
+
+	confirmMsg := fmt.Sprintf(`⚠️ **KONFIRMASI RESET LINK**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📊 **Total grup:** %d grup
+⏱️ **Delay:** %d detik/grup
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Link undangan lama dari semua grup di atas akan **langsung tidak berlaku** dan diganti link baru.
+
+Lanjutkan?`, len(state.SelectedGroups), state.DelaySeconds)
+
+	msg := tgbotapi.NewMessage(chatID, confirmMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("♻️ Ya, Reset Link", "reset_link_confirm"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_get_link"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// ConfirmResetLinks memulai proses reset link setelah user konfirmasi
+func ConfirmResetLinks(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := linkGrupStates[chatID]
+	if state == nil || !state.WaitingForResetConfirm {
+		msg := tgbotapi.NewMessage(chatID, "❌ Tidak ada proses reset link yang menunggu konfirmasi.")
+		telegramBot.Send(msg)
+		delete(linkResetModes, chatID)
+		return
+	}
+
+	StartWithAdminPreflight(chatID, "Reset Link", state.SelectedGroups, requireAdmin, client, telegramBot, func(allowed []GroupLinkInfo) {
+		ProcessResetLinks(allowed, state.DelaySeconds, chatID, client, telegramBot, state.Keyword)
+	})
+
+	delete(linkGrupStates, chatID)
+	delete(linkResetModes, chatID)
+}
+
+// ProcessResetLinks mereset link undangan grup terpilih dengan delay, mencatat link lama & baru,
+// lalu mengirim link baru dalam format yang sama seperti ProcessGetLinks ditambah file CSV
+func ProcessResetLinks(groups []GroupLinkInfo, delay int, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI, keyword string) {
+	processGroupLinks(groups, delay, chatID, client, telegramBot, keyword, true)
+}
+
+// currentGroupInviteLink mengambil link lama sebelum direset (dari WhatsApp, fallback ke cache metadata)
+func currentGroupInviteLink(ctx context.Context, client *whatsmeow.Client, groupJID string, jid types.JID) string {
+	link, err := client.GetGroupInviteLink(ctx, jid, false)
+	if err == nil {
+		return link
+	}
+
+	if meta, metaErr := utils.GetGroupMetadata(groupJID); metaErr == nil && meta != nil {
+		return meta.InviteLink
+	}
+	return ""
+}
+
+// sendInviteLinkResetCSV mengirim file CSV berisi link lama, link baru dan waktu reset setiap grup
+func sendInviteLinkResetCSV(chatID int64, records []inviteLinkResetRecord, telegramBot *tgbotapi.BotAPI) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"nama_grup", "jid", "link_lama", "link_baru", "waktu_reset"})
+	for _, record := range records {
+		writer.Write([]string{
+			record.Name,
+			record.JID,
+			record.OldLink,
+			record.NewLink,
+			record.ResetAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		utils.GetGrupLogger().Error("sendInviteLinkResetCSV: Gagal membuat CSV: %v", err)
+		return
+	}
+
+	fileName := fmt.Sprintf("group_links_reset_%s.csv", time.Now().Format("20060102_150405"))
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: buf.Bytes()})
+	doc.Caption = fmt.Sprintf("♻️ Riwayat Reset Link\n\n📊 %d grup direset\n📅 %s",
+		len(records), time.Now().Format("02 Jan 2006 15:04"))
+	if _, err := telegramBot.Send(doc); err != nil {
+		utils.GetGrupLogger().Error("sendInviteLinkResetCSV: Gagal mengirim file: %v", err)
+	}
+}
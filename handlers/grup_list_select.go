@@ -34,12 +34,14 @@ func ShowGroupListForLink(telegramBot *tgbotapi.BotAPI, chatID int64, page int)
 	if err != nil {
 		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
 		telegramBot.Send(errorMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
 	if len(groupsMap) == 0 {
 		noDataMsg := tgbotapi.NewMessage(chatID, "❌ Tidak ada grup yang terdeteksi.\n\nGrup akan otomatis terdeteksi saat bot menerima pesan dari grup.")
 		telegramBot.Send(noDataMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
@@ -159,12 +161,14 @@ func ShowGroupListForLinkEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messag
 	if err != nil {
 		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("❌ Error: %v", err))
 		telegramBot.Send(editMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
 	if len(groupsMap) == 0 {
 		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Tidak ada grup yang terdeteksi.\n\nGrup akan otomatis terdeteksi saat bot menerima pesan dari grup.")
 		telegramBot.Send(editMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
@@ -404,12 +408,14 @@ func GetAllLinksDirectly(chatID int64, telegramBot *tgbotapi.BotAPI) {
 	if err != nil {
 		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
 		telegramBot.Send(errorMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
 	if len(groupsMap) == 0 {
 		noDataMsg := tgbotapi.NewMessage(chatID, "❌ Tidak ada grup yang terdeteksi.")
 		telegramBot.Send(noDataMsg)
+		delete(linkResetModes, chatID)
 		return
 	}
 
@@ -0,0 +1,663 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// MemberRequestState menyimpan hasil scan permintaan masuk grup per chat
+type MemberRequestState struct {
+	Groups          []memberRequestGroup
+	Current         int // Index grup yang sedang dibuka
+	WaitingForRules bool
+}
+
+// memberRequestGroup adalah grup (kita admin & approval aktif) beserta permintaan masuk yang pending
+type memberRequestGroup struct {
+	JID      types.JID
+	Name     string
+	Requests []types.GroupParticipantRequest
+}
+
+var (
+	memberRequestStates = make(map[int64]*MemberRequestState)
+	// Melindungi memberRequestStates beserta isinya (tombol setujui/tolak diproses di goroutine)
+	memberRequestMutex sync.Mutex
+)
+
+const (
+	// Maksimal pemohon yang ditampilkan (dan diberi tombol) per halaman grup
+	memberRequestDisplayLimit = 10
+	// Jeda antar request scan permintaan per grup
+	memberRequestScanDelay = 500 * time.Millisecond
+)
+
+// ScanMemberRequests mencari grup yang kita kelola (admin + persetujuan anggota aktif) lalu mengambil permintaan pending
+func ScanMemberRequests(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	loading := tgbotapi.NewEditMessageText(chatID, messageID, "⏳ Memindai grup dengan persetujuan anggota aktif...")
+	telegramBot.Send(loading)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	joinedGroups, err := client.GetJoinedGroups(ctx)
+	cancel()
+	if err != nil {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengambil daftar grup"))
+		editMsg.ParseMode = "Markdown"
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	// Hanya grup dengan approval aktif dan akun ini admin yang punya antrean permintaan
+	var managed []*types.GroupInfo
+	for _, group := range joinedGroups {
+		if group == nil || !group.IsJoinApprovalRequired {
+			continue
+		}
+		for _, participant := range group.Participants {
+			if (participant.IsAdmin || participant.IsSuperAdmin) && (isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber)) {
+				managed = append(managed, group)
+				break
+			}
+		}
+	}
+
+	state := &MemberRequestState{}
+	for i, group := range managed {
+		if i > 0 {
+			time.Sleep(memberRequestScanDelay)
+		}
+		if i%10 == 0 {
+			progress := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("⏳ Memeriksa permintaan masuk... (%d/%d grup)", i, len(managed)))
+			telegramBot.Send(progress)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		requests, err := client.GetGroupRequestParticipants(ctx, group.JID)
+		cancel()
+		if err != nil {
+			utils.GetGrupLogger().Debug("ScanMemberRequests: Gagal ambil permintaan %s: %v", group.JID, err)
+			continue
+		}
+		if len(requests) > 0 {
+			state.Groups = append(state.Groups, memberRequestGroup{JID: group.JID, Name: group.Name, Requests: requests})
+		}
+	}
+
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+	memberRequestStates[chatID] = state
+	showMemberRequestGroupsEdit(state, len(managed), chatID, messageID, telegramBot)
+}
+
+// showMemberRequestGroupsEdit menampilkan daftar grup yang punya permintaan masuk pending (memberRequestMutex harus dipegang)
+func showMemberRequestGroupsEdit(state *MemberRequestState, managedCount int, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	total := 0
+	for _, group := range state.Groups {
+		total += len(group.Requests)
+	}
+
+	var text strings.Builder
+	text.WriteString("📥 PERMINTAAN MASUK GRUP\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if managedCount >= 0 {
+		text.WriteString(fmt.Sprintf("👑 Grup dikelola (approval ON): %d\n", managedCount))
+	}
+	text.WriteString(fmt.Sprintf("📋 Grup dengan permintaan: %d\n", len(state.Groups)))
+	text.WriteString(fmt.Sprintf("⏳ Total permintaan pending: %d\n\n", total))
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(state.Groups) == 0 {
+		text.WriteString("✅ Tidak ada permintaan masuk yang menunggu persetujuan.")
+	} else {
+		text.WriteString("💡 Pilih grup untuk menyetujui/menolak permintaan.")
+		for i, group := range state.Groups {
+			label := fmt.Sprintf("%s (%d)", group.Name, len(group.Requests))
+			if len([]rune(label)) > 40 {
+				label = string([]rune(group.Name)[:30]) + fmt.Sprintf("… (%d)", len(group.Requests))
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("mreq_group_%d", i)),
+			))
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🤖 Terapkan Aturan ke Semua Grup", "mreq_rules_apply"),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Pindai Ulang", "member_requests_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("⚙️ Aturan Auto-Approve", "mreq_rules_menu"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "change_join_approval_menu"),
+		),
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// ShowMemberRequestGroupEdit menampilkan pemohon di satu grup dengan tombol setujui/tolak
+func ShowMemberRequestGroupEdit(index int, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+	showMemberRequestGroupEdit(index, chatID, messageID, client, telegramBot)
+}
+
+// showMemberRequestGroupEdit menampilkan pemohon di satu grup (memberRequestMutex harus dipegang)
+// Tombol memakai JID pemohon, bukan posisi, karena antrean menyusut setelah setiap aksi
+func showMemberRequestGroupEdit(index int, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := memberRequestStates[chatID]
+	if state == nil || index < 0 || index >= len(state.Groups) {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Data permintaan sudah kedaluwarsa. Silakan pindai ulang.")
+		telegramBot.Send(editMsg)
+		return
+	}
+	state.Current = index
+	group := state.Groups[index]
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("📥 PERMINTAAN MASUK\n\n👥 %s\n⏳ %d permintaan\n\n", group.Name, len(group.Requests)))
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, request := range group.Requests {
+		if i >= memberRequestDisplayLimit {
+			text.WriteString(fmt.Sprintf("... dan %d permintaan lainnya (gunakan tombol massal)\n", len(group.Requests)-memberRequestDisplayLimit))
+			break
+		}
+		text.WriteString(fmt.Sprintf("%d. %s\n   🕒 %s lalu\n", i+1, requesterLabel(client, request.JID), formatStateDuration(time.Since(request.RequestedAt))))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Setujui %d", i+1), "mreq_approve_"+request.JID.String()),
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("❌ Tolak %d", i+1), "mreq_reject_"+request.JID.String()),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Setujui Semua", "mreq_approve_all"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Tolak Semua", "mreq_reject_all"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Daftar Grup", "mreq_groups"),
+		),
+	)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// ShowMemberRequestGroupsEdit kembali ke daftar grup hasil scan terakhir
+func ShowMemberRequestGroupsEdit(chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+	state := memberRequestStates[chatID]
+	if state == nil {
+		state = &MemberRequestState{}
+	}
+	showMemberRequestGroupsEdit(state, -1, chatID, messageID, telegramBot)
+}
+
+// requesterPhone mengembalikan nomor pemohon (LID dikonversi ke nomor jika mapping tersedia)
+func requesterPhone(client *whatsmeow.Client, jid types.JID) string {
+	if jid.Server == types.HiddenUserServer {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if pn, err := client.Store.LIDs.GetPNForLID(ctx, jid); err == nil && !pn.IsEmpty() {
+			return pn.User
+		}
+		return ""
+	}
+	return jid.User
+}
+
+// requesterLabel memformat pemohon untuk ditampilkan (+nomor, atau LID jika nomor tersembunyi)
+func requesterLabel(client *whatsmeow.Client, jid types.JID) string {
+	if phone := requesterPhone(client, jid); phone != "" {
+		return "+" + phone
+	}
+	return jid.User + " (nomor tersembunyi)"
+}
+
+// updateMemberRequests menyetujui/menolak pemohon di grup lalu menghapusnya dari antrean lokal
+func updateMemberRequests(client *whatsmeow.Client, group *memberRequestGroup, jids []types.JID, action whatsmeow.ParticipantRequestChange) (int, error) {
+	if len(jids) == 0 {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	results, err := client.UpdateGroupRequestParticipants(ctx, group.JID, jids, action)
+	if err != nil {
+		return 0, err
+	}
+
+	// Hasil per peserta: error != 0 berarti gagal untuk pemohon tersebut
+	done := make(map[string]bool)
+	for _, result := range results {
+		if result.Error == 0 {
+			done[result.JID.User] = true
+		}
+	}
+	if len(results) == 0 {
+		for _, jid := range jids {
+			done[jid.User] = true
+		}
+	}
+
+	remaining := group.Requests[:0]
+	for _, request := range group.Requests {
+		if !done[request.JID.User] {
+			remaining = append(remaining, request)
+		}
+	}
+	group.Requests = remaining
+	return len(done), nil
+}
+
+// HandleMemberRequestAction memproses tombol setujui/tolak satu pemohon (requester = JID pemohon) atau semua (requester kosong)
+func HandleMemberRequestAction(approve bool, requester string, chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+
+	state := memberRequestStates[chatID]
+	if state == nil || state.Current < 0 || state.Current >= len(state.Groups) {
+		return
+	}
+	group := &state.Groups[state.Current]
+
+	var jids []types.JID
+	if requester == "" {
+		for _, request := range group.Requests {
+			jids = append(jids, request.JID)
+		}
+	} else if target, err := types.ParseJID(requester); err == nil {
+		for _, request := range group.Requests {
+			if request.JID.ToNonAD() == target.ToNonAD() {
+				jids = append(jids, request.JID)
+				break
+			}
+		}
+	}
+	if len(jids) == 0 {
+		// Pemohon sudah diproses (tombol dari tampilan lama): tampilkan ulang antrean terbaru
+		showMemberRequestGroupEdit(state.Current, chatID, messageID, client, telegramBot)
+		return
+	}
+
+	action := whatsmeow.ParticipantChangeReject
+	actionLabel := "ditolak"
+	if approve {
+		action = whatsmeow.ParticipantChangeApprove
+		actionLabel = "disetujui"
+	}
+
+	count, err := updateMemberRequests(client, group, jids, action)
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal memproses permintaan: %v", err))
+		telegramBot.Send(errorMsg)
+		return
+	}
+	utils.GetGrupLogger().Info("HandleMemberRequestAction: %d permintaan %s di grup %s", count, actionLabel, group.JID)
+
+	if len(group.Requests) == 0 {
+		state.Groups = append(state.Groups[:state.Current], state.Groups[state.Current+1:]...)
+		showMemberRequestGroupsEdit(state, -1, chatID, messageID, telegramBot)
+		return
+	}
+	showMemberRequestGroupEdit(state.Current, chatID, messageID, client, telegramBot)
+}
+
+// ApplyMemberRequestRulesToAll menyetujui pemohon yang cocok dengan aturan di semua grup hasil scan
+// Pemohon yang tidak cocok dibiarkan tetap pending
+func ApplyMemberRequestRulesToAll(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+
+	state := memberRequestStates[chatID]
+	if state == nil {
+		return
+	}
+
+	rules, err := utils.GetMemberRequestRulesAt("", clientAccountID(client))
+	if err != nil || len(rules.Patterns) == 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "⚠️ Belum ada aturan auto-approve.\n\nAtur dulu lewat menu ⚙️ Aturan Auto-Approve.")
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("⚙️ Aturan Auto-Approve", "mreq_rules_menu"),
+				tgbotapi.NewInlineKeyboardButtonData("🔙 Daftar Grup", "mreq_groups"),
+			),
+		)
+		editMsg.ReplyMarkup = &keyboard
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	approved, failed := 0, 0
+	remainingGroups := state.Groups[:0]
+	for i := range state.Groups {
+		group := state.Groups[i]
+		jids := matchingRequesters(client, rules, group.Requests)
+		if len(jids) > 0 {
+			count, err := updateMemberRequests(client, &group, jids, whatsmeow.ParticipantChangeApprove)
+			if err != nil {
+				failed++
+			}
+			approved += count
+			time.Sleep(memberRequestScanDelay)
+		}
+		if len(group.Requests) > 0 {
+			remainingGroups = append(remainingGroups, group)
+		}
+	}
+	state.Groups = remainingGroups
+
+	resultMsg := fmt.Sprintf("🤖 Aturan diterapkan\n\n✅ Disetujui: %d permintaan", approved)
+	if failed > 0 {
+		resultMsg += fmt.Sprintf("\n❌ Gagal di %d grup", failed)
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, resultMsg))
+	showMemberRequestGroupsEdit(state, -1, chatID, messageID, telegramBot)
+}
+
+// matchingRequesters mengembalikan JID pemohon yang nomornya cocok dengan aturan
+func matchingRequesters(client *whatsmeow.Client, rules *utils.MemberRequestRules, requests []types.GroupParticipantRequest) []types.JID {
+	var jids []types.JID
+	for _, request := range requests {
+		if rules.Matches(requesterPhone(client, request.JID)) {
+			jids = append(jids, request.JID)
+		}
+	}
+	return jids
+}
+
+// HasNewMembershipRequests mengecek apakah event grup berisi permintaan masuk baru
+func HasNewMembershipRequests(evt *events.GroupInfo) bool {
+	for _, node := range evt.UnknownChanges {
+		if node != nil && node.Tag == "created_membership_requests" {
+			return true
+		}
+	}
+	return false
+}
+
+// AutoApproveMembershipRequests menyetujui permintaan masuk yang cocok dengan aturan akun (dbPath = database akun sumber event)
+// Return jumlah yang disetujui; 0 jika auto-approve tidak aktif
+func AutoApproveMembershipRequests(client *whatsmeow.Client, dbPath string, groupJID types.JID) (int, error) {
+	if client == nil || client.Store.ID == nil {
+		return 0, nil
+	}
+
+	rules, err := utils.GetMemberRequestRulesAt(dbPath, client.Store.ID.User)
+	if err != nil || !rules.AutoEnabled || len(rules.Patterns) == 0 {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	requests, err := client.GetGroupRequestParticipants(ctx, groupJID)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+
+	group := &memberRequestGroup{JID: groupJID, Requests: requests}
+	return updateMemberRequests(client, group, matchingRequesters(client, rules, requests), whatsmeow.ParticipantChangeApprove)
+}
+
+// ShowMemberRequestRulesEdit menampilkan aturan auto-approve (allowlist nomor / prefix kode negara)
+func ShowMemberRequestRulesEdit(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	rules, err := utils.GetMemberRequestRulesAt("", clientAccountID(client))
+	if err != nil {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("❌ Gagal memuat aturan: %v", err))
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	var text strings.Builder
+	text.WriteString("⚙️ ATURAN AUTO-APPROVE\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	autoLabel := "🔴 OFF"
+	if rules.AutoEnabled {
+		autoLabel = "🟢 ON"
+	}
+	text.WriteString(fmt.Sprintf("🤖 Auto-approve saat ada permintaan baru: %s\n\n", autoLabel))
+
+	if len(rules.Patterns) == 0 {
+		text.WriteString("📋 Belum ada aturan.\n")
+	} else {
+		text.WriteString(fmt.Sprintf("📋 Aturan (%d):\n", len(rules.Patterns)))
+		for _, pattern := range rules.Patterns {
+			if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+				text.WriteString(fmt.Sprintf("• Prefix +%s\n", prefix))
+			} else {
+				text.WriteString(fmt.Sprintf("• Nomor +%s\n", pattern))
+			}
+		}
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("💡 Pemohon yang nomornya cocok akan disetujui, yang lain tetap menunggu keputusan manual.")
+
+	toggleLabel := "🟢 Aktifkan Auto"
+	if rules.AutoEnabled {
+		toggleLabel = "🔴 Matikan Auto"
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Ubah Aturan", "mreq_rules_edit"),
+			tgbotapi.NewInlineKeyboardButtonData(toggleLabel, "mreq_rules_toggle"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗑️ Hapus Semua Aturan", "mreq_rules_clear"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Daftar Grup", "mreq_groups"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text.String())
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartMemberRequestRulesInput meminta user mengetik daftar aturan baru
+func StartMemberRequestRulesInput(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	state := memberRequestStates[chatID]
+	if state == nil {
+		state = &MemberRequestState{}
+		memberRequestStates[chatID] = state
+	}
+	state.WaitingForRules = true
+	memberRequestMutex.Unlock()
+
+	promptMsg := `✏️ **UBAH ATURAN AUTO-APPROVE**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Ketik daftar nomor atau prefix, satu per baris (atau pisahkan dengan koma).
+Prefix ditandai dengan akhiran *, spasi dan tanda hubung di dalam nomor diabaikan.
+
+**Contoh:**
+62* (semua nomor Indonesia)
+6281* (prefix 6281)
++62 812-3456-7890 (nomor tertentu)
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⚠️ Aturan lama akan diganti seluruhnya.
+
+⏳ Ketik aturan...`
+
+	msg := tgbotapi.NewMessage(chatID, promptMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "mreq_rules_cancel"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleMemberRequestRulesInput menyimpan aturan yang diketik user
+func HandleMemberRequestRulesInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	if !IsWaitingForMemberRequestRules(chatID) {
+		return
+	}
+
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	patterns, invalid := parseMemberRequestPatterns(input)
+	if len(patterns) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tidak ada aturan yang valid!\n\nContoh: 62* atau 6281* atau 081234567890"))
+		return
+	}
+
+	rules, err := utils.GetMemberRequestRulesAt("", accountID)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal memuat aturan: %v", err)))
+		return
+	}
+	rules.Patterns = patterns
+	if err := utils.SaveMemberRequestRules(rules); err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan aturan"))
+		errorMsg.ParseMode = "Markdown"
+		telegramBot.Send(errorMsg)
+		return
+	}
+	memberRequestMutex.Lock()
+	if state := memberRequestStates[chatID]; state != nil {
+		state.WaitingForRules = false
+	}
+	memberRequestMutex.Unlock()
+
+	resultMsg := fmt.Sprintf("✅ %d aturan disimpan.", len(patterns))
+	if len(invalid) > 0 {
+		resultMsg += fmt.Sprintf("\n\n⚠️ Diabaikan (format tidak valid): %s", strings.Join(invalid, ", "))
+	}
+	msg := tgbotapi.NewMessage(chatID, resultMsg)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚙️ Lihat Aturan", "mreq_rules_menu"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// parseMemberRequestPatterns membaca daftar aturan (dipisah baris, koma atau titik koma)
+// Hanya akhiran * yang menandai prefix (62*, +6281*); entri lain dianggap nomor lengkap dan dinormalisasi
+// dengan cleanPhoneNumber. Return pattern valid dan entri yang ditolak
+func parseMemberRequestPatterns(input string) ([]string, []string) {
+	var patterns, invalid []string
+	seen := make(map[string]bool)
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == '\n' || r == ',' || r == ';'
+	})
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		var pattern string
+		if prefix, ok := strings.CutSuffix(field, "*"); ok {
+			// Prefix harus diawali kode negara (nomor peminta selalu format internasional)
+			digits := strings.Map(func(r rune) rune {
+				if r >= '0' && r <= '9' {
+					return r
+				}
+				return -1
+			}, prefix)
+			if digits != "" && !strings.HasPrefix(digits, "0") && len(digits) <= 15 {
+				pattern = digits + "*"
+			}
+		} else {
+			pattern = cleanPhoneNumber(field)
+		}
+
+		if pattern == "" {
+			invalid = append(invalid, field)
+			continue
+		}
+		if !seen[pattern] {
+			seen[pattern] = true
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns, invalid
+}
+
+// ToggleMemberRequestAutoApprove menyalakan/mematikan auto-approve berbasis event
+func ToggleMemberRequestAutoApprove(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	rules, err := utils.GetMemberRequestRulesAt("", accountID)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal memuat aturan: %v", err)))
+		return
+	}
+	rules.AutoEnabled = !rules.AutoEnabled
+	if err := utils.SaveMemberRequestRules(rules); err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal menyimpan pengaturan: %v", err)))
+		return
+	}
+	ShowMemberRequestRulesEdit(chatID, messageID, client, telegramBot)
+}
+
+// ClearMemberRequestRules menghapus semua aturan (auto-approve ikut dimatikan)
+func ClearMemberRequestRules(chatID int64, messageID int, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	accountID := clientAccountID(client)
+	if accountID == "" {
+		telegramBot.Send(tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung."))
+		return
+	}
+
+	rules := &utils.MemberRequestRules{AccountID: accountID}
+	if err := utils.SaveMemberRequestRules(rules); err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal menghapus aturan: %v", err)))
+		return
+	}
+	ShowMemberRequestRulesEdit(chatID, messageID, client, telegramBot)
+}
+
+// CancelMemberRequestRulesInput membatalkan input aturan
+func CancelMemberRequestRulesInput(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	memberRequestMutex.Lock()
+	if state := memberRequestStates[chatID]; state != nil {
+		state.WaitingForRules = false
+	}
+	memberRequestMutex.Unlock()
+	telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Ubah aturan dibatalkan."))
+}
+
+// IsWaitingForMemberRequestRules checks if user is typing auto-approve rules
+func IsWaitingForMemberRequestRules(chatID int64) bool {
+	memberRequestMutex.Lock()
+	defer memberRequestMutex.Unlock()
+	state := memberRequestStates[chatID]
+	return state != nil && state.WaitingForRules
+}
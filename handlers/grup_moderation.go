@@ -0,0 +1,695 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// ModerationState menyimpan state input aturan moderasi per chat
+type ModerationState struct {
+	WaitingForInput bool
+}
+
+var (
+	moderationStates = make(map[int64]*ModerationState)
+
+	moderationFloodMutex sync.Mutex
+	moderationFloodHits  = make(map[string][]time.Time) // key: akun|grup|pengirim
+)
+
+// Pola link undangan grup WhatsApp
+var moderationInviteLinkPattern = regexp.MustCompile(`(?i)chat\.whatsapp\.com/(invite/)?[A-Za-z0-9]{10,}`)
+
+const (
+	// WhatsApp menandai pesan "diteruskan berkali-kali" mulai skor ini
+	moderationForwardedManyTimesScore = 5
+	// Default jendela flood jika hanya jumlah pesan yang diisi
+	defaultModerationFloodWindow = 10
+	// Batas jumlah key flood di memori sebelum dibersihkan
+	moderationFloodMaxKeys = 5000
+)
+
+// ShowModerationMenuEdit menampilkan daftar aturan moderasi akun
+func ShowModerationMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildModerationMenu()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildModerationMenu membuat teks daftar aturan moderasi (plain text karena nama grup bebas)
+func buildModerationMenu() (string, tgbotapi.InlineKeyboardMarkup) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var text strings.Builder
+	text.WriteString("🛡️ MODERASI OTOMATIS\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	text.WriteString("Pesan dari non-admin di grup yang punya aturan diperiksa otomatis: link undangan grup, pesan diteruskan berkali-kali, flood, dan kata terlarang.\n\n")
+	text.WriteString("⚠️ Pelanggar diberi peringatan (atau pesannya dihapus), lalu dikeluarkan setelah N peringatan.\n")
+	text.WriteString("🛡️ Hanya berlaku di grup tempat akun ini admin. Setiap tindakan dikirim ke sini dengan tombol batalkan.\n")
+
+	rules, err := utils.GetModerationRulesAt("", clientAccountID(GetWhatsAppClient()))
+	if err != nil {
+		text.WriteString(fmt.Sprintf("\n❌ Gagal memuat aturan: %v\n", err))
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(rules) == 0 && err == nil {
+		text.WriteString("Belum ada aturan. Klik ➕ untuk menambahkan.")
+	}
+
+	for i, rule := range rules {
+		label := rule.TargetName
+		if label == "" {
+			label = rule.Target
+		}
+		icon := "👥"
+		if rule.IsTagTarget() {
+			icon = "🏷️"
+		}
+		text.WriteString(fmt.Sprintf("%d. %s %s\n", i+1, icon, label))
+		text.WriteString(fmt.Sprintf("   %s\n", moderationRuleSummary(&rule)))
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("🗑️ Hapus Aturan %d", i+1), "mod_delete_"+strconv.FormatInt(rule.ID, 10)),
+		))
+	}
+
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah / Ubah Aturan", "mod_add"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+		),
+	)
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// moderationRuleSummary meringkas isi aturan dalam satu baris
+func moderationRuleSummary(rule *utils.ModerationRule) string {
+	var checks []string
+	if rule.BlockInviteLinks {
+		checks = append(checks, "🔗 link")
+	}
+	if rule.BlockForwarded {
+		checks = append(checks, "↪️ forward")
+	}
+	if rule.FloodLimit > 0 {
+		checks = append(checks, fmt.Sprintf("🌊 flood %d/%ds", rule.FloodLimit, rule.FloodWindow))
+	}
+	if len(rule.Keywords) > 0 {
+		checks = append(checks, fmt.Sprintf("🚫 %d kata", len(rule.Keywords)))
+	}
+
+	action := "⚠️ peringatan"
+	if rule.Action == utils.ModerationActionDelete {
+		action = "🗑️ hapus pesan"
+	}
+	kick := "tidak dikeluarkan"
+	if rule.MaxWarnings > 0 {
+		kick = fmt.Sprintf("keluarkan setelah %dx", rule.MaxWarnings)
+	}
+	return fmt.Sprintf("%s • %s • %s", strings.Join(checks, ", "), action, kick)
+}
+
+// StartModerationInput meminta input aturan moderasi (target + opsi)
+func StartModerationInput(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	moderationStates[chatID] = &ModerationState{WaitingForInput: true}
+
+	promptMsg := `🛡️ **INPUT ATURAN MODERASI**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Format:**
+Baris 1: nama grup (persis) atau koleksi tag
+Baris berikutnya: opsi ` + "`kunci: nilai`" + `
+
+**Contoh:**
+` + "`tag:kota=Jakarta\nlink: ya\nforward: ya\nflood: 5/10\nkata: judi, slot, pinjol\naksi: hapus\nmaks: 3`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**⚙️ Opsi:**
+• ` + "`link`" + ` - tindak link undangan grup (ya/tidak)
+• ` + "`forward`" + ` - tindak pesan diteruskan berkali-kali (ya/tidak)
+• ` + "`flood`" + ` - maksimal pesan/detik per pengirim (contoh 5/10)
+• ` + "`kata`" + ` - kata terlarang, pisahkan dengan koma
+• ` + "`aksi`" + ` - peringatan atau hapus (hapus pesan untuk semua)
+• ` + "`maks`" + ` - keluarkan peserta setelah N peringatan (0 = tidak)
+
+💡 Target yang sama akan ditimpa dengan aturan baru.
+
+⏳ Menunggu input...`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "moderation_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleModerationInput menyimpan aturan moderasi dari input user lalu menampilkan daftar aturan
+func HandleModerationInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := moderationStates[chatID]
+	if state == nil || !state.WaitingForInput {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	target := strings.TrimSpace(lines[0])
+	if target == "" || len(lines) < 2 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Format salah. Baris 1 berisi nama grup / tag:key=value, baris berikutnya opsi aturan."))
+		return
+	}
+
+	rule := &utils.ModerationRule{
+		AccountID: clientAccountID(client),
+		Action:    utils.ModerationActionWarn,
+	}
+	var err error
+	rule.Target, rule.TargetName, err = resolveGroupOrTagTarget(target)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+
+	if err := parseModerationRuleOptions(lines[1:], rule); err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+	if !rule.BlockInviteLinks && !rule.BlockForwarded && rule.FloodLimit == 0 && len(rule.Keywords) == 0 {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Aturan kosong. Aktifkan minimal satu: link, forward, flood atau kata."))
+		return
+	}
+
+	if err := utils.SaveModerationRule(rule); err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan aturan moderasi"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+	delete(moderationStates, chatID)
+	utils.LogActivity("moderation_rule_save", fmt.Sprintf("Moderation rule saved for %s", rule.TargetName), chatID)
+
+	telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("✅ Aturan moderasi disimpan untuk %s\n%s", rule.TargetName, moderationRuleSummary(rule))))
+
+	text, keyboard := buildModerationMenu()
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// parseModerationRuleOptions membaca baris "kunci: nilai" ke dalam aturan
+func parseModerationRuleOptions(lines []string, rule *utils.ModerationRule) error {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("Baris tidak dikenali: %s (gunakan format kunci: nilai)", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "link":
+			enabled, err := parseCSVToggle(value)
+			if err != nil {
+				return fmt.Errorf("Nilai link harus ya/tidak")
+			}
+			rule.BlockInviteLinks = enabled
+		case "forward", "terusan":
+			enabled, err := parseCSVToggle(value)
+			if err != nil {
+				return fmt.Errorf("Nilai forward harus ya/tidak")
+			}
+			rule.BlockForwarded = enabled
+		case "flood":
+			limitText, windowText, hasWindow := strings.Cut(value, "/")
+			limit, err := strconv.Atoi(strings.TrimSpace(limitText))
+			if err != nil || limit < 0 {
+				return fmt.Errorf("Format flood salah. Contoh: flood: 5/10 (5 pesan per 10 detik)")
+			}
+			window := defaultModerationFloodWindow
+			if hasWindow {
+				window, err = strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(windowText), "s"))
+				if err != nil || window < 1 || window > 3600 {
+					return fmt.Errorf("Jendela flood harus 1-3600 detik")
+				}
+			}
+			if limit > 0 && limit < 2 {
+				return fmt.Errorf("Batas flood minimal 2 pesan")
+			}
+			rule.FloodLimit = limit
+			rule.FloodWindow = window
+		case "kata", "keyword", "keywords":
+			rule.Keywords = nil
+			seen := make(map[string]bool)
+			for _, word := range strings.Split(value, ",") {
+				word = strings.ToLower(strings.TrimSpace(word))
+				if word != "" && !seen[word] {
+					seen[word] = true
+					rule.Keywords = append(rule.Keywords, word)
+				}
+			}
+		case "aksi", "action":
+			switch strings.ToLower(value) {
+			case "peringatan", "warn", "tegur":
+				rule.Action = utils.ModerationActionWarn
+			case "hapus", "delete", "revoke":
+				rule.Action = utils.ModerationActionDelete
+			default:
+				return fmt.Errorf("Aksi harus peringatan atau hapus")
+			}
+		case "maks", "max":
+			maxWarnings, err := strconv.Atoi(value)
+			if err != nil || maxWarnings < 0 || maxWarnings > 20 {
+				return fmt.Errorf("Nilai maks harus 0-20")
+			}
+			rule.MaxWarnings = maxWarnings
+		default:
+			return fmt.Errorf("Opsi tidak dikenal: %s", key)
+		}
+	}
+	return nil
+}
+
+// DeleteModerationRuleEntry menghapus aturan moderasi lalu menampilkan ulang daftar
+func DeleteModerationRuleEntry(ruleID int64, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	utils.DeleteModerationRule(clientAccountID(GetWhatsAppClient()), ruleID)
+	ShowModerationMenuEdit(telegramBot, chatID, messageID)
+}
+
+// IsWaitingForModerationInput mengecek apakah user sedang mengisi aturan moderasi
+func IsWaitingForModerationInput(chatID int64) bool {
+	state := moderationStates[chatID]
+	return state != nil && state.WaitingForInput
+}
+
+// CancelModerationInput membatalkan input aturan moderasi
+func CancelModerationInput(chatID int64) {
+	delete(moderationStates, chatID)
+}
+
+// groupMessageText mengambil teks / caption pesan grup (dipakai moderasi dan pantau kata kunci)
+func groupMessageText(msg *waProto.Message) string {
+	if msg == nil {
+		return ""
+	}
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	}
+	return ""
+}
+
+// moderationContextInfo mengambil ContextInfo pesan (untuk status diteruskan)
+func moderationContextInfo(msg *waProto.Message) *waProto.ContextInfo {
+	if msg == nil {
+		return nil
+	}
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	}
+	return nil
+}
+
+// recordModerationFlood mencatat pesan pengirim dan mengecek apakah melewati batas flood
+// Jika melewati batas, hitungan pengirim di-reset agar satu flood hanya ditindak sekali
+func recordModerationFlood(key string, limit, windowSeconds int) bool {
+	moderationFloodMutex.Lock()
+	defer moderationFloodMutex.Unlock()
+
+	now := time.Now()
+	window := time.Duration(windowSeconds) * time.Second
+
+	if len(moderationFloodHits) > moderationFloodMaxKeys {
+		for k, hits := range moderationFloodHits {
+			if len(hits) == 0 || now.Sub(hits[len(hits)-1]) > window {
+				delete(moderationFloodHits, k)
+			}
+		}
+	}
+
+	var recent []time.Time
+	for _, hit := range moderationFloodHits[key] {
+		if now.Sub(hit) <= window {
+			recent = append(recent, hit)
+		}
+	}
+	recent = append(recent, now)
+
+	if len(recent) > limit {
+		delete(moderationFloodHits, key)
+		return true
+	}
+	moderationFloodHits[key] = recent
+	return false
+}
+
+// detectModerationViolation memeriksa pesan terhadap aturan. Return jenis pelanggaran dan detailnya ("" = aman)
+func detectModerationViolation(rule *utils.ModerationRule, accountID string, evt *events.Message) (string, string) {
+	// Flood dihitung untuk setiap pesan walau ada pelanggaran lain, agar hitungan tetap akurat
+	flooded := false
+	if rule.FloodLimit > 0 {
+		key := accountID + "|" + evt.Info.Chat.String() + "|" + evt.Info.Sender.User
+		flooded = recordModerationFlood(key, rule.FloodLimit, rule.FloodWindow)
+	}
+
+	text := groupMessageText(evt.Message)
+	if rule.BlockInviteLinks {
+		if link := moderationInviteLinkPattern.FindString(text); link != "" {
+			return utils.ViolationInviteLink, link
+		}
+	}
+	if len(rule.Keywords) > 0 && text != "" {
+		lower := strings.ToLower(text)
+		for _, keyword := range rule.Keywords {
+			if strings.Contains(lower, keyword) {
+				return utils.ViolationKeyword, keyword
+			}
+		}
+	}
+	if rule.BlockForwarded {
+		if info := moderationContextInfo(evt.Message); info.GetIsForwarded() && info.GetForwardingScore() >= moderationForwardedManyTimesScore {
+			return utils.ViolationForwarded, fmt.Sprintf("diteruskan %d kali", info.GetForwardingScore())
+		}
+	}
+	if flooded {
+		return utils.ViolationFlood, fmt.Sprintf("lebih dari %d pesan dalam %d detik", rule.FloodLimit, rule.FloodWindow)
+	}
+	return "", ""
+}
+
+// moderationViolationLabel mengembalikan label pelanggaran untuk pesan grup dan Telegram
+func moderationViolationLabel(violation string) string {
+	switch violation {
+	case utils.ViolationInviteLink:
+		return "link undangan grup"
+	case utils.ViolationForwarded:
+		return "pesan diteruskan berkali-kali"
+	case utils.ViolationFlood:
+		return "flood / spam pesan"
+	case utils.ViolationKeyword:
+		return "kata terlarang"
+	default:
+		return violation
+	}
+}
+
+// moderationActionLabel mengembalikan label tindakan moderasi
+func moderationActionLabel(action string) string {
+	switch action {
+	case utils.ModerationActionDelete:
+		return "🗑️ Pesan dihapus"
+	case utils.ModerationActionRemove:
+		return "🚫 Peserta dikeluarkan"
+	default:
+		return "⚠️ Peringatan"
+	}
+}
+
+// moderationAdminStatus mengecek apakah akun ini dan pengirim pesan adalah admin grup
+func moderationAdminStatus(client *whatsmeow.Client, info *types.GroupInfo, sender, senderAlt types.JID) (bool, bool) {
+	ownAdmin, senderAdmin := false, false
+	for _, participant := range info.Participants {
+		isAdmin := participant.IsAdmin || participant.IsSuperAdmin
+		if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+			ownAdmin = isAdmin
+		}
+		for _, jid := range []types.JID{participant.JID, participant.PhoneNumber, participant.LID} {
+			if jid.User != "" && (jid.User == sender.User || jid.User == senderAlt.User) {
+				senderAdmin = isAdmin
+			}
+		}
+	}
+	return ownAdmin, senderAdmin
+}
+
+// ModerateGroupMessage memeriksa pesan grup terhadap aturan moderasi akun (dbPath = database akun sumber event)
+// dan menjalankan tindakan. Return tindakan yang dicatat, nil jika pesan aman / grup tidak dimoderasi
+func ModerateGroupMessage(client *whatsmeow.Client, dbPath string, evt *events.Message) (*utils.ModerationAction, error) {
+	if client == nil || client.Store.ID == nil || !evt.Info.IsGroup || evt.Info.IsFromMe {
+		return nil, nil
+	}
+	accountID := client.Store.ID.User
+	groupJID := evt.Info.Chat
+
+	rule, err := utils.FindModerationRuleAt(dbPath, accountID, groupJID.String())
+	if err != nil || rule == nil {
+		return nil, err
+	}
+
+	violation, detail := detectModerationViolation(rule, accountID, evt)
+	if violation == "" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	ownAdmin, senderAdmin := moderationAdminStatus(client, info, evt.Info.Sender, evt.Info.SenderAlt)
+	if !ownAdmin || senderAdmin {
+		return nil, nil // Tidak bisa menindak tanpa hak admin, dan admin grup tidak dimoderasi
+	}
+
+	sender := evt.Info.Sender
+	warnings, err := utils.AddModerationWarningAt(dbPath, accountID, groupJID.String(), sender.String())
+	if err != nil {
+		return nil, err
+	}
+
+	action := &utils.ModerationAction{
+		AccountID: accountID,
+		GroupJID:  groupJID.String(),
+		GroupName: info.Name,
+		SenderJID: sender.String(),
+		MessageID: evt.Info.ID,
+		Violation: violation,
+		Detail:    detail,
+		Action:    utils.ModerationActionWarn,
+		Warnings:  warnings,
+	}
+
+	if rule.Action == utils.ModerationActionDelete {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		_, err := client.SendMessage(ctx, groupJID, client.BuildRevoke(groupJID, sender, evt.Info.ID))
+		cancel()
+		if err == nil {
+			action.Action = utils.ModerationActionDelete
+		} else {
+			utils.GetGrupLogger().Debug("Moderasi: gagal menghapus pesan %s di %s: %v", evt.Info.ID, groupJID, err)
+		}
+	}
+
+	if rule.MaxWarnings > 0 && warnings >= rule.MaxWarnings {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		_, err := client.UpdateGroupParticipants(ctx, groupJID, []types.JID{sender}, whatsmeow.ParticipantChangeRemove)
+		cancel()
+		if err == nil {
+			action.Action = utils.ModerationActionRemove
+			utils.ResetModerationWarningsAt(dbPath, accountID, groupJID.String(), sender.String())
+		} else {
+			utils.GetGrupLogger().Debug("Moderasi: gagal mengeluarkan %s dari %s: %v", sender, groupJID, err)
+		}
+	}
+
+	if action.Action != utils.ModerationActionRemove {
+		sendModerationWarning(client, groupJID, sender, rule, action)
+	}
+
+	action.ID, err = utils.SaveModerationActionAt(dbPath, action)
+	return action, err
+}
+
+// sendModerationWarning mengirim peringatan ke grup dengan mention pelanggar
+func sendModerationWarning(client *whatsmeow.Client, groupJID, sender types.JID, rule *utils.ModerationRule, action *utils.ModerationAction) {
+	text := fmt.Sprintf("⚠️ @%s, pesan Anda melanggar aturan grup: %s.", sender.User, moderationViolationLabel(action.Violation))
+	if action.Action == utils.ModerationActionDelete {
+		text += " Pesan telah dihapus."
+	}
+	if rule.MaxWarnings > 0 {
+		text += fmt.Sprintf("\nPeringatan %d/%d. Anda akan dikeluarkan jika mencapai batas.", action.Warnings, rule.MaxWarnings)
+	} else {
+		text += fmt.Sprintf("\nPeringatan ke-%d.", action.Warnings)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	_, err := client.SendMessage(ctx, groupJID, &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text: proto.String(text),
+			ContextInfo: &waProto.ContextInfo{
+				MentionedJID: []string{sender.String()},
+			},
+		},
+	})
+	if err != nil {
+		utils.GetGrupLogger().Debug("Moderasi: gagal mengirim peringatan ke %s: %v", groupJID, err)
+	}
+}
+
+// moderationSenderLabel memformat pelanggar untuk laporan (client bisa nil jika akun sedang terputus)
+func moderationSenderLabel(client *whatsmeow.Client, jid types.JID) string {
+	if client == nil || client.Store == nil {
+		return jid.User
+	}
+	return requesterLabel(client, jid)
+}
+
+// ReportModerationAction mencatat tindakan moderasi ke log aktivitas akun dan mengirimnya ke pemilik akun
+// dengan tombol banding / batalkan (accountID = ID akun di account manager, dbPath = database akun tersebut)
+func ReportModerationAction(accountID int, dbPath string, client *whatsmeow.Client, action *utils.ModerationAction) {
+	ownerChatID := GetAccountTelegramID(GetAccountManager().GetAccount(accountID))
+	senderJID, _ := types.ParseJID(action.SenderJID)
+	senderLabel := moderationSenderLabel(client, senderJID)
+
+	description := fmt.Sprintf("%s: %s di %s (%s)", moderationActionLabel(action.Action), senderLabel, action.GroupName, moderationViolationLabel(action.Violation))
+	utils.LogActivityWithMetadataAt(dbPath, "moderation_"+action.Action, description, ownerChatID, map[string]interface{}{
+		"action_id": action.ID,
+		"group_jid": action.GroupJID,
+		"sender":    action.SenderJID,
+		"violation": action.Violation,
+		"detail":    action.Detail,
+		"warnings":  action.Warnings,
+	}, true)
+
+	var text strings.Builder
+	text.WriteString("🛡️ MODERASI OTOMATIS\n\n")
+	text.WriteString(fmt.Sprintf("👥 Grup: %s\n", action.GroupName))
+	text.WriteString(fmt.Sprintf("👤 Pengirim: %s\n", senderLabel))
+	text.WriteString(fmt.Sprintf("🚩 Pelanggaran: %s\n", moderationViolationLabel(action.Violation)))
+	if action.Detail != "" {
+		text.WriteString(fmt.Sprintf("📝 Detail: %s\n", action.Detail))
+	}
+	text.WriteString(fmt.Sprintf("⚡ Tindakan: %s\n", moderationActionLabel(action.Action)))
+	text.WriteString(fmt.Sprintf("🔢 Peringatan: %d\n", action.Warnings))
+
+	undoLabel := "↩️ Batalkan Peringatan (Banding)"
+	if action.Action == utils.ModerationActionRemove {
+		undoLabel = "↩️ Masukkan Kembali (Banding)"
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(undoLabel, fmt.Sprintf("mod_undo_%d_%d", accountID, action.ID)),
+		),
+	)
+	SendToAccountOwnerWithKeyboard(accountID, text.String(), keyboard)
+}
+
+// moderationAccount mendapatkan client dan database akun pemilik tindakan moderasi
+// accountID negatif = mode satu akun (client dan database aktif)
+func moderationAccount(accountID int, chatID int64) (*whatsmeow.Client, string, error) {
+	if accountID < 0 {
+		return GetWhatsAppClient(), "", nil
+	}
+	account := GetAccountManager().GetAccount(accountID)
+	if account == nil || account.BotDataDBPath == "" {
+		return nil, "", fmt.Errorf("akun tidak ditemukan")
+	}
+	// SECURITY: Hanya pemilik akun yang boleh membatalkan tindakan moderasi (pemilik tidak diketahui = ditolak)
+	if owner := GetAccountTelegramID(account); owner == 0 || owner != chatID {
+		return nil, "", fmt.Errorf("akses ditolak")
+	}
+	return GetAccountManager().GetClient(accountID), account.BotDataDBPath, nil
+}
+
+// UndoModerationAction membatalkan tindakan moderasi (banding diterima):
+// peringatan dikurangi, peserta yang dikeluarkan dimasukkan kembali. Pesan yang sudah dihapus tidak bisa dikembalikan
+func UndoModerationAction(accountID int, actionID int64, chatID int64, messageID int, telegramBot *tgbotapi.BotAPI) {
+	client, dbPath, err := moderationAccount(accountID, chatID)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Gagal membatalkan: "+err.Error()))
+		return
+	}
+
+	action, err := utils.GetModerationActionAt(dbPath, actionID)
+	if err != nil || action == nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Tindakan moderasi tidak ditemukan."))
+		return
+	}
+	if action.Undone {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "ℹ️ Tindakan ini sudah dibatalkan sebelumnya."))
+		return
+	}
+
+	groupJID, _ := types.ParseJID(action.GroupJID)
+	senderJID, _ := types.ParseJID(action.SenderJID)
+
+	result := "✅ Banding diterima: 1 peringatan dicabut."
+	if action.Action == utils.ModerationActionRemove {
+		if client == nil || !client.IsConnected() {
+			telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ WhatsApp tidak terhubung. Coba lagi nanti."))
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		_, err := client.UpdateGroupParticipants(ctx, groupJID, []types.JID{senderJID}, whatsmeow.ParticipantChangeAdd)
+		cancel()
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorPermission, err, "Gagal memasukkan kembali peserta"))
+			msg.ParseMode = "Markdown"
+			telegramBot.Send(msg)
+			return
+		}
+		utils.ResetModerationWarningsAt(dbPath, action.AccountID, action.GroupJID, action.SenderJID)
+		result = "✅ Banding diterima: peserta dimasukkan kembali ke grup dan peringatan di-reset."
+	} else {
+		utils.RemoveModerationWarningAt(dbPath, action.AccountID, action.GroupJID, action.SenderJID)
+		if action.Action == utils.ModerationActionDelete {
+			result += "\nℹ️ Pesan yang sudah dihapus tidak bisa dikembalikan."
+		}
+	}
+
+	utils.MarkModerationActionUndoneAt(dbPath, action.ID)
+	utils.LogActivityWithMetadataAt(dbPath, "moderation_undo",
+		fmt.Sprintf("Undo %s: %s di %s", action.Action, moderationSenderLabel(client, senderJID), action.GroupName), chatID,
+		map[string]interface{}{"action_id": action.ID, "group_jid": action.GroupJID, "sender": action.SenderJID}, true)
+
+	// Hapus tombol banding agar tidak diklik dua kali
+	telegramBot.Send(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}}))
+	telegramBot.Send(tgbotapi.NewMessage(chatID, result))
+}
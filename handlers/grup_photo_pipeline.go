@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// PhotoCropMode menentukan cara foto dibuat persegi
+type PhotoCropMode string
+
+const (
+	PhotoCropCenter PhotoCropMode = "center" // Potong bagian tengah
+	PhotoCropTop    PhotoCropMode = "top"    // Potong dengan bagian atas dipertahankan (cocok untuk foto orang/poster)
+	PhotoCropFit    PhotoCropMode = "fit"    // Foto utuh, sisa ruang diisi warna padding
+)
+
+const (
+	// Ukuran foto profil grup WhatsApp
+	groupPhotoSize = 640
+	// Target ukuran file JPEG; kualitas diturunkan bertahap sampai di bawah batas ini
+	groupPhotoMaxBytes   = 150 * 1024
+	groupPhotoMaxQuality = 92
+	groupPhotoMinQuality = 60
+)
+
+// PhotoOptions adalah pengaturan pipeline pemrosesan foto grup
+type PhotoOptions struct {
+	CropMode PhotoCropMode
+	PadColor *color.RGBA // Warna padding mode fit (nil = otomatis dari warna rata-rata foto)
+}
+
+// DefaultPhotoOptions adalah pengaturan bawaan (crop tengah)
+var DefaultPhotoOptions = PhotoOptions{CropMode: PhotoCropCenter}
+
+// Warna padding yang bisa dipilih user untuk mode fit
+var (
+	photoPadWhite = color.RGBA{255, 255, 255, 255}
+	photoPadBlack = color.RGBA{0, 0, 0, 255}
+)
+
+// Palet warna latar avatar teks (warna gelap agar teks putih tetap terbaca)
+var avatarPalette = []color.RGBA{
+	{0x1a, 0x73, 0xe8, 0xff},
+	{0x0b, 0x80, 0x43, 0xff},
+	{0xd9, 0x30, 0x25, 0xff},
+	{0x8e, 0x24, 0xaa, 0xff},
+	{0xe3, 0x74, 0x00, 0xff},
+	{0x00, 0x79, 0x6b, 0xff},
+	{0x5d, 0x40, 0x37, 0xff},
+	{0x30, 0x3f, 0x9f, 0xff},
+	{0xc2, 0x18, 0x5b, 0xff},
+	{0x37, 0x47, 0x4f, 0xff},
+}
+
+// Font avatar di-parse sekali lalu dipakai ulang
+var (
+	avatarFontOnce sync.Once
+	avatarFont     *opentype.Font
+	avatarFontErr  error
+)
+
+// Label mengembalikan nama mode crop untuk ditampilkan ke user
+func (opts PhotoOptions) Label() string {
+	switch opts.CropMode {
+	case PhotoCropTop:
+		return "Crop Atas"
+	case PhotoCropFit:
+		switch {
+		case opts.PadColor == nil:
+			return "Fit (padding otomatis)"
+		case *opts.PadColor == photoPadWhite:
+			return "Fit (padding putih)"
+		case *opts.PadColor == photoPadBlack:
+			return "Fit (padding hitam)"
+		}
+		return "Fit"
+	}
+	return "Crop Tengah"
+}
+
+// renderSquarePhoto membuat foto persegi size x size sesuai mode crop
+// Scaling memakai CatmullRom (kualitas tinggi) untuk foto besar dan ApproxBiLinear untuk foto kecil yang diperbesar
+func renderSquarePhoto(img image.Image, size int, opts PhotoOptions) image.Image {
+	bounds := img.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	canvas := image.NewRGBA(image.Rect(0, 0, size, size))
+
+	if width == 0 || height == 0 {
+		return canvas
+	}
+
+	scaler := xdraw.Interpolator(xdraw.CatmullRom)
+	if width < size && height < size {
+		scaler = xdraw.ApproxBiLinear
+	}
+
+	if opts.CropMode == PhotoCropFit {
+		// Foto utuh di tengah kanvas, sisa ruang diisi warna padding
+		padColor := averageColor(img)
+		if opts.PadColor != nil {
+			padColor = *opts.PadColor
+		}
+		draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: padColor}, image.Point{}, draw.Src)
+
+		var target image.Rectangle
+		if width > height {
+			newHeight := height * size / width
+			offset := (size - newHeight) / 2
+			target = image.Rect(0, offset, size, offset+newHeight)
+		} else {
+			newWidth := width * size / height
+			offset := (size - newWidth) / 2
+			target = image.Rect(offset, 0, offset+newWidth, size)
+		}
+		scaler.Scale(canvas, target, img, bounds, draw.Over, nil)
+		return canvas
+	}
+
+	// Crop: ambil area persegi dari foto asli lalu scale ke ukuran target
+	side := width
+	if height < side {
+		side = height
+	}
+	cropX := bounds.Min.X + (width-side)/2
+	cropY := bounds.Min.Y + (height-side)/2
+	if opts.CropMode == PhotoCropTop {
+		cropY = bounds.Min.Y
+	}
+	source := image.Rect(cropX, cropY, cropX+side, cropY+side)
+	scaler.Scale(canvas, canvas.Bounds(), img, source, draw.Src, nil)
+	return canvas
+}
+
+// averageColor menghitung warna rata-rata foto (sampling) untuk padding otomatis
+func averageColor(img image.Image) color.RGBA {
+	bounds := img.Bounds()
+	step := bounds.Dx() / 64
+	if dy := bounds.Dy() / 64; dy > step {
+		step = dy
+	}
+	if step < 1 {
+		step = 1
+	}
+
+	var r, g, b, count uint64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			r += uint64(cr >> 8)
+			g += uint64(cg >> 8)
+			b += uint64(cb >> 8)
+			count++
+		}
+	}
+	if count == 0 {
+		return photoPadWhite
+	}
+	return color.RGBA{uint8(r / count), uint8(g / count), uint8(b / count), 255}
+}
+
+// encodeGroupPhotoJPEG meng-encode foto ke JPEG, menurunkan kualitas bertahap sampai ukuran
+// di bawah groupPhotoMaxBytes (batas aman WhatsApp)
+func encodeGroupPhotoJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	for quality := groupPhotoMaxQuality; quality >= groupPhotoMinQuality; quality -= 8 {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("gagal convert foto: %v", err)
+		}
+		if buf.Len() <= groupPhotoMaxBytes {
+			break
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// preparePhotoWithOptions men-decode foto (JPG, PNG, GIF, WEBP), membuatnya persegi 640x640 sesuai mode crop
+// lalu encode ulang ke JPEG. Return data JPEG dan format asli foto
+func preparePhotoWithOptions(imgData []byte, opts PhotoOptions) ([]byte, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(imgData))
+	if err != nil {
+		return nil, format, err
+	}
+
+	photoBytes, err := encodeGroupPhotoJPEG(renderSquarePhoto(img, groupPhotoSize, opts))
+	if err != nil {
+		return nil, format, err
+	}
+	return photoBytes, format, nil
+}
+
+// avatarInitials mengambil inisial nama grup (maksimal 2 huruf), contoh "Grup Alumni 2020" -> "GA"
+func avatarInitials(name string) string {
+	var initials []rune
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials = append(initials, unicode.ToUpper(r))
+				break
+			}
+		}
+		if len(initials) == 2 {
+			break
+		}
+	}
+	if len(initials) == 0 {
+		return "#"
+	}
+	return string(initials)
+}
+
+// avatarColor memilih warna latar avatar secara konsisten berdasarkan nama grup
+func avatarColor(name string) color.RGBA {
+	hash := fnv.New32a()
+	hash.Write([]byte(strings.ToLower(name)))
+	return avatarPalette[hash.Sum32()%uint32(len(avatarPalette))]
+}
+
+// GenerateTextAvatar membuat foto avatar berisi inisial (useInitials) atau nama grup di atas warna latar
+// Avatar dirender lokal dan langsung di-encode ke JPEG siap upload
+func GenerateTextAvatar(name string, useInitials bool) ([]byte, error) {
+	avatarFontOnce.Do(func() {
+		avatarFont, avatarFontErr = opentype.Parse(gobold.TTF)
+	})
+	if avatarFontErr != nil {
+		return nil, fmt.Errorf("gagal memuat font avatar: %v", avatarFontErr)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, groupPhotoSize, groupPhotoSize))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: avatarColor(name)}, image.Point{}, draw.Src)
+
+	var lines []string
+	var fontSize float64
+	if useInitials {
+		lines = []string{avatarInitials(name)}
+		fontSize = 260
+	} else {
+		lines, fontSize = wrapAvatarName(name)
+	}
+
+	face, err := opentype.NewFace(avatarFont, &opentype.FaceOptions{Size: fontSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuat font avatar: %v", err)
+	}
+	defer face.Close()
+
+	drawer := &font.Drawer{Dst: canvas, Src: image.White, Face: face}
+	metrics := face.Metrics()
+	lineHeight := metrics.Height.Ceil()
+	blockHeight := lineHeight * len(lines)
+	top := (groupPhotoSize-blockHeight)/2 + metrics.Ascent.Ceil()
+
+	for i, line := range lines {
+		lineWidth := drawer.MeasureString(line).Ceil()
+		drawer.Dot = fixed.P((groupPhotoSize-lineWidth)/2, top+i*lineHeight)
+		drawer.DrawString(line)
+	}
+
+	return encodeGroupPhotoJPEG(canvas)
+}
+
+// wrapAvatarName memecah nama grup menjadi maksimal 4 baris dan memilih ukuran font yang muat
+func wrapAvatarName(name string) ([]string, float64) {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return []string{"#"}, 200
+	}
+
+	const maxLines = 4
+	const maxLineChars = 12
+
+	var lines []string
+	current := ""
+	for _, word := range words {
+		if current != "" && utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) > maxLineChars {
+			lines = append(lines, current)
+			current = word
+		} else if current == "" {
+			current = word
+		} else {
+			current += " " + word
+		}
+	}
+	lines = append(lines, current)
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] += "…"
+	}
+
+	// Ukuran font menyesuaikan baris terpanjang agar tetap muat dalam 85% lebar
+	longest := 1
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n > longest {
+			longest = n
+		}
+	}
+	fontSize := float64(groupPhotoSize) * 0.85 / (float64(longest) * 0.62)
+	if maxHeight := float64(groupPhotoSize) * 0.8 / (float64(len(lines)) * 1.2); fontSize > maxHeight {
+		fontSize = maxHeight
+	}
+	if fontSize > 200 {
+		fontSize = 200
+	}
+	return lines, fontSize
+}
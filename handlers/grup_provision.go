@@ -0,0 +1,883 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// ProvisionState menyimpan state provisioning grup dari spesifikasi CSV/JSON (satu baris = satu grup baru)
+type ProvisionState struct {
+	WaitingForFile  bool
+	WaitingForDelay bool
+	Rows            []ProvisionRow
+	DelaySeconds    int
+}
+
+// ProvisionRow adalah spesifikasi satu grup yang akan dibuat beserta setup setelah dibuat
+// Field pengaturan nil = tidak diatur (default WhatsApp)
+type ProvisionRow struct {
+	Line         int
+	Name         string
+	Description  string
+	Photo        string // "" = tanpa foto, "avatar"/"inisial" = avatar teks, URL http(s), atau nama file di ZIP
+	PhotoPath    string // Foto dari ZIP yang sudah di-resize (file sementara)
+	Announce     *bool
+	Locked       *bool
+	JoinApproval *bool
+	MemberAdd    *bool  // true = semua anggota boleh menambah anggota
+	Ephemeral    *int64 // detik: 0 = OFF, 86400 = 24h, 604800 = 7d, 7776000 = 90d
+	Participants []string
+	Admins       []string // Selalu juga termasuk di Participants
+}
+
+// provisionResult adalah hasil pembuatan satu baris spesifikasi (untuk CSV hasil)
+type provisionResult struct {
+	Row    ProvisionRow
+	JID    string
+	Link   string
+	Status string // "ok", "sebagian", "gagal"
+	Notes  []string
+}
+
+var provisionStates = make(map[int64]*ProvisionState)
+
+// Kolom spesifikasi yang dikenali (nama kolom / key JSON tidak case-sensitive)
+var provisionColumnAliases = map[string]string{
+	"name":          "name",
+	"nama":          "name",
+	"nama_grup":     "name",
+	"description":   "description",
+	"deskripsi":     "description",
+	"photo":         "photo",
+	"foto":          "photo",
+	"announce":      "announce",
+	"locked":        "locked",
+	"join_approval": "join_approval",
+	"member_add":    "member_add",
+	"ephemeral":     "ephemeral",
+	"participants":  "participants",
+	"peserta":       "participants",
+	"anggota":       "participants",
+	"admins":        "admins",
+	"admin":         "admins",
+}
+
+const (
+	// Batas baris per file agar job pembuatan grup tidak terlalu panjang
+	provisionMaxRows = 100
+	// Batas ukuran foto yang diunduh dari URL
+	provisionMaxPhotoSize = 5 * 1024 * 1024
+	// Batas waktu unduh foto dari URL agar satu URL yang menggantung tidak menahan job
+	provisionPhotoDownloadTimeout = 20 * time.Second
+)
+
+// ShowProvisionMenuEdit menampilkan penjelasan format spesifikasi provisioning dengan EDIT message
+func ShowProvisionMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	menuMsg := "📦 **PROVISIONING GRUP (CSV/JSON)**\n\n" +
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+		"Setiap baris = satu grup baru. Bot membuat grup, mengatur deskripsi/foto/pengaturan, mengangkat admin, mengambil link, lalu mengirim CSV hasil.\n\n" +
+		"**📋 Kolom:**\n" +
+		"• `name` - nama grup (wajib)\n" +
+		"• `description` - deskripsi\n" +
+		"• `photo` - URL gambar, `avatar` / `inisial`, atau nama file di ZIP\n" +
+		"• `announce`, `locked`, `join_approval`, `member_add` - on/off\n" +
+		"• `ephemeral` - off / 24h / 7d / 90d\n" +
+		"• `participants` - nomor anggota (pisah spasi atau |)\n" +
+		"• `admins` - nomor yang diangkat admin (otomatis ikut ditambahkan)\n\n" +
+		"**📝 Contoh CSV:**\n" +
+		"```\n" +
+		"name,description,photo,announce,participants,admins\n" +
+		"Grup A,Info Grup A,avatar,on,628111|628222,628111\n" +
+		"```\n\n" +
+		"**📝 Contoh JSON:**\n" +
+		"```\n" +
+		"[{\"name\":\"Grup B\",\"join_approval\":true,\"participants\":[\"628333\"],\"admins\":[\"628333\"]}]\n" +
+		"```\n\n" +
+		"━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+		"💡 Untuk foto dari file, kirim ZIP berisi file spesifikasi (.csv/.json) dan gambar yang disebut di kolom photo.\n\n" +
+		fmt.Sprintf("⚠️ Maksimal %d grup per file. File divalidasi dulu sebelum grup dibuat.", provisionMaxRows)
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📤 Kirim File Spesifikasi", "start_provision"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "create_group_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, menuMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartProvisionProcess memulai provisioning (menunggu file spesifikasi)
+func StartProvisionProcess(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	cleanupProvisionState(provisionStates[chatID])
+	provisionStates[chatID] = &ProvisionState{WaitingForFile: true}
+
+	msg := tgbotapi.NewMessage(chatID, "📤 **KIRIM FILE SPESIFIKASI**\n\nKirim file `.csv`, `.json`, atau `.zip` (spesifikasi + gambar).\n\n💡 Lihat format kolom di menu sebelumnya.")
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_provision"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// IsProvisionUpload mengecek apakah file yang dikirim adalah spesifikasi provisioning
+func IsProvisionUpload(chatID int64, fileName string) bool {
+	state := provisionStates[chatID]
+	if state == nil || !state.WaitingForFile {
+		return false
+	}
+	switch strings.ToLower(path.Ext(fileName)) {
+	case ".csv", ".json", ".zip":
+		return true
+	}
+	return false
+}
+
+// HandleProvisionFile mengunduh dan memvalidasi file spesifikasi lalu menampilkan laporan validasi
+func HandleProvisionFile(fileID, fileName string, chatID int64, telegramBot *tgbotapi.BotAPI, botToken string) {
+	state := provisionStates[chatID]
+	if state == nil {
+		return
+	}
+
+	content, err := downloadZipPhotoFile(fileID, botToken)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengunduh file spesifikasi")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	var archive *zip.Reader
+	specName := fileName
+	if strings.EqualFold(path.Ext(fileName), ".zip") {
+		archive, err = zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err == nil {
+			content, specName, err = readProvisionSpecFromZip(archive)
+		}
+		if err != nil {
+			telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ ZIP TIDAK VALID\n\n%v\n\nPerbaiki file lalu kirim ulang.", err)))
+			return
+		}
+	}
+
+	var records []map[string]string
+	if strings.EqualFold(path.Ext(specName), ".json") {
+		records, err = parseProvisionJSON(content)
+	} else {
+		records, err = parseProvisionCSV(content)
+	}
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ FILE SPESIFIKASI TIDAK VALID\n\n%v\n\nPerbaiki file lalu kirim ulang.", err)))
+		return
+	}
+
+	rows, validationErrors := ValidateProvisionRecords(records, archive)
+	cleanupProvisionState(state)
+	state.Rows = rows
+	sendProvisionReport(chatID, state, validationErrors, telegramBot)
+}
+
+// readProvisionSpecFromZip mencari tepat satu file spesifikasi (.csv/.json) di dalam ZIP
+func readProvisionSpecFromZip(archive *zip.Reader) ([]byte, string, error) {
+	var spec *zip.File
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || strings.HasPrefix(path.Base(entry.Name), ".") {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(entry.Name))
+		if ext != ".csv" && ext != ".json" {
+			continue
+		}
+		if spec != nil {
+			return nil, "", fmt.Errorf("ZIP berisi lebih dari satu file spesifikasi (%s, %s)", spec.Name, entry.Name)
+		}
+		spec = entry
+	}
+	if spec == nil {
+		return nil, "", fmt.Errorf("ZIP tidak berisi file spesifikasi .csv atau .json")
+	}
+
+	rc, err := spec.Open()
+	if err != nil {
+		return nil, "", err
+	}
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	return content, spec.Name, err
+}
+
+// parseProvisionCSV membaca CSV spesifikasi menjadi daftar baris (key = nama kolom kanonik, "_line" = nomor baris)
+func parseProvisionCSV(content []byte) ([]map[string]string, error) {
+	columns, rows, err := readCSVTable(content, provisionColumnAliases)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("kolom name wajib ada")
+	}
+
+	var records []map[string]string
+	for _, row := range rows {
+		record := map[string]string{"_line": strconv.Itoa(row.Line)}
+		for column, index := range columns {
+			if index < len(row.Cells) {
+				record[column] = strings.TrimSpace(row.Cells[index])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseProvisionJSON membaca JSON spesifikasi: array objek atau {"groups": [...]}
+// Nilai bool/angka/array diubah ke bentuk teks yang sama dengan sel CSV
+func parseProvisionJSON(content []byte) ([]map[string]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(content, &items); err != nil {
+		var wrapped struct {
+			Groups []map[string]interface{} `json:"groups"`
+		}
+		if errWrapped := json.Unmarshal(content, &wrapped); errWrapped != nil || wrapped.Groups == nil {
+			return nil, fmt.Errorf("format JSON salah: %v", err)
+		}
+		items = wrapped.Groups
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("JSON tidak berisi grup")
+	}
+
+	var records []map[string]string
+	for i, item := range items {
+		record := map[string]string{"_line": strconv.Itoa(i + 1)}
+		for key, value := range item {
+			column, ok := provisionColumnAliases[strings.ToLower(strings.TrimSpace(key))]
+			if !ok {
+				return nil, fmt.Errorf("grup #%d: key \"%s\" tidak dikenal", i+1, key)
+			}
+			record[column] = provisionJSONValue(value)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// provisionJSONValue mengubah nilai JSON menjadi teks sel
+func provisionJSONValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case bool:
+		if v {
+			return "on"
+		}
+		return "off"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, part := range v {
+			parts = append(parts, provisionJSONValue(part))
+		}
+		return strings.Join(parts, " ")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// parseProvisionPhones membaca daftar nomor (pisah spasi, |, koma, titik koma atau baris baru)
+func parseProvisionPhones(value string) ([]string, []string) {
+	var phones, invalid []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ' ' || r == '|' || r == ',' || r == ';' || r == '\n' || r == '\t'
+	}) {
+		phone := cleanPhoneNumber(field)
+		if phone == "" {
+			invalid = append(invalid, field)
+			continue
+		}
+		if !seen[phone] {
+			seen[phone] = true
+			phones = append(phones, phone)
+		}
+	}
+	return phones, invalid
+}
+
+// ValidateProvisionRecords memvalidasi baris spesifikasi. Foto dari ZIP langsung diproses ke file sementara
+// Return baris valid dan daftar error validasi per baris
+func ValidateProvisionRecords(records []map[string]string, archive *zip.Reader) ([]ProvisionRow, []string) {
+	var rows []ProvisionRow
+	var validationErrors []string
+
+	zipEntries := make(map[string]*zip.File)
+	if archive != nil {
+		for _, entry := range archive.File {
+			if !entry.FileInfo().IsDir() {
+				zipEntries[strings.ToLower(path.Base(entry.Name))] = entry
+			}
+		}
+	}
+
+	if len(records) > provisionMaxRows {
+		validationErrors = append(validationErrors, fmt.Sprintf("File berisi %d grup, hanya %d pertama yang diproses", len(records), provisionMaxRows))
+		records = records[:provisionMaxRows]
+	}
+
+	seenNames := make(map[string]int)
+	for _, record := range records {
+		line, _ := strconv.Atoi(record["_line"])
+		row := ProvisionRow{Line: line}
+		var rowErrors []string
+
+		row.Name = record["name"]
+		switch {
+		case row.Name == "":
+			rowErrors = append(rowErrors, "name kosong")
+		case utf8.RuneCountInString(row.Name) > maxGroupNameLength:
+			rowErrors = append(rowErrors, fmt.Sprintf("name lebih dari %d karakter", maxGroupNameLength))
+		default:
+			key := strings.ToLower(row.Name)
+			if firstLine, duplicate := seenNames[key]; duplicate {
+				rowErrors = append(rowErrors, fmt.Sprintf("nama sama dengan baris %d", firstLine))
+			} else {
+				seenNames[key] = line
+			}
+		}
+
+		row.Description = record["description"]
+		if utf8.RuneCountInString(row.Description) > 1024 {
+			rowErrors = append(rowErrors, "description lebih dari 1024 karakter")
+		}
+
+		for _, column := range []string{"announce", "locked", "join_approval", "member_add"} {
+			value := record[column]
+			if value == "" {
+				continue
+			}
+			parsed, err := parseCSVToggle(value)
+			if err != nil {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s: %v", column, err))
+				continue
+			}
+			switch column {
+			case "announce":
+				row.Announce = &parsed
+			case "locked":
+				row.Locked = &parsed
+			case "join_approval":
+				row.JoinApproval = &parsed
+			case "member_add":
+				row.MemberAdd = &parsed
+			}
+		}
+
+		if value := record["ephemeral"]; value != "" {
+			seconds, valid := csvEphemeralValues[strings.ToLower(value)]
+			if !valid {
+				rowErrors = append(rowErrors, fmt.Sprintf("ephemeral \"%s\" tidak valid (off/24h/7d/90d)", value))
+			} else {
+				row.Ephemeral = &seconds
+			}
+		}
+
+		participants, invalidParticipants := parseProvisionPhones(record["participants"])
+		admins, invalidAdmins := parseProvisionPhones(record["admins"])
+		if invalid := append(invalidParticipants, invalidAdmins...); len(invalid) > 0 {
+			rowErrors = append(rowErrors, fmt.Sprintf("nomor tidak valid: %s", strings.Join(invalid, ", ")))
+		}
+		// Admin otomatis ikut ditambahkan sebagai anggota
+		inParticipants := make(map[string]bool)
+		for _, phone := range participants {
+			inParticipants[phone] = true
+		}
+		for _, phone := range admins {
+			if !inParticipants[phone] {
+				participants = append(participants, phone)
+			}
+		}
+		row.Participants = participants
+		row.Admins = admins
+
+		row.Photo = record["photo"]
+		if row.Photo != "" && len(rowErrors) == 0 {
+			lower := strings.ToLower(row.Photo)
+			switch {
+			case lower == "avatar" || lower == "inisial" || lower == "initials":
+			case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://"):
+			default:
+				entry, ok := zipEntries[strings.ToLower(path.Base(row.Photo))]
+				if !ok {
+					rowErrors = append(rowErrors, fmt.Sprintf("foto \"%s\" tidak ditemukan (gunakan URL, avatar, atau kirim ZIP berisi gambar)", row.Photo))
+					break
+				}
+				photoPath, err := extractZipPhoto(entry, DefaultPhotoOptions)
+				if err != nil {
+					rowErrors = append(rowErrors, fmt.Sprintf("foto \"%s\": %v", row.Photo, err))
+					break
+				}
+				row.PhotoPath = photoPath
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			if row.PhotoPath != "" {
+				os.Remove(row.PhotoPath)
+			}
+			validationErrors = append(validationErrors, fmt.Sprintf("Baris %d: %s", line, strings.Join(rowErrors, "; ")))
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, validationErrors
+}
+
+// sendProvisionReport mengirim laporan validasi dan meminta delay jika ada baris valid
+func sendProvisionReport(chatID int64, state *ProvisionState, validationErrors []string, telegramBot *tgbotapi.BotAPI) {
+	totalParticipants, totalAdmins := 0, 0
+	for _, row := range state.Rows {
+		totalParticipants += len(row.Participants)
+		totalAdmins += len(row.Admins)
+	}
+
+	var report strings.Builder
+	report.WriteString("📋 LAPORAN VALIDASI PROVISIONING\n\n")
+	report.WriteString(fmt.Sprintf("✅ Grup valid: %d\n", len(state.Rows)))
+	report.WriteString(fmt.Sprintf("👥 Total anggota awal: %d (👑 %d admin)\n", totalParticipants, totalAdmins))
+	report.WriteString(fmt.Sprintf("❌ Baris bermasalah: %d\n", len(validationErrors)))
+
+	if len(validationErrors) > 0 {
+		report.WriteString("\n")
+		for i, validationError := range validationErrors {
+			if i >= csvImportMaxReportErrors {
+				report.WriteString(fmt.Sprintf("... dan %d baris bermasalah lainnya\n", len(validationErrors)-csvImportMaxReportErrors))
+				break
+			}
+			report.WriteString("• " + validationError + "\n")
+		}
+	}
+
+	if len(state.Rows) == 0 {
+		delete(provisionStates, chatID)
+		report.WriteString("\n⚠️ Tidak ada grup yang bisa dibuat. Perbaiki file lalu mulai ulang provisioning.")
+		telegramBot.Send(tgbotapi.NewMessage(chatID, report.String()))
+		return
+	}
+
+	state.WaitingForFile = false
+	state.WaitingForDelay = true
+
+	if len(validationErrors) > 0 {
+		report.WriteString("\nBaris bermasalah akan dilewati.\n")
+	}
+	report.WriteString("\n⏱️ Ketik delay antar grup (detik) untuk mulai, contoh: 10")
+
+	msg := tgbotapi.NewMessage(chatID, report.String())
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_provision"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleDelayInputForProvision memproses input delay lalu menjalankan job provisioning
+func HandleDelayInputForProvision(input string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := provisionStates[chatID]
+	if state == nil || !state.WaitingForDelay {
+		return
+	}
+
+	delay, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || delay < 0 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Delay harus berupa angka positif (dalam detik)!")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	client := GetWhatsAppClient()
+	if client == nil || client.Store.ID == nil {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+		telegramBot.Send(errorMsg)
+		cleanupProvisionState(state)
+		delete(provisionStates, chatID)
+		return
+	}
+
+	state.DelaySeconds = delay
+	state.WaitingForDelay = false
+
+	startMsg := fmt.Sprintf("🚀 **MEMULAI PROVISIONING**\n\n📊 **Total Grup:** %d grup\n⏱️ **Delay:** %d detik/grup\n\n⏳ Mohon tunggu, proses sedang berjalan...", len(state.Rows), delay)
+	msg := tgbotapi.NewMessage(chatID, startMsg)
+	msg.ParseMode = "Markdown"
+	telegramBot.Send(msg)
+
+	go ProcessProvisionGroups(state, chatID, client, telegramBot)
+}
+
+// ProcessProvisionGroups membuat setiap grup sesuai spesifikasi lalu menjalankan setup setelah dibuat
+func ProcessProvisionGroups(state *ProvisionState, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	defer delete(provisionStates, chatID)
+	defer cleanupProvisionState(state)
+
+	names := make([]string, len(state.Rows))
+	for i, row := range state.Rows {
+		names[i] = row.Name
+	}
+	totalGroups := len(names)
+
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Provisioning Grup", names)
+	defer job.Finish()
+
+	var results []provisionResult
+	var progressMsgSent *tgbotapi.Message
+
+	for i, row := range state.Rows {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah dibuat tetap dikirim
+		}
+
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessProvisionGroups", i, totalGroups)
+		if shouldStop {
+			disconnectMsg := fmt.Sprintf("⚠️ **PROSES DIHENTIKAN**\n\nClient WhatsApp terputus pada grup %d/%d", i+1, totalGroups)
+			notifMsg := tgbotapi.NewMessage(chatID, disconnectMsg)
+			notifMsg.ParseMode = "Markdown"
+			telegramBot.Send(notifMsg)
+			break
+		}
+
+		results = append(results, provisionGroup(job.Context(), validClient, row))
+
+		if totalGroups > 1 {
+			progressPercent := ((i + 1) * 100) / totalGroups
+			progressMsg := fmt.Sprintf(`⏳ **PROGRESS PROVISIONING**
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+%s **%d%%**
+📊 **Grup:** %d/%d grup
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, generateProgressBar(progressPercent), progressPercent, i+1, totalGroups)
+
+			if progressMsgSent == nil {
+				updateMsg := tgbotapi.NewMessage(chatID, progressMsg)
+				updateMsg.ParseMode = "Markdown"
+				sent, _ := telegramBot.Send(updateMsg)
+				progressMsgSent = &sent
+			} else {
+				editMsg := tgbotapi.NewEditMessageText(chatID, progressMsgSent.MessageID, progressMsg)
+				editMsg.ParseMode = "Markdown"
+				telegramBot.Send(editMsg)
+			}
+		}
+
+		if i < totalGroups-1 && state.DelaySeconds > 0 {
+			job.Sleep(time.Duration(state.DelaySeconds) * time.Second)
+		}
+	}
+
+	if progressMsgSent != nil {
+		telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, progressMsgSent.MessageID))
+	}
+
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	var summary strings.Builder
+	summary.WriteString(job.PausedNote())
+	summary.WriteString("🎉 PROVISIONING SELESAI!\n\n")
+	summary.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	summary.WriteString(fmt.Sprintf("📊 Diproses: %d/%d grup\n", len(results), totalGroups))
+	summary.WriteString(fmt.Sprintf("✅ Lengkap: %d\n", counts["ok"]))
+	summary.WriteString(fmt.Sprintf("⚠️ Dibuat, setup sebagian gagal: %d\n", counts["sebagian"]))
+	summary.WriteString(fmt.Sprintf("❌ Gagal dibuat: %d\n", counts["gagal"]))
+	summary.WriteString("\n📎 Detail JID, link dan catatan per baris ada di file CSV hasil.")
+	telegramBot.Send(tgbotapi.NewMessage(chatID, summary.String()))
+
+	if len(results) > 0 {
+		sendProvisionResultsCSV(chatID, results, telegramBot)
+	}
+
+	utils.LogActivity("provision_groups", fmt.Sprintf("Provision %d groups: %d ok, %d partial, %d failed", len(results), counts["ok"], counts["sebagian"], counts["gagal"]), chatID)
+}
+
+// provisionGroup membuat satu grup lalu menerapkan deskripsi, foto, mode tambah anggota, admin dan mengambil link
+func provisionGroup(ctx context.Context, client *whatsmeow.Client, row ProvisionRow) provisionResult {
+	result := provisionResult{Row: row, Status: "ok"}
+	note := func(format string, args ...interface{}) {
+		result.Notes = append(result.Notes, fmt.Sprintf(format, args...))
+		result.Status = "sebagian"
+	}
+
+	participants := []types.JID{}
+	for _, phone := range row.Participants {
+		if jid, err := parseJIDFromString(phone + "@s.whatsapp.net"); err == nil {
+			participants = append(participants, jid)
+		}
+	}
+
+	// Pengaturan yang didukung saat pembuatan dipasang lewat buildCreateGroupRequest (sama dengan buat grup biasa)
+	// MessageLogging/EditSettings di buat grup bermakna kebalikan announce/locked
+	settings := &GroupCreateState{JoinApproval: row.JoinApproval, Ephemeral: row.Ephemeral}
+	if row.Announce != nil {
+		allowAll := !*row.Announce
+		settings.MessageLogging = &allowAll
+	}
+	if row.Locked != nil {
+		allowAll := !*row.Locked
+		settings.EditSettings = &allowAll
+	}
+
+	createCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	groupInfo, err := client.CreateGroup(createCtx, buildCreateGroupRequest(row.Name, participants, settings))
+	cancel()
+	if err != nil {
+		result.Status = "gagal"
+		result.Notes = append(result.Notes, fmt.Sprintf("buat grup: %v", err))
+		return result
+	}
+
+	jid := groupInfo.JID
+	result.JID = jid.String()
+	if err := utils.SaveGroupToDB(result.JID, row.Name); err != nil {
+		utils.GetGrupLogger().Warn("provisionGroup: Gagal menyimpan grup %s ke database: %v", row.Name, err)
+	}
+
+	// run menjalankan satu langkah setup dengan timeout sendiri
+	run := func(timeout time.Duration, step func(ctx context.Context) error) error {
+		stepCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return step(stepCtx)
+	}
+
+	if row.MemberAdd != nil {
+		addMode := types.GroupMemberAddModeAdmin
+		if *row.MemberAdd {
+			addMode = types.GroupMemberAddModeAllMember
+		}
+		if err := run(10*time.Second, func(ctx context.Context) error {
+			return client.SetGroupMemberAddMode(ctx, jid, addMode)
+		}); err != nil {
+			note("member_add: %v", err)
+		}
+	}
+
+	if row.Description != "" {
+		if err := run(30*time.Second, func(ctx context.Context) error {
+			return client.SetGroupDescription(ctx, jid, row.Description)
+		}); err != nil {
+			note("deskripsi: %v", err)
+		}
+	}
+
+	if row.Photo != "" {
+		photoBytes, err := loadProvisionPhoto(row)
+		if err == nil {
+			err = run(30*time.Second, func(ctx context.Context) error {
+				_, err := client.SetGroupPhoto(ctx, jid, photoBytes)
+				return err
+			})
+		}
+		if err != nil {
+			note("foto: %v", err)
+		}
+	}
+
+	// Peserta yang tidak bisa ditambahkan langsung (privasi) dicatat agar bisa diundang manual
+	added := make(map[string]types.JID)
+	for _, participant := range groupInfo.Participants {
+		// Error != 0 (mis. 403 = hanya dikirimi undangan karena privasi) berarti tidak ikut masuk
+		if participant.Error != 0 {
+			continue
+		}
+		if phone := participantPhone(client, participant); phone != "" {
+			added[phone] = participant.JID
+		}
+	}
+	var notAdded []string
+	for _, phone := range row.Participants {
+		if _, ok := added[phone]; !ok {
+			notAdded = append(notAdded, phone)
+		}
+	}
+	if len(notAdded) > 0 {
+		note("tidak langsung masuk: %s", strings.Join(notAdded, " "))
+	}
+
+	var adminJIDs []types.JID
+	for _, phone := range row.Admins {
+		if participantJID, ok := added[phone]; ok {
+			adminJIDs = append(adminJIDs, participantJID)
+		}
+	}
+	if len(adminJIDs) > 0 {
+		var updated []types.GroupParticipant
+		err := run(30*time.Second, func(ctx context.Context) error {
+			var err error
+			updated, err = client.UpdateGroupParticipants(ctx, jid, adminJIDs, whatsmeow.ParticipantChangePromote)
+			return err
+		})
+		if err != nil {
+			note("admin: %v", err)
+		} else {
+			for _, participant := range updated {
+				if participant.Error != 0 {
+					note("admin %s: error code %d", requesterLabel(client, participant.JID), participant.Error)
+				}
+			}
+		}
+	}
+
+	var link string
+	if err := run(10*time.Second, func(ctx context.Context) error {
+		var err error
+		link, err = client.GetGroupInviteLink(ctx, jid, false)
+		return err
+	}); err != nil {
+		note("link: %v", err)
+	} else {
+		result.Link = link
+		recordInviteLink(client, result.JID, link)
+	}
+
+	return result
+}
+
+// loadProvisionPhoto menyiapkan foto grup dari ZIP, URL, atau avatar teks
+func loadProvisionPhoto(row ProvisionRow) ([]byte, error) {
+	if row.PhotoPath != "" {
+		return os.ReadFile(row.PhotoPath)
+	}
+
+	lower := strings.ToLower(row.Photo)
+	if lower == "avatar" || lower == "inisial" || lower == "initials" {
+		return GenerateTextAvatar(row.Name, lower != "avatar")
+	}
+
+	httpClient := &http.Client{
+		Timeout: provisionPhotoDownloadTimeout,
+	}
+	resp, err := httpClient.Get(row.Photo)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unduh gagal (HTTP %d)", resp.StatusCode)
+	}
+
+	imgData, err := io.ReadAll(io.LimitReader(resp.Body, provisionMaxPhotoSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(imgData) > provisionMaxPhotoSize {
+		return nil, fmt.Errorf("ukuran lebih dari 5MB")
+	}
+
+	photoBytes, _, err := preparePhotoWithOptions(imgData, DefaultPhotoOptions)
+	if err != nil {
+		return nil, fmt.Errorf("gambar tidak valid: %v", err)
+	}
+	return photoBytes, nil
+}
+
+// sendProvisionResultsCSV mengirim CSV hasil yang memetakan baris spesifikasi ke JID dan link grup baru
+func sendProvisionResultsCSV(chatID int64, results []provisionResult, telegramBot *tgbotapi.BotAPI) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{"baris", "nama_grup", "status", "jid", "link", "catatan"})
+	for _, result := range results {
+		writer.Write([]string{
+			strconv.Itoa(result.Row.Line),
+			result.Row.Name,
+			result.Status,
+			result.JID,
+			result.Link,
+			strings.Join(result.Notes, "; "),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		utils.GetGrupLogger().Error("sendProvisionResultsCSV: Gagal membuat CSV: %v", err)
+		return
+	}
+
+	fileName := fmt.Sprintf("provisioning_result_%s.csv", time.Now().Format("20060102_150405"))
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: fileName, Bytes: buf.Bytes()})
+	doc.Caption = fmt.Sprintf("📦 Hasil Provisioning Grup\n\n📊 %d baris\n📅 %s",
+		len(results), time.Now().Format("02 Jan 2006 15:04"))
+	if _, err := telegramBot.Send(doc); err != nil {
+		utils.GetGrupLogger().Error("sendProvisionResultsCSV: Gagal mengirim file: %v", err)
+	}
+}
+
+// cleanupProvisionState menghapus file foto sementara hasil ekstrak ZIP
+func cleanupProvisionState(state *ProvisionState) {
+	if state == nil {
+		return
+	}
+	for _, row := range state.Rows {
+		if row.PhotoPath != "" {
+			os.Remove(row.PhotoPath)
+		}
+	}
+}
+
+// CancelProvision membatalkan provisioning grup
+func CancelProvision(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	cleanupProvisionState(provisionStates[chatID])
+	delete(provisionStates, chatID)
+	msg := tgbotapi.NewMessage(chatID, "❌ Provisioning grup dibatalkan.")
+	telegramBot.Send(msg)
+}
+
+// IsWaitingForProvisionInput mengecek apakah user sedang di proses provisioning
+func IsWaitingForProvisionInput(chatID int64) bool {
+	state := provisionStates[chatID]
+	return state != nil && (state.WaitingForFile || state.WaitingForDelay)
+}
+
+// GetProvisionInputType mengembalikan jenis input yang sedang ditunggu
+func GetProvisionInputType(chatID int64) string {
+	state := provisionStates[chatID]
+	if state == nil {
+		return ""
+	}
+	if state.WaitingForFile {
+		return "file"
+	}
+	if state.WaitingForDelay {
+		return "delay"
+	}
+	return ""
+}
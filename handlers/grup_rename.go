@@ -0,0 +1,1138 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+)
+
+// Batas panjang nama grup WhatsApp
+const maxGroupNameLength = 100
+
+// Mode pola ganti nama grup
+const (
+	RenameModeTemplate = "template" // Template dengan placeholder {name} dan {no}
+	RenameModeRegex    = "regex"    // Cari & ganti dengan regular expression
+)
+
+// GroupRenameState manages the state for bulk renaming groups
+type GroupRenameState struct {
+	WaitingForGroupName bool
+	WaitingForDelay     bool
+	WaitingForMode      bool
+	WaitingForPattern   bool
+	WaitingForConfirm   bool
+	SelectedGroups      []GroupLinkInfo
+	Keyword             string
+	DelaySeconds        int
+	Mode                string
+	Pattern             string
+	Plan                []GroupRenameItem
+}
+
+// GroupRenameItem adalah satu baris rencana ganti nama (nama lama -> nama baru)
+type GroupRenameItem struct {
+	JID     string
+	OldName string
+	NewName string
+	Skip    string // Alasan grup dilewati (kosong jika akan di-rename)
+}
+
+var groupRenameStates = make(map[int64]*GroupRenameState)
+
+// Map to track if selection is for rename
+var renameSelection = make(map[int64]bool)
+
+// Placeholder nomor urut: {no} atau {no:3} (zero padding 3 digit)
+var renameNumberPlaceholder = regexp.MustCompile(`\{no(?::(\d+))?\}`)
+
+// ShowRenameGroupMenuEdit menampilkan menu ganti nama grup dengan EDIT message (no spam!)
+func ShowRenameGroupMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	menuMsg := `✏️ **GANTI NAMA GRUP MASSAL**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Fitur ini akan mengganti nama banyak grup WhatsApp sekaligus menggunakan pola.
+
+**📋 Pilihan Metode:**
+
+🔍 **Cari Manual** - Ketik nama/kata kunci grup (atau kirim file .txt)
+📋 **Lihat & Pilih** - Lihat daftar lalu pilih
+⚡ **Ganti Semua** - Proses semua grup sekaligus
+
+**✏️ Pola Nama:**
+
+📝 **Template** - Contoh: ` + "`{name} - 2026`" + `
+🔢 **Penomoran** - Contoh: ` + "`ANGKATAN {no}`" + ` (urutan natural)
+🔁 **Regex** - Cari & ganti dengan regular expression
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⚠️ **Catatan Penting:**
+• Bot harus menjadi admin grup (atau grup mengizinkan semua anggota edit info)
+• Preview nama lama → baru ditampilkan sebelum diterapkan
+• Maksimal 100 karakter per nama grup
+• Delay membantu menghindari rate limit WhatsApp
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Pilih metode yang Anda inginkan`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📋 Lihat & Pilih", "show_group_list_rename"),
+			tgbotapi.NewInlineKeyboardButtonData("🔍 Cari Manual", "start_rename_group"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚡ Ganti Semua", "rename_all_groups"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📖 Lihat Contoh", "rename_example"),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "grup"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, menuMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// ShowRenameExampleEdit menampilkan contoh pola ganti nama dengan EDIT message
+func ShowRenameExampleEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	exampleMsg := `📖 **CONTOH POLA GANTI NAMA**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**📝 Template**
+Placeholder:
+• ` + "`{name}`" + ` = nama grup saat ini
+• ` + "`{no}`" + ` = nomor urut (1, 2, 3...)
+• ` + "`{no:3}`" + ` = nomor urut 3 digit (001, 002...)
+
+Contoh: ` + "`{name} - 2026`" + `
+KELAS A → KELAS A - 2026
+
+**🔢 Penomoran**
+Contoh: ` + "`ANGKATAN {no:2}`" + `
+Grup diurutkan natural (Grup 2 sebelum Grup 10):
+Grup 1 → ANGKATAN 01
+Grup 2 → ANGKATAN 02
+Grup 10 → ANGKATAN 03
+
+Mulai dari nomor lain? Tambahkan baris kedua:
+` + "`ANGKATAN {no}`" + `
+` + "`mulai=11`" + `
+
+**🔁 Regex**
+Baris 1 = pola, baris 2 = pengganti (boleh kosong untuk menghapus)
+` + "`2025`" + `
+` + "`2026`" + `
+GRUP 2025 A → GRUP 2026 A
+
+Grup tangkap ` + "`$1`" + ` didukung:
+` + "`^(.*) OLD$`" + `
+` + "`$1 NEW`" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Grup yang namanya tidak berubah otomatis dilewati.`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "rename_group_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, exampleMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// StartRenameGroupProcess memulai proses ganti nama grup (cari manual / file .txt)
+func StartRenameGroupProcess(telegramBot *tgbotapi.BotAPI, chatID int64) {
+	delete(renameSelection, chatID)
+	groupRenameStates[chatID] = &GroupRenameState{
+		WaitingForGroupName: true,
+		SelectedGroups:      []GroupLinkInfo{},
+	}
+
+	promptMsg := `🔍 **MASUKKAN NAMA GRUP**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+✅ **Mode Input Aktif**
+
+Ketik nama grup atau kata kunci untuk mencari grup yang ingin diganti namanya.
+
+**Contoh Input:**
+• "Keluarga" - Cari grup dengan kata keluarga
+• "Kerja" - Cari grup dengan kata kerja
+• "." - Ganti nama SEMUA grup (hati-hati!)
+
+**Multi-line Input (Exact Match):**
+GROUP ANGKATAN 1
+GROUP ANGKATAN 2
+GROUP ANGKATAN 3
+
+📄 Atau kirim file **.txt** berisi nama grup (satu per baris)
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏳ Menunggu input...`
+
+	msg := tgbotapi.NewMessage(chatID, promptMsg)
+	msg.ParseMode = "Markdown"
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_rename_group"),
+		),
+	)
+	msg.ReplyMarkup = keyboard
+
+	telegramBot.Send(msg)
+}
+
+// HandleGroupNameInputForRename memproses input nama grup / kata kunci
+func HandleGroupNameInputForRename(keyword string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForGroupName {
+		return
+	}
+
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Nama grup tidak boleh kosong!")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	state.Keyword = keyword
+
+	loadingMsg := tgbotapi.NewMessage(chatID, "🔍 Mencari grup...")
+	loadingMsgSent, _ := telegramBot.Send(loadingMsg)
+
+	var groups map[string]string
+	var err error
+
+	// Smart search logic (same as description & photo features)
+	if keyword == "." {
+		groups, err = utils.GetAllGroupsFromDB()
+	} else {
+		lines := strings.Split(keyword, "\n")
+		if len(lines) > 1 {
+			// Multi-line: exact match
+			groups, err = utils.SearchGroupsExactMultiple(lines)
+		} else if len(keyword) > 30 {
+			// Long single-line: try exact first
+			groups, err = utils.SearchGroupsExact(keyword)
+			if err == nil && len(groups) == 0 {
+				groups, err = utils.SearchGroupsFlexible(keyword)
+			}
+		} else {
+			// Short keyword: flexible search
+			groups, err = utils.SearchGroupsFlexible(keyword)
+		}
+	}
+
+	// Delete loading message
+	deleteMsg := tgbotapi.NewDeleteMessage(chatID, loadingMsgSent.MessageID)
+	telegramBot.Request(deleteMsg)
+
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+		telegramBot.Send(errorMsg)
+		state.WaitingForGroupName = false
+		return
+	}
+
+	if len(groups) == 0 {
+		noResultMsg := fmt.Sprintf(`❌ **TIDAK DITEMUKAN**
+
+Tidak ada grup yang cocok dengan kata kunci: **"%s"**
+
+**Saran:**
+• Coba kata kunci yang berbeda
+• Periksa ejaan kata kunci
+• Gunakan kata kunci yang lebih umum`, keyword)
+
+		msg := tgbotapi.NewMessage(chatID, noResultMsg)
+		msg.ParseMode = "Markdown"
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🔍 Cari Lagi", "start_rename_group"),
+				tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "rename_group_menu"),
+			),
+		)
+		telegramBot.Send(msg)
+
+		state.WaitingForGroupName = false
+		return
+	}
+
+	setRenameSelectedGroups(state, groups)
+	askDelayForRename(state, chatID, telegramBot, fmt.Sprintf("✅ **GRUP DITEMUKAN**\n\n📊 **Total:** %d grup", len(state.SelectedGroups)))
+}
+
+// HandleFileInputForRename - Handle file .txt untuk ganti nama grup
+func HandleFileInputForRename(fileID string, chatID int64, telegramBot *tgbotapi.BotAPI, botToken string) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForGroupName {
+		return
+	}
+
+	// Download file
+	fileURL := fmt.Sprintf("https://api.telegram.org/bot%s/getFile?file_id=%s", botToken, fileID)
+	resp, err := http.Get(fileURL)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorConnection, err, "Gagal mengunduh file")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		utils.LogActivityError("rename_group", "Gagal download file", chatID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	var fileResp struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			FilePath string `json:"file_path"`
+		} `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&fileResp); err != nil || !fileResp.OK {
+		errorMsg := utils.FormatUserError(utils.ErrorValidation, fmt.Errorf("file tidak valid"), "Gagal mengambil informasi file")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		utils.LogActivityError("rename_group", "File tidak valid", chatID, err)
+		return
+	}
+
+	// Download file content
+	downloadURL := fmt.Sprintf("https://api.telegram.org/file/bot%s/%s", botToken, fileResp.Result.FilePath)
+	fileResp2, err := http.Get(downloadURL)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorConnection, err, "Gagal membaca file")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		utils.LogActivityError("rename_group", "Gagal membaca file", chatID, err)
+		return
+	}
+	defer fileResp2.Body.Close()
+
+	// Read file content - extract group names (one per line)
+	var groupNames []string
+	scanner := bufio.NewScanner(fileResp2.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			groupNames = append(groupNames, line)
+		}
+	}
+
+	if len(groupNames) == 0 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ **FILE KOSONG**\n\nFile `.txt` yang Anda kirim tidak berisi nama grup.\n\n**Format yang benar:**\nSatu nama grup per baris.")
+		errorMsg.ParseMode = "Markdown"
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	utils.LogActivity("rename_group_file", fmt.Sprintf("File .txt diterima dengan %d nama grup", len(groupNames)), chatID)
+
+	// Search groups using exact match for each name
+	groups, err := utils.SearchGroupsExactMultiple(groupNames)
+	if err != nil {
+		errorMsg := utils.FormatUserError(utils.ErrorDatabase, err, "Gagal mencari grup")
+		msg := tgbotapi.NewMessage(chatID, errorMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		utils.LogActivityError("rename_group", "Gagal mencari grup dari file", chatID, err)
+		return
+	}
+
+	if len(groups) == 0 {
+		notFoundMsg := fmt.Sprintf("❌ **GRUP TIDAK DITEMUKAN**\n\nTidak ada grup yang cocok dengan nama-nama di file.\n\n📋 **Nama yang dicari:** %d grup", len(groupNames))
+		msg := tgbotapi.NewMessage(chatID, notFoundMsg)
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+
+	setRenameSelectedGroups(state, groups)
+	askDelayForRename(state, chatID, telegramBot, fmt.Sprintf("✅ **GRUP DITEMUKAN**\n\n📊 **Total:** %d grup (dari %d yang dicari)", len(state.SelectedGroups), len(groupNames)))
+}
+
+// ShowGroupListForRenameEdit menampilkan daftar grup dengan pagination untuk ganti nama (EDIT, NO SPAM!)
+func ShowGroupListForRenameEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int, page int) {
+	// CRITICAL FIX: Pastikan menggunakan database user yang benar
+	am := GetAccountManager()
+	userAccount := am.GetAccountByTelegramID(chatID)
+	if userAccount != nil {
+		EnsureDBConfigForUser(chatID, userAccount)
+	}
+
+	groupsMap, err := utils.GetAllGroupsFromDB()
+	if err != nil {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, fmt.Sprintf("❌ Error: %v", err))
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	if len(groupsMap) == 0 {
+		editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Tidak ada grup yang terdeteksi.\n\nGrup akan otomatis terdeteksi saat bot menerima pesan dari grup.")
+		telegramBot.Send(editMsg)
+		return
+	}
+
+	// Convert to slice with natural sorting
+	groups := []GroupLinkInfo{}
+	for _, group := range utils.SortGroupsNaturally(groupsMap) {
+		groups = append(groups, GroupLinkInfo{JID: group.JID, Name: group.Name})
+	}
+
+	// Pagination
+	groupsPerPage := 10
+	totalPages := (len(groups) + groupsPerPage - 1) / groupsPerPage
+	if page < 1 {
+		page = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
+	start := (page - 1) * groupsPerPage
+	end := start + groupsPerPage
+	if end > len(groups) {
+		end = len(groups)
+	}
+
+	msg := fmt.Sprintf(`📋 **DAFTAR GRUP**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📊 **Total:** %d grup
+📄 **Halaman:** %d dari %d
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Ketik nomor grup untuk memilih:**
+(Contoh: 1,3,5 atau 1-10)
+
+`, len(groups), page, totalPages)
+
+	for i, group := range groups[start:end] {
+		msg += fmt.Sprintf("**%d.** %s\n", start+i+1, group.Name)
+	}
+
+	msg += `
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 **Cara Pilih:**
+• Ketik: **1** (pilih 1 grup)
+• Ketik: **1,3,5** (pilih beberapa)
+• Ketik: **1-10** (pilih range)
+• Ketik: **all** (pilih semua)
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+
+	// Navigation buttons
+	navRow := []tgbotapi.InlineKeyboardButton{}
+	if page > 1 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("⬅️ Prev", fmt.Sprintf("rename_page_%d", page-1)))
+	}
+	navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("📄 %d/%d", page, totalPages), "noop"))
+	if page < totalPages {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("➡️ Next", fmt.Sprintf("rename_page_%d", page+1)))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, navRow)
+
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⚡ Pilih Semua", "rename_all_groups"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "rename_group_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, msg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+
+	// Store state with custom marker to distinguish from link selection
+	listSelectStates[chatID] = &ListSelectState{
+		CurrentPage:    page,
+		TotalPages:     totalPages,
+		GroupsPerPage:  groupsPerPage,
+		AllGroups:      groups,
+		SelectedGroups: make(map[int]bool),
+	}
+	renameSelection[chatID] = true
+}
+
+// IsWaitingForRenameSelection checks if user is selecting groups for rename
+func IsWaitingForRenameSelection(chatID int64) bool {
+	return renameSelection[chatID] && listSelectStates[chatID] != nil
+}
+
+// ProcessSelectedGroupsForRename processes selected groups untuk ganti nama
+func ProcessSelectedGroupsForRename(selection string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	selectedGroups := HandleGroupSelection(selection, chatID, telegramBot)
+
+	if len(selectedGroups) == 0 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Pilihan tidak valid!\n\nContoh: 1, 1-5, 1,3,5, atau 'all'")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	// Clear rename selection marker
+	delete(renameSelection, chatID)
+	delete(listSelectStates, chatID)
+
+	state := &GroupRenameState{}
+	groupRenameStates[chatID] = state
+
+	groups := make(map[string]string, len(selectedGroups))
+	for _, group := range selectedGroups {
+		groups[group.JID] = group.Name
+	}
+	setRenameSelectedGroups(state, groups)
+	askDelayForRename(state, chatID, telegramBot, fmt.Sprintf("✅ **GRUP TERPILIH**\n\n📊 **Total terpilih:** %d grup", len(state.SelectedGroups)))
+}
+
+// HandleRenameAllGroups handles "Ganti Semua" untuk ganti nama grup
+func HandleRenameAllGroups(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	groupsMap, err := utils.GetAllGroupsFromDB()
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: %v", err))
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	if len(groupsMap) == 0 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Tidak ada grup yang tersedia.")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	delete(renameSelection, chatID)
+	delete(listSelectStates, chatID)
+
+	state := &GroupRenameState{}
+	groupRenameStates[chatID] = state
+
+	setRenameSelectedGroups(state, groupsMap)
+	askDelayForRename(state, chatID, telegramBot, fmt.Sprintf("⚡ **GANTI NAMA SEMUA GRUP**\n\n📊 **Total grup:** %d grup\n\n⚠️ **PERINGATAN:**\nAnda akan mengganti nama SEMUA grup sekaligus!", len(state.SelectedGroups)))
+}
+
+// setRenameSelectedGroups menyimpan grup terpilih dengan urutan natural (dipakai untuk penomoran {no})
+func setRenameSelectedGroups(state *GroupRenameState, groups map[string]string) {
+	state.SelectedGroups = []GroupLinkInfo{}
+	for _, group := range utils.SortGroupsNaturally(groups) {
+		state.SelectedGroups = append(state.SelectedGroups, GroupLinkInfo{
+			JID:  group.JID,
+			Name: group.Name,
+		})
+	}
+	state.WaitingForGroupName = false
+	state.WaitingForDelay = true
+}
+
+// askDelayForRename menampilkan grup terpilih dan meminta input delay
+func askDelayForRename(state *GroupRenameState, chatID int64, telegramBot *tgbotapi.BotAPI, header string) {
+	resultMsg := header + "\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n**Daftar grup (urutan natural):**\n\n"
+
+	for i, group := range state.SelectedGroups {
+		resultMsg += fmt.Sprintf("%d. %s\n", i+1, group.Name)
+		if i >= 9 && len(state.SelectedGroups) > 10 {
+			resultMsg += fmt.Sprintf("... dan %d grup lainnya\n", len(state.SelectedGroups)-10)
+			break
+		}
+	}
+
+	resultMsg += `
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏱️ **TENTUKAN DELAY**
+
+Ketik delay (dalam detik) antar grup.
+
+💡 **Rekomendasi:**
+• < 10 grup: 1-2 detik
+• 10-30 grup: 2-3 detik
+• > 30 grup: 3-5 detik
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏳ Menunggu input delay...`
+
+	msg := tgbotapi.NewMessage(chatID, resultMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_rename_group"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleDelayInputForRename memproses input delay lalu meminta pilihan mode pola
+func HandleDelayInputForRename(input string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForDelay {
+		return
+	}
+
+	var delay int
+	_, err := fmt.Sscanf(strings.TrimSpace(input), "%d", &delay)
+	if err != nil || delay < 0 || delay > 60 {
+		errorMsg := tgbotapi.NewMessage(chatID, "❌ Input tidak valid!\n\nDelay harus berupa angka antara 0-60 detik.\n\nContoh: 2, 5, 10")
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	state.DelaySeconds = delay
+	state.WaitingForDelay = false
+	showRenameModeChoice(state, chatID, telegramBot)
+}
+
+// showRenameModeChoice meminta user memilih mode pola (template/penomoran atau regex)
+func showRenameModeChoice(state *GroupRenameState, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state.WaitingForMode = true
+	state.WaitingForPattern = false
+	state.WaitingForConfirm = false
+
+	modeMsg := fmt.Sprintf(`✏️ **PILIH POLA NAMA**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+✅ **Grup dipilih:** %d grup
+⏱️ **Delay:** %d detik per grup
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📝 **Template / Penomoran** - Susun nama baru dengan %s dan %s
+🔁 **Regex** - Cari & ganti bagian nama dengan regular expression
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏳ Klik salah satu button di bawah...`, len(state.SelectedGroups), state.DelaySeconds, "`{name}`", "`{no}`")
+
+	msg := tgbotapi.NewMessage(chatID, modeMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📝 Template / Penomoran", "rename_mode_template"),
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Regex", "rename_mode_regex"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_rename_group"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandleRenameModeChoice memproses pilihan mode pola dari button
+func HandleRenameModeChoice(mode string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForMode {
+		return
+	}
+
+	state.Mode = mode
+	state.WaitingForMode = false
+	state.WaitingForPattern = true
+
+	var promptMsg string
+	if mode == RenameModeRegex {
+		promptMsg = "🔁 **MODE REGEX**\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+			"Ketik 2 baris:\n**Baris 1:** pola regex yang dicari\n**Baris 2:** teks pengganti (kosongkan untuk menghapus)\n\n" +
+			"**Contoh:**\n`2025`\n`2026`\n\nGrup tangkap seperti `$1` didukung.\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n⏳ Menunggu pola..."
+	} else {
+		promptMsg = "📝 **MODE TEMPLATE / PENOMORAN**\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n" +
+			"Ketik template nama baru.\n\n**Placeholder:**\n• `{name}` = nama grup saat ini\n• `{no}` = nomor urut (urutan natural)\n• `{no:3}` = nomor urut 3 digit (001)\n• `{{.MemberCount}}`, `{{.CreatedAt}}`, `{{.Tags.kota}}` = metadata grup\n\n" +
+			"**Contoh:**\n• `{name} - 2026`\n• `ANGKATAN {no}`\n• `{no:2}. {name}`\n\nMulai dari nomor lain? Tambahkan baris kedua `mulai=11`\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n⏳ Menunggu template..."
+	}
+
+	msg := tgbotapi.NewMessage(chatID, promptMsg)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_rename_group"),
+		),
+	)
+	telegramBot.Send(msg)
+}
+
+// HandlePatternInputForRename memproses pola dari user lalu menampilkan preview nama lama → baru
+func HandlePatternInputForRename(input string, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForPattern {
+		return
+	}
+
+	var plan []GroupRenameItem
+	var err error
+	if state.Mode == RenameModeRegex {
+		plan, err = BuildRegexRenamePlan(state.SelectedGroups, input)
+	} else {
+		if IsGroupTemplate(input) {
+			loadingMsg, _ := telegramBot.Send(tgbotapi.NewMessage(chatID, "⏳ Merender template untuk setiap grup..."))
+			defer telegramBot.Request(tgbotapi.NewDeleteMessage(chatID, loadingMsg.MessageID))
+		}
+		plan, err = BuildTemplateRenamePlan(GetWhatsAppClient(), state.SelectedGroups, input)
+	}
+	if err != nil {
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Pola tidak valid!\n\n%v\n\nSilakan ketik ulang pola.", err))
+		telegramBot.Send(errorMsg)
+		return
+	}
+
+	state.Pattern = input
+	state.Plan = plan
+	state.WaitingForPattern = false
+	state.WaitingForConfirm = true
+
+	showRenamePreview(state, chatID, telegramBot)
+}
+
+// BuildTemplateRenamePlan membuat rencana ganti nama dari template {name}/{no}
+// Template per grup ({{.Name}}, {{.MemberCount}}, dll) juga didukung dan dirender dengan metadata grup
+// Baris kedua opsional "mulai=N" mengatur nomor awal penomoran
+func BuildTemplateRenamePlan(client *whatsmeow.Client, groups []GroupLinkInfo, input string) ([]GroupRenameItem, error) {
+	lines := strings.Split(strings.TrimSpace(input), "\n")
+	template := strings.TrimSpace(lines[0])
+	if template == "" {
+		return nil, fmt.Errorf("template tidak boleh kosong")
+	}
+
+	startNumber := 1
+	if len(lines) > 1 {
+		option := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lines[1]), " ", ""))
+		value := strings.TrimPrefix(option, "mulai=")
+		n, err := strconv.Atoi(value)
+		if value == option || err != nil || n < 0 {
+			return nil, fmt.Errorf("baris kedua harus berformat mulai=N (contoh: mulai=11)")
+		}
+		startNumber = n
+	}
+
+	isGroupTemplate := IsGroupTemplate(template)
+	if !isGroupTemplate && !strings.Contains(template, "{name}") && !renameNumberPlaceholder.MatchString(template) && len(groups) > 1 {
+		return nil, fmt.Errorf("template tanpa {name} atau {no} akan membuat semua grup bernama sama")
+	}
+
+	var groupTemplate *texttemplate.Template
+	if isGroupTemplate {
+		tmpl, err := ParseGroupTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		groupTemplate = tmpl
+	}
+
+	plan := make([]GroupRenameItem, 0, len(groups))
+	for i, group := range groups {
+		number := startNumber + i
+		baseName := template
+		if groupTemplate != nil {
+			data := LoadGroupTemplateData(client, group, i, template)
+			data.No = number
+			rendered, err := RenderGroupTemplate(groupTemplate, data)
+			if err != nil {
+				return nil, err
+			}
+			baseName = rendered
+		}
+
+		newName := renameNumberPlaceholder.ReplaceAllStringFunc(baseName, func(placeholder string) string {
+			width := renameNumberPlaceholder.FindStringSubmatch(placeholder)[1]
+			if width == "" {
+				return strconv.Itoa(number)
+			}
+			return fmt.Sprintf("%0*d", mustAtoi(width), number)
+		})
+		newName = strings.ReplaceAll(newName, "{name}", group.Name)
+		plan = append(plan, newRenameItem(group, newName))
+	}
+	return plan, nil
+}
+
+// BuildRegexRenamePlan membuat rencana ganti nama dengan regex find/replace (baris 1 pola, baris 2 pengganti)
+func BuildRegexRenamePlan(groups []GroupLinkInfo, input string) ([]GroupRenameItem, error) {
+	lines := strings.SplitN(strings.TrimRight(input, "\n"), "\n", 2)
+	// Spasi di pola/pengganti dipertahankan karena bisa jadi bagian dari yang dicari
+	pattern := strings.TrimRight(lines[0], "\r")
+	if strings.TrimSpace(pattern) == "" {
+		return nil, fmt.Errorf("pola regex tidak boleh kosong")
+	}
+	replacement := ""
+	if len(lines) > 1 {
+		replacement = strings.TrimRight(lines[1], "\r")
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("regex tidak valid: %v", err)
+	}
+
+	plan := make([]GroupRenameItem, 0, len(groups))
+	for _, group := range groups {
+		plan = append(plan, newRenameItem(group, re.ReplaceAllString(group.Name, replacement)))
+	}
+	return plan, nil
+}
+
+// newRenameItem membuat item rencana dan menandai grup yang harus dilewati
+func newRenameItem(group GroupLinkInfo, newName string) GroupRenameItem {
+	newName = strings.TrimSpace(newName)
+	item := GroupRenameItem{JID: group.JID, OldName: group.Name, NewName: newName}
+
+	switch {
+	case newName == "":
+		item.Skip = "nama baru kosong"
+	case newName == group.Name:
+		item.Skip = "nama tidak berubah"
+	case utf8.RuneCountInString(newName) > maxGroupNameLength:
+		item.Skip = fmt.Sprintf("lebih dari %d karakter", maxGroupNameLength)
+	}
+	return item
+}
+
+// mustAtoi mengubah string angka (sudah divalidasi regex) menjadi int
+func mustAtoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// countRenameItems menghitung jumlah grup yang akan di-rename
+func countRenameItems(plan []GroupRenameItem) int {
+	count := 0
+	for _, item := range plan {
+		if item.Skip == "" {
+			count++
+		}
+	}
+	return count
+}
+
+// showRenamePreview menampilkan preview nama lama → baru sebelum diterapkan
+// Jika grup banyak, preview lengkap dikirim sebagai file .txt
+func showRenamePreview(state *GroupRenameState, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	const previewLimit = 15
+
+	toRename := countRenameItems(state.Plan)
+
+	var preview strings.Builder
+	preview.WriteString("👀 **PREVIEW GANTI NAMA**\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	preview.WriteString(fmt.Sprintf("✏️ **Akan diganti:** %d grup\n", toRename))
+	preview.WriteString(fmt.Sprintf("⏭️ **Dilewati:** %d grup\n", len(state.Plan)-toRename))
+	preview.WriteString(fmt.Sprintf("⏱️ **Delay:** %d detik per grup\n\n", state.DelaySeconds))
+
+	for i, item := range state.Plan {
+		if i >= previewLimit {
+			preview.WriteString(fmt.Sprintf("\n... dan %d grup lainnya (lihat file preview)\n", len(state.Plan)-previewLimit))
+			break
+		}
+		if item.Skip != "" {
+			preview.WriteString(fmt.Sprintf("%d. %s ⏭️ (%s)\n", i+1, item.OldName, item.Skip))
+		} else {
+			preview.WriteString(fmt.Sprintf("%d. %s\n    → %s\n", i+1, item.OldName, item.NewName))
+		}
+	}
+
+	preview.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n💡 Periksa preview lalu klik **Terapkan** untuk mulai mengganti nama.")
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if toRename > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Terapkan", "rename_apply"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✏️ Ubah Pola", "rename_change_pattern"),
+		tgbotapi.NewInlineKeyboardButtonData("❌ Batalkan", "cancel_rename_group"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, preview.String())
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := telegramBot.Send(msg); err != nil {
+		// Nama grup bisa mengandung karakter Markdown, kirim ulang tanpa format
+		msg.ParseMode = ""
+		telegramBot.Send(msg)
+	}
+
+	if len(state.Plan) > previewLimit {
+		sendRenamePreviewFile(state.Plan, chatID, telegramBot)
+	}
+}
+
+// sendRenamePreviewFile mengirim preview lengkap sebagai file .txt
+func sendRenamePreviewFile(plan []GroupRenameItem, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	var content strings.Builder
+	for i, item := range plan {
+		if item.Skip != "" {
+			content.WriteString(fmt.Sprintf("%d. %s (DILEWATI: %s)\n", i+1, item.OldName, item.Skip))
+		} else {
+			content.WriteString(fmt.Sprintf("%d. %s -> %s\n", i+1, item.OldName, item.NewName))
+		}
+	}
+
+	filename := fmt.Sprintf("preview_ganti_nama_%d.txt", time.Now().Unix())
+	if err := os.WriteFile(filename, []byte(content.String()), 0644); err != nil {
+		utils.GetLogger().Warn("Rename: Gagal membuat file preview: %v", err)
+		return
+	}
+	defer os.Remove(filename)
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FilePath(filename))
+	doc.Caption = fmt.Sprintf("📄 Preview lengkap ganti nama (%d grup)", len(plan))
+	telegramBot.Send(doc)
+}
+
+// HandleRenameChangePattern kembali ke pilihan mode pola dari preview
+func HandleRenameChangePattern(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForConfirm {
+		return
+	}
+	state.Plan = nil
+	showRenameModeChoice(state, chatID, telegramBot)
+}
+
+// HandleRenameApply menerapkan rencana ganti nama setelah user konfirmasi preview
+func HandleRenameApply(chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := groupRenameStates[chatID]
+	if state == nil || !state.WaitingForConfirm {
+		return
+	}
+
+	plan := []GroupRenameItem{}
+	for _, item := range state.Plan {
+		if item.Skip == "" {
+			plan = append(plan, item)
+		}
+	}
+	delay := state.DelaySeconds
+
+	// Clear state
+	delete(groupRenameStates, chatID)
+
+	startMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🚀 Memulai ganti nama untuk %d grup...", len(plan)))
+	telegramBot.Send(startMsg)
+
+	groups := make([]GroupLinkInfo, len(plan))
+	for i, item := range plan {
+		groups[i] = GroupLinkInfo{JID: item.JID, Name: item.OldName}
+	}
+	StartWithAdminPreflight(chatID, "Ganti Nama", groups, requireEditInfo, client, telegramBot, func(allowed []GroupLinkInfo) {
+		allowedJIDs := make(map[string]bool)
+		for _, group := range allowed {
+			allowedJIDs[group.JID] = true
+		}
+		filtered := []GroupRenameItem{}
+		for _, item := range plan {
+			if allowedJIDs[item.JID] {
+				filtered = append(filtered, item)
+			}
+		}
+		ProcessRenameGroups(filtered, delay, chatID, client, telegramBot)
+	})
+}
+
+// ProcessRenameGroups memproses ganti nama grup sesuai rencana
+func ProcessRenameGroups(plan []GroupRenameItem, delay int, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	totalGroups := len(plan)
+	successCount := 0
+	failedCount := 0
+	var failedGroups []string
+
+	var progressMsgSent *tgbotapi.Message
+
+	items := make([]string, 0, len(plan))
+	for _, item := range plan {
+		items = append(items, item.OldName)
+	}
+
+	// Daftarkan sebagai job berjalan agar bisa dijeda (checkpoint) saat graceful shutdown
+	job := StartGroupJob(chatID, "Ganti Nama Grup", items)
+	defer job.Finish()
+
+	for i, item := range plan {
+		if !job.Next(i) {
+			break // Bot shutdown: sisa item sudah disimpan, hasil yang sudah diproses tetap dikirim
+		}
+
+		// Ambil active client di setiap iterasi untuk proses panjang
+		validClient, shouldStop := ValidateClientForBackgroundProcess(client, "ProcessRenameGroups", i, totalGroups)
+		if shouldStop {
+			disconnectMsg := fmt.Sprintf("⚠️ **PROSES DIHENTIKAN**\n\nClient WhatsApp terputus pada grup %d/%d\n\n✅ Berhasil: %d\n❌ Gagal: %d", i+1, totalGroups, successCount, failedCount)
+			notifMsg := tgbotapi.NewMessage(chatID, disconnectMsg)
+			notifMsg.ParseMode = "Markdown"
+			telegramBot.Send(notifMsg)
+			break
+		}
+
+		jid, err := parseJIDFromString(item.JID)
+		if err != nil {
+			failedCount++
+			failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (invalid JID)", item.OldName))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(job.Context(), 30*time.Second)
+		err = validClient.SetGroupName(ctx, jid, item.NewName)
+		cancel()
+
+		if err != nil {
+			failedCount++
+			failedGroups = append(failedGroups, fmt.Sprintf("❌ %s (%v)", item.OldName, err))
+		} else {
+			successCount++
+			// Perbarui nama grup di database lokal agar pencarian langsung memakai nama baru
+			if err := utils.SaveGroupToDB(item.JID, item.NewName); err != nil {
+				utils.GetLogger().Warn("Rename: Gagal update nama grup %s di database: %v", item.JID, err)
+			}
+		}
+
+		// Show progress if more than 3 groups
+		if totalGroups > 3 {
+			progressPercent := ((i + 1) * 100) / totalGroups
+			progressBar := generateProgressBar(progressPercent)
+
+			progressMsg := fmt.Sprintf(`⏳ **PROGRESS**
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+%s **%d%%**
+📊 **Diproses:** %d/%d grup
+✅ **Berhasil:** %d
+❌ **Gagal:** %d
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+⏳ Sedang memproses...`, progressBar, progressPercent, i+1, totalGroups, successCount, failedCount)
+
+			if progressMsgSent == nil {
+				updateMsg := tgbotapi.NewMessage(chatID, progressMsg)
+				updateMsg.ParseMode = "Markdown"
+				sent, _ := telegramBot.Send(updateMsg)
+				progressMsgSent = &sent
+			} else {
+				editMsg := tgbotapi.NewEditMessageText(chatID, progressMsgSent.MessageID, progressMsg)
+				editMsg.ParseMode = "Markdown"
+				telegramBot.Send(editMsg)
+			}
+		}
+
+		// Delay
+		if delay > 0 && i < len(plan)-1 {
+			job.Sleep(time.Duration(delay) * time.Second)
+		}
+	}
+
+	// Delete progress message
+	if progressMsgSent != nil {
+		deleteMsg := tgbotapi.NewDeleteMessage(chatID, progressMsgSent.MessageID)
+		telegramBot.Request(deleteMsg)
+	}
+
+	resultMsg := job.PausedNote() + fmt.Sprintf(`🎉 **SELESAI!**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📊 **RINGKASAN**
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+✅ **Berhasil:** %d grup
+❌ **Gagal:** %d grup
+⏱️ **Delay:** %d detik/grup
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━`, successCount, failedCount, delay)
+
+	msg := tgbotapi.NewMessage(chatID, resultMsg)
+	msg.ParseMode = "Markdown"
+	telegramBot.Send(msg)
+
+	// Send failed groups if any (batching)
+	if len(failedGroups) > 0 {
+		batchSize := 10
+		for i := 0; i < len(failedGroups); i += batchSize {
+			end := i + batchSize
+			if end > len(failedGroups) {
+				end = len(failedGroups)
+			}
+
+			failedMsg := fmt.Sprintf("Grup yang Gagal (Batch %d):\n\n%s", (i/batchSize)+1, strings.Join(failedGroups[i:end], "\n"))
+			telegramBot.Send(tgbotapi.NewMessage(chatID, failedMsg))
+
+			if end < len(failedGroups) {
+				time.Sleep(1 * time.Second)
+			}
+		}
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✏️ Ganti Lagi", "rename_group_menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Menu Grup", "grup"),
+		),
+	)
+
+	completionMsg := tgbotapi.NewMessage(chatID, "💡 Apa yang ingin Anda lakukan selanjutnya?")
+	completionMsg.ReplyMarkup = keyboard
+	telegramBot.Send(completionMsg)
+}
+
+// CancelRenameGroup membatalkan proses ganti nama grup
+func CancelRenameGroup(chatID int64, telegramBot *tgbotapi.BotAPI) {
+	delete(groupRenameStates, chatID)
+	delete(renameSelection, chatID)
+
+	msg := tgbotapi.NewMessage(chatID, "❌ Proses ganti nama grup dibatalkan.")
+	telegramBot.Send(msg)
+}
+
+// IsWaitingForRenameInput checks if user is waiting to input rename-related data
+func IsWaitingForRenameInput(chatID int64) bool {
+	state := groupRenameStates[chatID]
+	return state != nil && (state.WaitingForGroupName || state.WaitingForDelay || state.WaitingForPattern)
+}
+
+// GetRenameInputType returns the current input type
+func GetRenameInputType(chatID int64) string {
+	state := groupRenameStates[chatID]
+	if state == nil {
+		return ""
+	}
+
+	if state.WaitingForGroupName {
+		return "group_name"
+	}
+	if state.WaitingForDelay {
+		return "delay"
+	}
+	if state.WaitingForPattern {
+		return "pattern"
+	}
+
+	return ""
+}
@@ -0,0 +1,443 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// WelcomeState menyimpan state input template sambutan per chat
+type WelcomeState struct {
+	WaitingForInput bool
+}
+
+// pendingWelcome adalah anggota baru satu grup yang menunggu disambut (digabung selama cooldown grup)
+type pendingWelcome struct {
+	Members []types.JID
+	Timer   *time.Timer
+}
+
+var (
+	welcomeStates = make(map[int64]*WelcomeState)
+
+	welcomeMutex    sync.Mutex
+	welcomePending  = make(map[string]*pendingWelcome) // key: akun|grup
+	welcomeLastSent = make(map[string]time.Time)       // key: akun|grup
+	welcomeSeen     = make(map[string]time.Time)       // key: akun|grup|anggota, cegah sambutan ganda dari event berulang
+)
+
+const (
+	// Jeda minimal antar pesan sambutan di satu grup; anggota yang masuk selama jeda digabung dalam satu pesan
+	welcomeGroupCooldown = 2 * time.Minute
+	// Anggota yang sama tidak disambut ulang dalam jangka ini (event join bisa terkirim ulang saat reconnect)
+	welcomeDedupWindow = 30 * time.Minute
+	// Maksimal anggota yang di-mention dalam satu pesan sambutan
+	welcomeMaxMentions = 20
+	// Separator antara pesan sambutan dan peraturan grup pada input template
+	welcomeRulesSeparator = "---"
+	// Template bawaan jika pesan tidak diisi
+	defaultWelcomeMessage = "👋 Selamat datang {mention} di *{group}*!"
+)
+
+// ShowWelcomeMenuEdit menampilkan saklar sambutan dan daftar template sambutan akun
+func ShowWelcomeMenuEdit(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	text, keyboard := buildWelcomeMenu()
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// buildWelcomeMenu membuat teks daftar template sambutan (plain text karena template bebas)
+func buildWelcomeMenu() (string, tgbotapi.InlineKeyboardMarkup) {
+	accountID := clientAccountID(GetWhatsAppClient())
+	enabled, _ := utils.IsWelcomeEnabledAt("", accountID)
+	templates, err := utils.GetWelcomeTemplatesAt("", accountID)
+
+	var text strings.Builder
+	text.WriteString("👋 PESAN SAMBUTAN ANGGOTA BARU\n\n")
+	text.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	statusLabel := "🔴 OFF"
+	if enabled {
+		statusLabel = "🟢 ON"
+	}
+	text.WriteString(fmt.Sprintf("Status: %s\n\n", statusLabel))
+	text.WriteString("Saat ada anggota baru di grup yang punya template, akun ini mengirim pesan sambutan dengan mention anggota baru dan peraturan grup.\n\n")
+	text.WriteString(fmt.Sprintf("⏱️ Maksimal 1 pesan per grup tiap %s; anggota yang masuk bersamaan digabung dalam satu pesan.\n", formatStateDuration(welcomeGroupCooldown)))
+	text.WriteString("🛡️ Hanya dikirim di grup tempat akun ini admin.\n")
+
+	if err != nil {
+		text.WriteString(fmt.Sprintf("\n❌ Gagal memuat template: %v\n", err))
+	}
+
+	text.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	if len(templates) == 0 && err == nil {
+		text.WriteString("Belum ada template. Klik ➕ untuk menambahkan.")
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, template := range templates {
+		label := template.TargetName
+		if label == "" {
+			label = template.Target
+		}
+		icon := "👥"
+		if template.IsTagTarget() {
+			icon = "🏷️"
+		}
+		rulesInfo := "tanpa peraturan"
+		if template.Rules != "" {
+			rulesInfo = "dengan peraturan"
+		}
+		text.WriteString(fmt.Sprintf("%d. %s %s\n   📝 %s\n", i+1, icon, label, rulesInfo))
+
+		id := fmt.Sprintf("%d", template.ID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("👁️ Preview %d", i+1), "welcome_preview_"+id),
+			tgbotapi.NewInlineKeyboardButtonData("🗑️", "welcome_delete_"+id),
+		))
+	}
+
+	toggleLabel := "🟢 Aktifkan Sambutan"
+	if enabled {
+		toggleLabel = "🔴 Matikan Sambutan"
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(toggleLabel, "welcome_toggle"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ Tambah / Ubah Template", "welcome_add"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu_grup"),
+		),
+	)
+
+	return text.String(), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// ToggleWelcome menyalakan / mematikan sambutan anggota baru untuk akun aktif
+func ToggleWelcome(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	accountID := clientAccountID(GetWhatsAppClient())
+	enabled, _ := utils.IsWelcomeEnabledAt("", accountID)
+	if err := utils.SetWelcomeEnabled(accountID, !enabled); err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal mengubah status sambutan"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+	utils.LogActivity("welcome_toggle", fmt.Sprintf("Welcome messages enabled: %v", !enabled), chatID)
+	ShowWelcomeMenuEdit(telegramBot, chatID, messageID)
+}
+
+// StartWelcomeInput meminta input template sambutan (target + pesan + peraturan)
+func StartWelcomeInput(telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	welcomeStates[chatID] = &WelcomeState{WaitingForInput: true}
+
+	promptMsg := `👋 **INPUT TEMPLATE SAMBUTAN**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**Format:**
+Baris 1: nama grup (persis) atau koleksi tag
+Baris berikutnya: pesan sambutan
+Setelah baris ` + "`---`" + `: peraturan grup (opsional)
+
+**Contoh:**
+` + "```\ntag:kota=Jakarta\nHalo {mention}, selamat datang di {group}! 👋\n---\n1. Dilarang spam\n2. Gunakan bahasa yang sopan\n```" + `
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+**🔤 Variabel:**
+• ` + "`{mention}`" + ` - mention anggota baru
+• ` + "`{group}`" + ` - nama grup
+• ` + "`{count}`" + ` - jumlah anggota grup
+• ` + "`{rules}`" + ` - peraturan (jika tidak dipakai, peraturan ditambahkan di akhir pesan)
+
+💡 Template grup lebih diutamakan daripada template tag. Target yang sama akan ditimpa.
+
+⏳ Menunggu input...`
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Batal", "welcome_menu"),
+		),
+	)
+
+	editMsg := tgbotapi.NewEditMessageText(chatID, messageID, promptMsg)
+	editMsg.ParseMode = "Markdown"
+	editMsg.ReplyMarkup = &keyboard
+	telegramBot.Send(editMsg)
+}
+
+// HandleWelcomeInput menyimpan template sambutan dari input user lalu menampilkan daftar template
+func HandleWelcomeInput(input string, chatID int64, client *whatsmeow.Client, telegramBot *tgbotapi.BotAPI) {
+	state := welcomeStates[chatID]
+	if state == nil || !state.WaitingForInput {
+		return
+	}
+
+	target, body, _ := strings.Cut(strings.TrimSpace(input), "\n")
+	target = strings.TrimSpace(target)
+	if target == "" {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ Format salah. Baris 1 berisi nama grup / tag:key=value, baris berikutnya pesan sambutan."))
+		return
+	}
+
+	template := &utils.WelcomeTemplate{AccountID: clientAccountID(client)}
+	var err error
+	template.Target, template.TargetName, err = resolveGroupOrTagTarget(target)
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "❌ "+err.Error()))
+		return
+	}
+
+	message, rules := splitWelcomeRules(body)
+	if message == "" {
+		message = defaultWelcomeMessage
+	}
+	template.Message = message
+	template.Rules = rules
+
+	if err := utils.SaveWelcomeTemplate(template); err != nil {
+		msg := tgbotapi.NewMessage(chatID, utils.FormatUserError(utils.ErrorDatabase, err, "Gagal menyimpan template sambutan"))
+		msg.ParseMode = "Markdown"
+		telegramBot.Send(msg)
+		return
+	}
+	delete(welcomeStates, chatID)
+
+	result := fmt.Sprintf("✅ Template sambutan disimpan untuk %s", template.TargetName)
+	if !strings.Contains(template.Message, "{mention}") {
+		result += "\n💡 Template tidak memakai {mention}; anggota baru tetap di-mention di awal pesan."
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, result))
+
+	text, keyboard := buildWelcomeMenu()
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	telegramBot.Send(msg)
+}
+
+// splitWelcomeRules memisahkan pesan sambutan dan peraturan grup (dipisah baris "---")
+func splitWelcomeRules(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == welcomeRulesSeparator {
+			return strings.TrimSpace(strings.Join(lines[:i], "\n")), strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		}
+	}
+	return strings.TrimSpace(body), ""
+}
+
+// renderWelcomeMessage mengisi variabel template sambutan
+// mentions berisi "@nomor" anggota baru; groupName dan memberCount dari info grup
+func renderWelcomeMessage(template *utils.WelcomeTemplate, mentions []string, groupName string, memberCount int) string {
+	mentionText := strings.Join(mentions, " ")
+	message := template.Message
+	if !strings.Contains(message, "{mention}") && mentionText != "" {
+		message = mentionText + "\n" + message
+	}
+
+	replacer := strings.NewReplacer(
+		"{mention}", mentionText,
+		"{group}", groupName,
+		"{count}", fmt.Sprintf("%d", memberCount),
+		"{rules}", template.Rules,
+	)
+	text := replacer.Replace(message)
+
+	if template.Rules != "" && !strings.Contains(template.Message, "{rules}") {
+		text += "\n\n📜 *Peraturan Grup:*\n" + template.Rules
+	}
+	return strings.TrimSpace(text)
+}
+
+// PreviewWelcomeTemplate mengirim contoh pesan sambutan hasil render template
+func PreviewWelcomeTemplate(templateID int64, chatID int64, telegramBot *tgbotapi.BotAPI) {
+	templates, err := utils.GetWelcomeTemplatesAt("", clientAccountID(GetWhatsAppClient()))
+	if err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal memuat template: %v", err)))
+		return
+	}
+
+	for _, template := range templates {
+		if template.ID != templateID {
+			continue
+		}
+		groupName := template.TargetName
+		if template.IsTagTarget() {
+			groupName = "Nama Grup"
+		}
+		preview := renderWelcomeMessage(&template, []string{"@628123456789"}, groupName, 42)
+		telegramBot.Send(tgbotapi.NewMessage(chatID, "👁️ PREVIEW SAMBUTAN\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n"+preview))
+		return
+	}
+	telegramBot.Send(tgbotapi.NewMessage(chatID, "⚠️ Template tidak ditemukan."))
+}
+
+// DeleteWelcomeTemplateEntry menghapus template sambutan lalu menampilkan ulang daftar
+func DeleteWelcomeTemplateEntry(templateID int64, telegramBot *tgbotapi.BotAPI, chatID int64, messageID int) {
+	if err := utils.DeleteWelcomeTemplate(clientAccountID(GetWhatsAppClient()), templateID); err != nil {
+		telegramBot.Send(tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Gagal menghapus template: %v", err)))
+		return
+	}
+	ShowWelcomeMenuEdit(telegramBot, chatID, messageID)
+}
+
+// IsWaitingForWelcomeInput mengecek apakah user sedang mengisi template sambutan
+func IsWaitingForWelcomeInput(chatID int64) bool {
+	state := welcomeStates[chatID]
+	return state != nil && state.WaitingForInput
+}
+
+// CancelWelcomeInput membatalkan input template sambutan
+func CancelWelcomeInput(chatID int64) {
+	delete(welcomeStates, chatID)
+}
+
+// WelcomeLabel mengembalikan label tombol menu grup sesuai status sambutan akun aktif
+func WelcomeLabel(client *whatsmeow.Client) string {
+	if enabled, _ := utils.IsWelcomeEnabledAt("", clientAccountID(client)); enabled {
+		return "👋 Sambutan: 🟢 ON"
+	}
+	return "👋 Sambutan: 🔴 OFF"
+}
+
+// QueueWelcomeForJoins menjadwalkan sambutan untuk anggota baru dari event GroupInfo (dbPath = database akun sumber event)
+// Anggota yang masuk selama cooldown grup digabung dalam satu pesan
+func QueueWelcomeForJoins(client *whatsmeow.Client, dbPath string, evt *events.GroupInfo) {
+	if client == nil || client.Store.ID == nil || len(evt.Join) == 0 {
+		return
+	}
+	accountID := client.Store.ID.User
+
+	if enabled, err := utils.IsWelcomeEnabledAt(dbPath, accountID); err != nil || !enabled {
+		return
+	}
+	if template, err := utils.FindWelcomeTemplateAt(dbPath, accountID, evt.JID.String()); err != nil || template == nil {
+		return
+	}
+
+	groupKey := accountID + "|" + evt.JID.String()
+	now := time.Now()
+
+	welcomeMutex.Lock()
+	defer welcomeMutex.Unlock()
+
+	for key, seenAt := range welcomeSeen {
+		if now.Sub(seenAt) > welcomeDedupWindow {
+			delete(welcomeSeen, key)
+		}
+	}
+
+	var members []types.JID
+	for _, jid := range evt.Join {
+		if isOwnJID(client, jid) {
+			continue // Akun ini sendiri yang masuk
+		}
+		memberKey := groupKey + "|" + jid.User
+		if _, seen := welcomeSeen[memberKey]; seen {
+			continue
+		}
+		welcomeSeen[memberKey] = now
+		members = append(members, jid)
+	}
+	if len(members) == 0 {
+		return
+	}
+
+	pending := welcomePending[groupKey]
+	if pending != nil {
+		pending.Members = append(pending.Members, members...)
+		return // Timer yang sudah berjalan akan mengirim semuanya sekaligus
+	}
+
+	delay := time.Duration(0)
+	if last, ok := welcomeLastSent[groupKey]; ok && now.Sub(last) < welcomeGroupCooldown {
+		delay = welcomeGroupCooldown - now.Sub(last)
+	}
+	pending = &pendingWelcome{Members: members}
+	welcomePending[groupKey] = pending
+	groupJID := evt.JID
+	pending.Timer = time.AfterFunc(delay, func() {
+		welcomeMutex.Lock()
+		members := pending.Members
+		delete(welcomePending, groupKey)
+		welcomeLastSent[groupKey] = time.Now()
+		welcomeMutex.Unlock()
+
+		if err := sendWelcomeMessage(client, dbPath, groupJID, members); err != nil {
+			utils.GetGrupLogger().Debug("Welcome: gagal mengirim sambutan ke %s: %v", groupJID, err)
+		}
+	})
+}
+
+// sendWelcomeMessage mengirim satu pesan sambutan untuk anggota baru (hanya jika akun ini admin grup)
+func sendWelcomeMessage(client *whatsmeow.Client, dbPath string, groupJID types.JID, members []types.JID) error {
+	if client == nil || client.Store.ID == nil || !client.IsConnected() {
+		return fmt.Errorf("client WhatsApp tidak terhubung")
+	}
+
+	// Template dicek ulang: bisa saja dihapus / sambutan dimatikan selama cooldown
+	accountID := client.Store.ID.User
+	if enabled, err := utils.IsWelcomeEnabledAt(dbPath, accountID); err != nil || !enabled {
+		return err
+	}
+	template, err := utils.FindWelcomeTemplateAt(dbPath, accountID, groupJID.String())
+	if err != nil || template == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	info, err := client.GetGroupInfo(ctx, groupJID)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	isAdmin := false
+	for _, participant := range info.Participants {
+		if isOwnJID(client, participant.JID) || isOwnJID(client, participant.PhoneNumber) || isOwnJID(client, participant.LID) {
+			isAdmin = participant.IsAdmin || participant.IsSuperAdmin
+			break
+		}
+	}
+	if !isAdmin {
+		return fmt.Errorf("akun bukan admin grup")
+	}
+
+	if len(members) > welcomeMaxMentions {
+		members = members[:welcomeMaxMentions]
+	}
+	var mentions []string
+	var mentionedJIDs []string
+	for _, jid := range members {
+		mentions = append(mentions, "@"+jid.User)
+		mentionedJIDs = append(mentionedJIDs, jid.String())
+	}
+
+	text := renderWelcomeMessage(template, mentions, info.Name, len(info.Participants))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_, err = client.SendMessage(ctx, groupJID, &waProto.Message{
+		ExtendedTextMessage: &waProto.ExtendedTextMessage{
+			Text: proto.String(text),
+			ContextInfo: &waProto.ContextInfo{
+				MentionedJID: mentionedJIDs,
+			},
+		},
+	})
+	return err
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-bot/utils"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// GroupJob menyimpan progress satu proses massal (bulk) yang sedang berjalan
+// Dipakai untuk graceful shutdown: job dihentikan di batas item berikutnya dan sisa item disimpan untuk dilanjutkan
+type GroupJob struct {
+	ID        int64
+	ChatID    int64
+	Name      string   // Nama proses yang ditampilkan ke user (contoh: "Ubah Deskripsi")
+	Items     []string // Item yang diproses (nama grup / link / nama grup baru) untuk file resume
+	StartedAt time.Time
+
+	mutex       sync.Mutex
+	next        int // Index item yang sedang/akan diproses (item sebelum index ini sudah selesai)
+	paused      bool
+	checkpoints sync.Once
+	done        chan struct{}
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+var (
+	runningJobs      = make(map[int64]*GroupJob)
+	runningJobsMutex sync.Mutex
+	jobIDCounter     int64
+	shuttingDown     bool
+	shutdownCh       = make(chan struct{})
+)
+
+// IsShuttingDown mengecek apakah bot sedang dalam proses shutdown (tidak menerima pekerjaan baru)
+func IsShuttingDown() bool {
+	runningJobsMutex.Lock()
+	defer runningJobsMutex.Unlock()
+	return shuttingDown
+}
+
+// BeginShutdown menandai bot sedang shutdown: job baru ditolak dan job berjalan akan berhenti di item berikutnya
+func BeginShutdown() {
+	runningJobsMutex.Lock()
+	defer runningJobsMutex.Unlock()
+	if !shuttingDown {
+		shuttingDown = true
+		close(shutdownCh)
+	}
+}
+
+// StartGroupJob mendaftarkan proses massal yang sedang berjalan
+// Selalu panggil job.Finish() (defer) setelah proses selesai
+func StartGroupJob(chatID int64, name string, items []string) *GroupJob {
+	runningJobsMutex.Lock()
+	defer runningJobsMutex.Unlock()
+
+	jobIDCounter++
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &GroupJob{
+		ID:        jobIDCounter,
+		ChatID:    chatID,
+		Name:      name,
+		Items:     items,
+		StartedAt: time.Now(),
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	runningJobs[job.ID] = job
+	return job
+}
+
+// groupJobItems mengubah daftar grup menjadi item job (nama grup, format sama dengan input file .txt)
+func groupJobItems(groups []GroupLinkInfo) []string {
+	items := make([]string, 0, len(groups))
+	for _, group := range groups {
+		items = append(items, group.Name)
+	}
+	return items
+}
+
+// Context dipakai untuk request WhatsApp di dalam job; dibatalkan saat job di-checkpoint
+// sehingga request yang masih menggantung tidak menahan shutdown
+func (j *GroupJob) Context() context.Context {
+	return j.ctx
+}
+
+// Next menandai item ke-index akan diproses
+// Return false jika bot sedang shutdown: job sudah di-checkpoint dan caller harus keluar dari loop
+// (break, bukan return) agar hasil sebagian tetap dikirim dan file sementara dibersihkan
+func (j *GroupJob) Next(index int) bool {
+	j.mutex.Lock()
+	j.next = index
+	j.mutex.Unlock()
+
+	if IsShuttingDown() {
+		j.checkpoint()
+		return false
+	}
+	return true
+}
+
+// Sleep menunggu delay antar item, berhenti lebih awal jika shutdown dimulai
+// Return false jika terpotong shutdown: loop di dalam satu item (mis. per nomor) harus berhenti,
+// bukan melanjutkan tanpa jeda anti-spam
+func (j *GroupJob) Sleep(d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-shutdownCh:
+		return false
+	}
+}
+
+// Paused mengecek apakah job dijeda karena shutdown
+func (j *GroupJob) Paused() bool {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.paused
+}
+
+// PausedNote mengembalikan catatan pembuka ringkasan hasil jika job dijeda karena shutdown ("" jika selesai normal)
+func (j *GroupJob) PausedNote() string {
+	if !j.Paused() {
+		return ""
+	}
+	return "⏸️ Proses dijeda karena bot dimatikan. Ringkasan di bawah hanya mencakup item yang sudah diproses.\n\n"
+}
+
+// Finish menandai job selesai (atau berhenti) dan menghapusnya dari daftar job berjalan
+func (j *GroupJob) Finish() {
+	runningJobsMutex.Lock()
+	defer runningJobsMutex.Unlock()
+
+	if _, exists := runningJobs[j.ID]; exists {
+		delete(runningJobs, j.ID)
+		close(j.done)
+	}
+	j.cancel()
+}
+
+// checkpoint menyimpan sisa item job ke database master dan memberi tahu user pemilik job
+func (j *GroupJob) checkpoint() {
+	j.checkpoints.Do(func() {
+		j.mutex.Lock()
+		j.paused = true
+		processed := j.next
+		j.mutex.Unlock()
+		j.cancel()
+
+		if processed > len(j.Items) {
+			processed = len(j.Items)
+		}
+		remaining := j.Items[processed:]
+
+		if err := savePausedJob(j, processed, remaining); err != nil {
+			utils.GetLogger().Warn("GroupJob: Gagal simpan checkpoint job %d (%s): %v", j.ID, j.Name, err)
+		}
+		utils.GetLogger().Info("GroupJob: Job %d (%s) dijeda pada %d/%d item untuk chat %d", j.ID, j.Name, processed, len(j.Items), j.ChatID)
+
+		notifyPausedJob(j, processed, remaining)
+	})
+}
+
+// WaitForGroupJobs menunggu semua job berjalan selesai/checkpoint sampai deadline context
+// Job yang masih berjalan saat deadline akan di-checkpoint paksa berdasarkan progress terakhir
+func WaitForGroupJobs(ctx context.Context) int {
+	runningJobsMutex.Lock()
+	jobs := make([]*GroupJob, 0, len(runningJobs))
+	for _, job := range runningJobs {
+		jobs = append(jobs, job)
+	}
+	runningJobsMutex.Unlock()
+
+	forced := 0
+	for _, job := range jobs {
+		select {
+		case <-job.done:
+		case <-ctx.Done():
+			job.checkpoint()
+			forced++
+		}
+	}
+	return forced
+}
+
+// initPausedJobsTable membuat tabel untuk menyimpan job yang dijeda saat shutdown
+func initPausedJobsTable() error {
+	db, err := getMasterBotDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS paused_jobs (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			telegram_chat_id INTEGER NOT NULL,
+			job_name TEXT NOT NULL,
+			total_items INTEGER NOT NULL,
+			processed_items INTEGER NOT NULL,
+			remaining_items TEXT,
+			paused_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// savePausedJob menyimpan checkpoint job ke database master
+func savePausedJob(j *GroupJob, processed int, remaining []string) error {
+	if err := initPausedJobsTable(); err != nil {
+		return err
+	}
+
+	db, err := getMasterBotDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`
+		INSERT INTO paused_jobs (telegram_chat_id, job_name, total_items, processed_items, remaining_items)
+		VALUES (?, ?, ?, ?, ?)
+	`, j.ChatID, j.Name, len(j.Items), processed, strings.Join(remaining, "\n"))
+	return err
+}
+
+// notifyPausedJob memberi tahu user bahwa job-nya dijeda dan mengirim file sisa item untuk dilanjutkan
+func notifyPausedJob(j *GroupJob, processed int, remaining []string) {
+	if TgBot == nil {
+		return
+	}
+
+	notification := fmt.Sprintf(`⏸️ **PROSES DIJEDA**
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+Bot sedang dimatikan (maintenance/restart).
+
+📋 **Proses:** %s
+📊 **Selesai:** %d/%d
+⏳ **Sisa:** %d
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 **Cara melanjutkan:**
+Setelah bot aktif kembali, buka menu %s lalu upload file sisa di bawah ini sebagai input.`, j.Name, processed, len(j.Items), len(remaining), j.Name)
+
+	msg := tgbotapi.NewMessage(j.ChatID, notification)
+	msg.ParseMode = "Markdown"
+	TgBot.Send(msg)
+
+	if len(remaining) == 0 {
+		return
+	}
+
+	filename := fmt.Sprintf("sisa_proses_%d_%s.txt", j.ID, time.Now().Format("20060102_150405"))
+	if err := os.WriteFile(filename, []byte(strings.Join(remaining, "\n")+"\n"), 0644); err != nil {
+		utils.GetLogger().Warn("GroupJob: Gagal membuat file sisa job %d: %v", j.ID, err)
+		return
+	}
+	defer os.Remove(filename)
+
+	doc := tgbotapi.NewDocument(j.ChatID, tgbotapi.FilePath(filename))
+	doc.Caption = fmt.Sprintf("📄 Sisa %d item - %s", len(remaining), j.Name)
+	TgBot.Send(doc)
+}
@@ -36,13 +36,19 @@ type WhatsAppAccount struct {
 	UpdatedAt     time.Time
 }
 
+// AccountEventHandlerFactory membuat event handler WhatsApp yang terikat ke satu account ID
+// Diset dari main (package core) karena handlers tidak boleh import core
+type AccountEventHandlerFactory func(accountID int) func(evt interface{})
+
 // AccountManager mengelola multiple WhatsApp accounts
 type AccountManager struct {
-	accounts    map[int]*WhatsAppAccount  // Map account ID -> Account info
-	clients     map[int]*whatsmeow.Client // Map account ID -> WhatsApp client
-	currentID   int                       // Current active account ID
-	mutex       sync.RWMutex
-	telegramBot *tgbotapi.BotAPI
+	accounts            map[int]*WhatsAppAccount  // Map account ID -> Account info
+	clients             map[int]*whatsmeow.Client // Map account ID -> WhatsApp client
+	currentID           int                       // Current active account ID
+	mutex               sync.RWMutex
+	telegramBot         *tgbotapi.BotAPI
+	eventHandlerFactory AccountEventHandlerFactory
+	registeredHandlers  map[*whatsmeow.Client]int // Client yang sudah punya event handler -> account ID
 }
 
 var accountManager *AccountManager
@@ -62,9 +68,10 @@ var deleteConfirmMutex sync.Mutex
 func GetAccountManager() *AccountManager {
 	accountManagerOnce.Do(func() {
 		accountManager = &AccountManager{
-			accounts:  make(map[int]*WhatsAppAccount),
-			clients:   make(map[int]*whatsmeow.Client),
-			currentID: -1,
+			accounts:           make(map[int]*WhatsAppAccount),
+			clients:            make(map[int]*whatsmeow.Client),
+			currentID:          -1,
+			registeredHandlers: make(map[*whatsmeow.Client]int),
 		}
 	})
 	return accountManager
@@ -77,13 +84,89 @@ func (am *AccountManager) SetTelegramBot(bot *tgbotapi.BotAPI) {
 	am.telegramBot = bot
 }
 
+// SetEventHandlerFactory mengatur factory event handler per akun
+// Setiap client yang disimpan di AccountManager akan mendapat handler yang tahu account ID-nya
+func (am *AccountManager) SetEventHandlerFactory(factory AccountEventHandlerFactory) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+	am.eventHandlerFactory = factory
+}
+
+// setClient menyimpan client untuk account ID dan mendaftarkan event handler per akun
+// Event dari client ini akan selalu membawa account ID sumbernya (tidak perlu menebak dari global client)
+func (am *AccountManager) setClient(accountID int, client *whatsmeow.Client) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	am.clients[accountID] = client
+	if client == nil || am.eventHandlerFactory == nil {
+		return
+	}
+
+	// Reconnect ditangani supervisor akun (exponential backoff + state tercatat), bukan auto-reconnect whatsmeow
+	client.EnableAutoReconnect = false
+
+	// Cegah handler terdaftar dua kali pada client yang sama
+	if _, registered := am.registeredHandlers[client]; registered {
+		return
+	}
+	client.AddEventHandler(am.eventHandlerFactory(accountID))
+	am.registeredHandlers[client] = accountID
+}
+
+// GetAccountIDByClient mencari account ID pemilik client (return -1 jika tidak ditemukan)
+func (am *AccountManager) GetAccountIDByClient(client *whatsmeow.Client) int {
+	if client == nil {
+		return -1
+	}
+
+	am.mutex.RLock()
+	defer am.mutex.RUnlock()
+
+	if accountID, exists := am.registeredHandlers[client]; exists {
+		return accountID
+	}
+	for accountID, accClient := range am.clients {
+		if accClient == client {
+			return accountID
+		}
+	}
+	return -1
+}
+
+// GetAccountTelegramID mengambil Telegram ID pemilik akun dari BotDataDBPath
+// Mendukung format baru (bot_data-{telegramID}-{phone}.db) dan format lama (bot_data(telegramID)>)
+// Return 0 jika tidak bisa di-parse
+func GetAccountTelegramID(account *WhatsAppAccount) int64 {
+	if account == nil || account.BotDataDBPath == "" {
+		return 0
+	}
+
+	reNew := regexp.MustCompile(`bot_data-(\d+)-(\d+)\.db`)
+	if matches := reNew.FindStringSubmatch(account.BotDataDBPath); len(matches) >= 2 {
+		if parsedID, err := strconv.ParseInt(matches[1], 10, 64); err == nil {
+			return parsedID
+		}
+	}
+
+	reOld := regexp.MustCompile(`bot_data\((\d+)\)>`)
+	if matches := reOld.FindStringSubmatch(account.BotDataDBPath); len(matches) >= 2 {
+		if parsedID, err := strconv.ParseInt(matches[1], 10, 64); err == nil {
+			return parsedID
+		}
+	}
+
+	return 0
+}
+
 // getMasterBotDB mendapatkan database master untuk menyimpan info akun multi-account
 // Selalu menggunakan bot_data.db default, bukan database dinamis
 func getMasterBotDB() (*sql.DB, error) {
 	// Selalu gunakan bot_data.db sebagai database master untuk multi-account
 	// Gunakan DELETE mode untuk menghilangkan -shm dan -wal files
+	// _busy_timeout mencegah "database is locked" saat banyak akun menulis status bersamaan (startup paralel)
 	dbPath := "bot_data.db"
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=DELETE&_cache=shared")
+	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=DELETE&_cache=shared&_busy_timeout=10000")
 	if err != nil {
 		return nil, err
 	}
@@ -110,8 +193,17 @@ func InitAccountDB() error {
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Tabel state lifecycle koneksi akun (supervisor)
+	if err := initAccountStateTables(); err != nil {
+		return err
+	}
+
+	// Tabel checkpoint job yang dijeda saat graceful shutdown
+	return initPausedJobsTable()
 }
 
 // LoadAccounts memuat semua akun dari database
@@ -528,6 +620,20 @@ func (am *AccountManager) SyncAccountStatus(accountID int) error {
 		actualStatus = "inactive"
 	}
 
+	// Selaraskan state supervisor jika event koneksi terlewat
+	state := GetAccountStatus(accountID).State
+	if actualStatus == "active" && state != StateConnected {
+		TransitionAccountState(accountID, StateConnected, "sinkronisasi status")
+		return nil
+	}
+	if actualStatus == "inactive" && state == StateConnected {
+		TransitionAccountState(accountID, StateDisconnected, "sinkronisasi status: client tidak terhubung")
+		if client != nil {
+			startAccountReconnect(accountID, 0)
+		}
+		return nil
+	}
+
 	// Update jika berbeda
 	if account.Status != actualStatus {
 		return am.UpdateAccountStatus(accountID, actualStatus)
@@ -687,6 +793,28 @@ func (am *AccountManager) GetClient(accountID int) *whatsmeow.Client {
 	return nil
 }
 
+// DisconnectAll memutuskan koneksi semua client WhatsApp (dipakai saat graceful shutdown)
+// Client tetap disimpan di map agar state akun tidak berubah; return jumlah client yang diputus
+func (am *AccountManager) DisconnectAll() int {
+	am.mutex.RLock()
+	clients := make([]*whatsmeow.Client, 0, len(am.clients))
+	for _, client := range am.clients {
+		if client != nil {
+			clients = append(clients, client)
+		}
+	}
+	am.mutex.RUnlock()
+
+	disconnected := 0
+	for _, client := range clients {
+		if client.IsConnected() {
+			client.Disconnect()
+			disconnected++
+		}
+	}
+	return disconnected
+}
+
 // SetCurrentAccount mengatur akun yang sedang aktif
 // FIXED: Validasi status account sebelum set current
 func (am *AccountManager) SetCurrentAccount(id int) error {
@@ -959,6 +1087,12 @@ func (am *AccountManager) CreateClient(accountID int) (*whatsmeow.Client, error)
 		return nil, fmt.Errorf("akun dengan ID %d tidak ditemukan", accountID)
 	}
 
+	// Kunci database sesi agar tidak dibuka dua proses sekaligus (penyebab stream replaced & SQLite lock error)
+	// Akun yang database-nya dikunci proses lain dilewati tanpa mengubah state-nya
+	if err := utils.AcquireDBLock(account.DBPath); err != nil {
+		return nil, err
+	}
+
 	// Setup WhatsApp database store
 	// FIXED: Tambahkan _busy_timeout dan _locking_mode untuk mencegah "database table is locked" saat concurrent access
 	// Gunakan DELETE mode untuk menghilangkan -shm dan -wal files
@@ -997,13 +1131,13 @@ func (am *AccountManager) CreateClient(accountID int) (*whatsmeow.Client, error)
 	clientLog := &utils.FilteredLogger{Logger: baseLog}
 	waClient := whatsmeow.NewClient(deviceStore, clientLog)
 
-	// Store client
-	am.mutex.Lock()
-	am.clients[accountID] = waClient
-	am.mutex.Unlock()
+	// Store client dan daftarkan event handler per akun (sebelum Connect agar event awal tidak terlewat)
+	am.setClient(accountID, waClient)
 
 	// Connect to WhatsApp
+	TransitionAccountState(accountID, StateConnecting, "membuat client")
 	if err := waClient.Connect(); err != nil {
+		TransitionAccountState(accountID, StateDisconnected, fmt.Sprintf("gagal connect: %v", err))
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
@@ -1018,6 +1152,7 @@ func (am *AccountManager) CreateClient(accountID int) (*whatsmeow.Client, error)
 	}
 
 	if !waClient.IsConnected() {
+		TransitionAccountState(accountID, StateDisconnected, "connection timeout")
 		return nil, fmt.Errorf("connection timeout")
 	}
 
@@ -1059,8 +1194,16 @@ func (am *AccountManager) RemoveAccount(id int) error {
 			client.Disconnect()
 		}
 		delete(am.clients, id)
+		delete(am.registeredHandlers, client)
 	}
 
+	// Lepas lock database sesi sebelum file database dihapus
+	utils.ReleaseDBLock(dbPath)
+
+	// Hentikan supervisor dan lepas state terkini akun (riwayat transisi tetap disimpan)
+	RemoveAccountSupervisor(id)
+	clearAccountCurrentState(id)
+
 	// Jika ini current account, set ke akun lain atau -1
 	if am.currentID == id {
 		// Cari akun aktif lain
@@ -1094,6 +1237,7 @@ func (am *AccountManager) RemoveAccount(id int) error {
 	// Tutup connection pool database untuk akun ini terlebih dahulu
 	// (jika ada pool untuk account ini)
 	utils.CloseDBPools()
+	utils.CloseBotDBPoolForPath(botDataDBPath)
 
 	// Hapus file database WhatsApp dan Bot Data beserta file pendukungnya
 	dbFiles := []string{
@@ -1144,6 +1288,7 @@ Fitur ini memungkinkan Anda untuk login hingga **%d akun WhatsApp** sekaligus.
 • ➕ Login akun WhatsApp baru
 • 📋 Lihat daftar semua akun
 • 🔄 Ganti akun aktif
+• 📡 Status koneksi setiap akun
 • 🗑️ Hapus akun
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -1170,6 +1315,9 @@ Fitur ini memungkinkan Anda untuk login hingga **%d akun WhatsApp** sekaligus.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔄 Ganti Akun", "multi_account_switch"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📡 Status Koneksi", "account_status_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu"),
 		),
@@ -1200,6 +1348,7 @@ Fitur ini memungkinkan Anda untuk login hingga **%d akun WhatsApp** sekaligus.
 • ➕ Login akun WhatsApp baru
 • 📋 Lihat daftar semua akun
 • 🔄 Ganti akun aktif
+• 📡 Status koneksi setiap akun
 • 🗑️ Hapus akun
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -1223,6 +1372,9 @@ Fitur ini memungkinkan Anda untuk login hingga **%d akun WhatsApp** sekaligus.
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔄 Ganti Akun", "multi_account_switch"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📡 Status Koneksi", "account_status_menu"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔙 Kembali", "menu"),
 		),
@@ -1647,10 +1799,9 @@ func processMultiAccountPairing(phoneNumber, dbPath, botDataDBPath string, chatI
 				return
 			}
 
-			// Simpan client
-			am.mutex.Lock()
-			am.clients[account.ID] = waClient
-			am.mutex.Unlock()
+			// Simpan client dan daftarkan event handler per akun
+			am.setClient(account.ID, waClient)
+			TransitionAccountState(account.ID, StateConnected, "pairing berhasil")
 
 			// ✅ AMAN: Set sebagai current jika ini akun pertama untuk user ini
 			// Cek jumlah akun hanya untuk user yang memanggil (filter by TelegramID)
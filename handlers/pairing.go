@@ -171,6 +171,15 @@ func PairDeviceViaTelegram(phone string, chatID int64) error {
 		utils.GetLogger().Info("[Pairing] Folder database verified: writable, path=%s", dbDir)
 	}
 
+	// Login ulang nomor yang sudah terdaftar: catat state pairing, kembali ke logout jika pairing tidak selesai
+	pairingAccountID := beginAccountPairing(chatID, cleanPhone)
+	paired := false
+	defer func() {
+		if pairingAccountID >= 0 && !paired {
+			TransitionAccountState(pairingAccountID, StateLoggedOut, "pairing ulang tidak selesai")
+		}
+	}()
+
 	// CRITICAL FIX: Hapus database lama jika ada untuk memastikan pairing fresh
 	if _, err := os.Stat(dbPath); err == nil {
 		utils.GetLogger().Info("[Pairing] Database lama ditemukan, menghapus untuk pairing fresh: %s", dbPath)
@@ -609,9 +618,11 @@ func PairDeviceViaTelegram(phone string, chatID int64) error {
 			for _, acc := range allAccounts {
 				if acc.PhoneNumber == whatsappNumber {
 					accountExists = true
+					TransitionAccountState(acc.ID, StateConnected, "pairing ulang berhasil")
 					break
 				}
 			}
+			paired = true
 
 			// Jika belum terdaftar, daftarkan sekarang
 			if !accountExists {
@@ -624,10 +635,9 @@ func PairDeviceViaTelegram(phone string, chatID int64) error {
 				// ✅ AMAN: Pass chatID (TelegramID) untuk validasi ownership
 				account, err := am.AddAccount(whatsappNumber, newWhatsAppDB, newBotDataDB, chatID)
 				if err == nil {
-					// Simpan client ke account manager
-					am.mutex.Lock()
-					am.clients[account.ID] = client
-					am.mutex.Unlock()
+					// Simpan client ke account manager (sekaligus daftarkan event handler per akun)
+					am.setClient(account.ID, client)
+					TransitionAccountState(account.ID, StateConnected, "pairing berhasil")
 
 					// Set sebagai current jika belum ada current
 					if am.GetCurrentAccount() == nil {
@@ -150,6 +150,7 @@ func HandleTelegramCommand(message *tgbotapi.Message, client *whatsmeow.Client,
 
 👥 **GRUP**
    /grup - Manajemen grup WhatsApp
+   /tag <grup> | <key>=<nilai> - Tag custom untuk template grup
 
 🔧 **PENGATURAN**
    /pair <nomor> - Pairing WhatsApp
@@ -237,6 +238,10 @@ func HandleTelegramCommand(message *tgbotapi.Message, client *whatsmeow.Client,
 			telegramBot.Send(errorMsg)
 		}
 
+	case "tag":
+		// Atur/lihat tag custom per grup untuk template
+		HandleGroupTagCommand(args, chatID, telegramBot)
+
 	case "stopchat":
 		// Stop broadcast jika sedang berjalan
 		if IsBroadcastRunning(chatID) {
@@ -648,6 +653,35 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		// Batalkan proses ambil link
 		CancelGetLink(chatID, telegramBot)
 
+	case "export_valid_links":
+		// Export link valid dari registry (hanya entri lama yang dicek/diambil ulang)
+		exportClient := GetClientForUser(int64(chatID), telegramBot, activeClient)
+		if exportClient == nil || exportClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		go ExportValidInviteLinks(chatID, exportClient, telegramBot)
+
+	case "reset_link_menu":
+		// Menu reset link undangan grup (link bocor) - EDIT existing message
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		ShowResetLinkMenuEdit(telegramBot, chatID, messageID)
+
+	case "reset_link_confirm":
+		// Konfirmasi reset link, mulai proses
+		resetClient := GetClientForUser(int64(chatID), telegramBot, activeClient)
+		if resetClient == nil || resetClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		ConfirmResetLinks(chatID, resetClient, telegramBot)
+
 	case "change_photo_menu":
 		// Handler untuk menu ganti foto profil grup - EDIT existing message
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -674,6 +708,36 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		// Batalkan proses ganti foto
 		CancelChangePhoto(chatID, telegramBot)
 
+	case "photo_avatar_initials", "photo_avatar_name":
+		// Buat avatar teks untuk grup terpilih yang belum punya foto
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		StartAvatarGeneration(data == "photo_avatar_initials", chatID, activeClient, telegramBot)
+
+	case "start_zip_photo":
+		// Mulai proses foto per grup dari file ZIP
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		StartZipPhotoProcess(telegramBot, chatID)
+
+	case "zip_photo_apply":
+		// Jalankan ganti foto per grup setelah laporan pencocokan dikonfirmasi
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		ApplyZipPhotos(chatID, activeClient, telegramBot)
+
+	case "cancel_zip_photo":
+		CancelZipPhoto(chatID, telegramBot)
+
 	case "change_description_menu":
 		// Handler untuk menu atur deskripsi grup - EDIT existing message
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -696,6 +760,24 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		// Tampilkan contoh penggunaan ubah deskripsi - EDIT existing message
 		ShowDescriptionExampleEdit(telegramBot, chatID, messageID)
 
+	case "csv_import_menu":
+		ShowCSVImportMenuEdit(telegramBot, chatID, messageID)
+
+	case "start_csv_import":
+		StartCSVImportProcess(chatID, telegramBot)
+
+	case "cancel_csv_import":
+		CancelCSVImport(chatID, telegramBot)
+
+	case "description_template_apply":
+		// Terapkan deskripsi template per grup setelah preview
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		ApplyDescriptionTemplate(chatID, activeClient, telegramBot)
+
 	case "cancel_change_description":
 		// Batalkan proses ubah deskripsi
 		CancelChangeDescription(chatID, telegramBot)
@@ -788,6 +870,69 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		// Batalkan proses atur tambah anggota
 		CancelChangeMemberAdd(chatID, telegramBot)
 
+	case "rename_group_menu":
+		// Handler untuk menu ganti nama grup massal - EDIT existing message
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		ShowRenameGroupMenuEdit(telegramBot, chatID, messageID)
+
+	case "start_rename_group":
+		// Mulai proses ganti nama grup (cari manual / file .txt)
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		StartRenameGroupProcess(telegramBot, chatID)
+
+	case "show_group_list_rename":
+		// CRITICAL FIX: Gunakan client dari session user yang benar
+		if userClient == nil || userClient.Store == nil || userClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		ShowGroupListForRenameEdit(telegramBot, chatID, messageID, 1)
+
+	case "rename_all_groups":
+		// Ganti nama semua grup
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		HandleRenameAllGroups(chatID, telegramBot)
+
+	case "rename_example":
+		// Tampilkan contoh pola ganti nama - EDIT existing message
+		ShowRenameExampleEdit(telegramBot, chatID, messageID)
+
+	case "rename_mode_template":
+		HandleRenameModeChoice(RenameModeTemplate, chatID, telegramBot)
+
+	case "rename_mode_regex":
+		HandleRenameModeChoice(RenameModeRegex, chatID, telegramBot)
+
+	case "rename_change_pattern":
+		// Kembali ke pilihan pola dari preview
+		HandleRenameChangePattern(chatID, telegramBot)
+
+	case "rename_apply":
+		// Terapkan rencana ganti nama setelah preview
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		HandleRenameApply(chatID, activeClient, telegramBot)
+
+	case "cancel_rename_group":
+		// Batalkan proses ganti nama grup
+		CancelRenameGroup(chatID, telegramBot)
+
 	case "change_join_approval_menu":
 		// Handler untuk menu atur persetujuan anggota baru - EDIT existing message
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -1108,6 +1253,110 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		}
 		ShowCreateGroupMenuEdit(telegramBot, chatID, messageID)
 
+	case "community_menu":
+		// Handler untuk daftar komunitas WhatsApp
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		go ShowCommunityMenu(chatID, messageID, activeClient, telegramBot)
+
+	case "community_create":
+		StartCommunityCreate(chatID, messageID, telegramBot)
+
+	case "community_link":
+		StartCommunityLink(chatID, messageID, telegramBot)
+
+	case "community_back":
+		// Kembali / refresh detail komunitas yang sedang dibuka
+		go ShowCommunityDetail(chatID, messageID, activeClient, telegramBot)
+
+	case "channel_menu":
+		// Handler untuk daftar saluran WhatsApp (newsletter)
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		go ShowChannelMenu(chatID, messageID, activeClient, telegramBot)
+
+	case "channel_create":
+		StartChannelInput(channelInputCreate, chatID, messageID, telegramBot)
+
+	case "channel_edit_name":
+		StartChannelInput(channelInputName, chatID, messageID, telegramBot)
+
+	case "channel_edit_description":
+		StartChannelInput(channelInputDescription, chatID, messageID, telegramBot)
+
+	case "channel_edit_photo":
+		StartChannelInput(channelInputPhoto, chatID, messageID, telegramBot)
+
+	case "channel_get_link":
+		go SendChannelInviteLink(chatID, activeClient, telegramBot)
+
+	case "channel_back":
+		ShowChannelDetail(chatID, messageID, telegramBot)
+
+	case "welcome_menu":
+		// Handler untuk template pesan sambutan anggota baru
+		CancelWelcomeInput(chatID)
+		ShowWelcomeMenuEdit(telegramBot, chatID, messageID)
+
+	case "welcome_toggle":
+		ToggleWelcome(telegramBot, chatID, messageID)
+
+	case "welcome_add":
+		StartWelcomeInput(telegramBot, chatID, messageID)
+
+	case "moderation_menu":
+		// Handler untuk aturan moderasi otomatis
+		CancelModerationInput(chatID)
+		ShowModerationMenuEdit(telegramBot, chatID, messageID)
+
+	case "mod_add":
+		StartModerationInput(telegramBot, chatID, messageID)
+
+	case "blocklist_menu":
+		// Handler untuk blocklist nomor peserta
+		CancelBlocklistInput(chatID)
+		ShowBlocklistMenuEdit(telegramBot, chatID, messageID)
+
+	case "blocklist_add":
+		StartBlocklistInput(true, telegramBot, chatID, messageID)
+
+	case "blocklist_remove":
+		StartBlocklistInput(false, telegramBot, chatID, messageID)
+
+	case "blocklist_sweep":
+		go ScanBlocklistSweep(chatID, messageID, activeClient, telegramBot)
+
+	case "blocklist_sweep_apply":
+		go ApplyBlocklistSweep(chatID, messageID, activeClient, telegramBot)
+
+	case "watch_menu":
+		// Handler untuk aturan pantau kata kunci
+		CancelKeywordWatchInput(chatID)
+		ShowKeywordWatchMenuEdit(telegramBot, chatID, messageID)
+
+	case "watch_add":
+		StartKeywordWatchInput(telegramBot, chatID, messageID)
+
+	case "provision_menu":
+		ShowProvisionMenuEdit(telegramBot, chatID, messageID)
+
+	case "start_provision":
+		if activeClient == nil || activeClient.Store.ID == nil {
+			msg := tgbotapi.NewMessage(chatID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(msg)
+			return
+		}
+		StartProvisionProcess(chatID, telegramBot)
+
+	case "cancel_provision":
+		CancelProvision(chatID, telegramBot)
+
 	case "create_group_mode_single":
 		// Opsi 1: Nama + Jumlah
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -1235,6 +1484,34 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		}
 		ShowUnadminMenuEdit(telegramBot, chatID, messageID)
 
+	case "preflight_continue":
+		// Lanjutkan operasi bulk hanya untuk grup yang lolos cek status admin
+		ContinueAdminPreflight(chatID, messageID, telegramBot)
+
+	case "preflight_cancel":
+		CancelAdminPreflight(chatID, messageID, telegramBot)
+
+	case "admin_roster_menu":
+		// Handler untuk daftar roster admin deklaratif
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		CancelAdminRosterInput(chatID)
+		ShowAdminRosterMenuEdit(telegramBot, chatID, messageID)
+
+	case "roster_add":
+		StartAdminRosterInput(telegramBot, chatID, messageID)
+
+	case "roster_apply":
+		if activeClient == nil || activeClient.Store.ID == nil {
+			callback := tgbotapi.NewCallback(callbackQuery.ID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Request(callback)
+			return
+		}
+		go ApplyAdminRoster(chatID, messageID, activeClient, telegramBot)
+
 	case "start_admin_process":
 		// Handler untuk memulai proses auto admin
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -1290,6 +1567,27 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 		}
 		ShowLeaveGroupMenuEdit(telegramBot, chatID, messageID)
 
+	case "rejoin_menu", "rejoin_menu_new":
+		// Daftar grup yang ditinggalkan dan masih bisa digabungi kembali (link tersimpan)
+		// rejoin_menu_new dari ringkasan keluar grup: kirim pesan baru agar ringkasan tidak tertimpa
+		delete(rejoinStates, chatID)
+		if data == "rejoin_menu_new" {
+			ShowRejoinMenu(chatID, 0, telegramBot)
+		} else {
+			ShowRejoinMenu(chatID, messageID, telegramBot)
+		}
+
+	case "rejoin_select_all", "rejoin_select_none":
+		SetAllRejoinSelection(data == "rejoin_select_all", chatID, messageID, telegramBot)
+
+	case "rejoin_confirm":
+		if activeClient == nil || activeClient.Store.ID == nil {
+			callback := tgbotapi.NewCallback(callbackQuery.ID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Request(callback)
+			return
+		}
+		go ProcessRejoinGroups(chatID, messageID, activeClient, telegramBot)
+
 	case "start_leave_group":
 		// Mulai proses keluar grup
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -1314,6 +1612,72 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 	case "leave_notification_no":
 		HandleNotificationChoiceForLeave(false, chatID, telegramBot)
 
+	case "leave_template_apply":
+		// Mulai keluar grup setelah preview notifikasi template dikonfirmasi
+		ApplyLeaveNotificationTemplate(chatID, telegramBot)
+
+	case "member_requests_menu":
+		// Scan permintaan masuk di grup yang kita kelola (EDIT message)
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		go ScanMemberRequests(chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_groups":
+		ShowMemberRequestGroupsEdit(chatID, messageID, telegramBot)
+
+	case "mreq_approve_all", "mreq_reject_all":
+		if activeClient == nil || activeClient.Store.ID == nil {
+			return
+		}
+		go HandleMemberRequestAction(data == "mreq_approve_all", "", chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_rules_apply":
+		if activeClient == nil || activeClient.Store.ID == nil {
+			return
+		}
+		go ApplyMemberRequestRulesToAll(chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_rules_menu":
+		ShowMemberRequestRulesEdit(chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_rules_edit":
+		StartMemberRequestRulesInput(chatID, telegramBot)
+
+	case "mreq_rules_toggle":
+		ToggleMemberRequestAutoApprove(chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_rules_clear":
+		ClearMemberRequestRules(chatID, messageID, activeClient, telegramBot)
+
+	case "mreq_rules_cancel":
+		CancelMemberRequestRulesInput(chatID, telegramBot)
+
+	case "join_requests_menu":
+		// Daftar permintaan join yang menunggu/sudah disetujui/ditolak (EDIT message)
+		ShowJoinRequestsEdit(telegramBot, chatID, messageID)
+
+	case "join_requests_refresh":
+		// Cek ulang permintaan pending langsung ke WhatsApp
+		if activeClient == nil || activeClient.Store.ID == nil {
+			editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+			telegramBot.Send(editMsg)
+			return
+		}
+		go RefreshPendingJoinRequests(chatID, messageID, activeClient, telegramBot)
+
+	case "join_select_all":
+		SetAllJoinPreviewSelection(true, chatID, messageID, telegramBot)
+
+	case "join_select_none":
+		SetAllJoinPreviewSelection(false, chatID, messageID, telegramBot)
+
+	case "join_preview_confirm":
+		// Lanjut ke input delay dengan link yang dipilih di preview
+		ConfirmJoinPreview(chatID, telegramBot)
+
 	case "process_join_group":
 		// Process join groups dengan client
 		if activeClient == nil || activeClient.Store.ID == nil {
@@ -1336,6 +1700,7 @@ Pesan akan divariasikan otomatis untuk menghindari spam detection
 
 👥 **GRUP**
    /grup - Manajemen grup WhatsApp
+   /tag <grup> | <key>=<nilai> - Tag custom untuk template grup
 
 🔧 **PENGATURAN**
    /pair <nomor> - Pairing WhatsApp
@@ -1472,6 +1837,10 @@ Pairing adalah proses menghubungkan bot dengan akun WhatsApp Anda.
 		// Tampilkan daftar akun untuk switch (EDIT, NO SPAM!)
 		ShowAccountListEdit(telegramBot, chatID, messageID)
 
+	case "account_status_menu":
+		// Tampilkan state koneksi setiap akun (EDIT, NO SPAM!)
+		ShowAccountStatusEdit(telegramBot, chatID, messageID)
+
 	case "multi_account_cancel_pairing":
 		// Batalkan pairing multi-account (EDIT, NO SPAM!)
 		delete(multiAccountLoginStates, chatID)
@@ -1524,6 +1893,149 @@ Pairing adalah proses menghubungkan bot dengan akun WhatsApp Anda.
 			}
 		}
 
+		// Handle pilih grup untuk gabung kembali (rejoin_toggle_X)
+		if strings.HasPrefix(data, "rejoin_toggle_") {
+			if recordID, err := strconv.ParseInt(strings.TrimPrefix(data, "rejoin_toggle_"), 10, 64); err == nil {
+				ToggleRejoinSelection(recordID, chatID, messageID, telegramBot)
+			}
+			return
+		}
+
+		// Handle buka saluran (channel_view_X)
+		if strings.HasPrefix(data, "channel_view_") {
+			if index, err := strconv.Atoi(strings.TrimPrefix(data, "channel_view_")); err == nil {
+				OpenChannel(index, chatID, messageID, telegramBot)
+			}
+			return
+		}
+
+		// Handle hapus aturan pantau kata kunci (watch_delete_ID)
+		if strings.HasPrefix(data, "watch_delete_") {
+			if id, err := strconv.ParseInt(strings.TrimPrefix(data, "watch_delete_"), 10, 64); err == nil {
+				DeleteKeywordWatchEntry(id, telegramBot, chatID, messageID)
+			}
+			return
+		}
+
+		// Handle hapus aturan moderasi (mod_delete_ID) dan banding tindakan moderasi (mod_undo_AKUN_ID)
+		if strings.HasPrefix(data, "mod_delete_") {
+			if id, err := strconv.ParseInt(strings.TrimPrefix(data, "mod_delete_"), 10, 64); err == nil {
+				DeleteModerationRuleEntry(id, telegramBot, chatID, messageID)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "mod_undo_") {
+			accountText, actionText, _ := strings.Cut(strings.TrimPrefix(data, "mod_undo_"), "_")
+			accountID, err1 := strconv.Atoi(accountText)
+			actionID, err2 := strconv.ParseInt(actionText, 10, 64)
+			if err1 == nil && err2 == nil {
+				go UndoModerationAction(accountID, actionID, chatID, messageID, telegramBot)
+			}
+			return
+		}
+
+		// Handle preview / hapus template sambutan (welcome_preview_ID, welcome_delete_ID)
+		if strings.HasPrefix(data, "welcome_preview_") {
+			if id, err := strconv.ParseInt(strings.TrimPrefix(data, "welcome_preview_"), 10, 64); err == nil {
+				PreviewWelcomeTemplate(id, chatID, telegramBot)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "welcome_delete_") {
+			if id, err := strconv.ParseInt(strings.TrimPrefix(data, "welcome_delete_"), 10, 64); err == nil {
+				DeleteWelcomeTemplateEntry(id, telegramBot, chatID, messageID)
+			}
+			return
+		}
+
+		// Handle buka komunitas / lepas subgrup komunitas (community_view_X, community_unlink_JID, community_unlink_ok_JID)
+		if strings.HasPrefix(data, "community_view_") {
+			if index, err := strconv.Atoi(strings.TrimPrefix(data, "community_view_")); err == nil {
+				go OpenCommunity(index, chatID, messageID, activeClient, telegramBot)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "community_unlink_ok_") {
+			go UnlinkCommunitySubGroup(strings.TrimPrefix(data, "community_unlink_ok_"), chatID, messageID, activeClient, telegramBot)
+			return
+		}
+		if strings.HasPrefix(data, "community_unlink_") {
+			ConfirmCommunityUnlink(strings.TrimPrefix(data, "community_unlink_"), chatID, messageID, telegramBot)
+			return
+		}
+
+		// Handle preview / toggle demote / hapus roster admin (roster_preview_X, roster_demote_X, roster_delete_X)
+		if strings.HasPrefix(data, "roster_preview_") {
+			if rosterID, err := strconv.ParseInt(strings.TrimPrefix(data, "roster_preview_"), 10, 64); err == nil && activeClient != nil {
+				go PreviewAdminRoster(rosterID, chatID, messageID, activeClient, telegramBot)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "roster_demote_") {
+			if rosterID, err := strconv.ParseInt(strings.TrimPrefix(data, "roster_demote_"), 10, 64); err == nil {
+				ToggleAdminRosterDemote(rosterID, telegramBot, chatID, messageID)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "roster_delete_") {
+			if rosterID, err := strconv.ParseInt(strings.TrimPrefix(data, "roster_delete_"), 10, 64); err == nil {
+				DeleteAdminRosterEntry(rosterID, telegramBot, chatID, messageID)
+			}
+			return
+		}
+
+		// Handle buka grup / setujui / tolak satu permintaan masuk (mreq_group_X, mreq_approve_JID, mreq_reject_JID)
+		if strings.HasPrefix(data, "mreq_group_") {
+			if index, err := strconv.Atoi(strings.TrimPrefix(data, "mreq_group_")); err == nil {
+				ShowMemberRequestGroupEdit(index, chatID, messageID, activeClient, telegramBot)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "mreq_approve_") || strings.HasPrefix(data, "mreq_reject_") {
+			approve := strings.HasPrefix(data, "mreq_approve_")
+			requester := strings.TrimPrefix(strings.TrimPrefix(data, "mreq_approve_"), "mreq_reject_")
+			if requester != "" && activeClient != nil {
+				go HandleMemberRequestAction(approve, requester, chatID, messageID, activeClient, telegramBot)
+			}
+			return
+		}
+
+		// Handle toggle & pagination preview link join (join_toggle_X, join_preview_page_X)
+		if strings.HasPrefix(data, "join_toggle_") {
+			if index, err := strconv.Atoi(strings.TrimPrefix(data, "join_toggle_")); err == nil {
+				ToggleJoinPreviewEntry(index, chatID, messageID, telegramBot)
+			}
+			return
+		}
+		if strings.HasPrefix(data, "join_preview_page_") {
+			if page, err := strconv.Atoi(strings.TrimPrefix(data, "join_preview_page_")); err == nil {
+				ShowJoinPreviewPage(page, chatID, messageID, telegramBot)
+			}
+			return
+		}
+
+		// Handle pilihan mode crop foto (photo_crop_X)
+		if strings.HasPrefix(data, "photo_crop_") {
+			SetPhotoCropMode(strings.TrimPrefix(data, "photo_crop_"), chatID, telegramBot)
+			return
+		}
+
+		// Handle pagination callbacks (rename_page_X)
+		if strings.HasPrefix(data, "rename_page_") {
+			pageStr := strings.TrimPrefix(data, "rename_page_")
+			page, err := strconv.Atoi(pageStr)
+			if err == nil {
+				// CRITICAL FIX: Gunakan client dari session user yang benar
+				if userClient == nil || userClient.Store == nil || userClient.Store.ID == nil {
+					editMsg := tgbotapi.NewEditMessageText(chatID, messageID, "❌ Bot WhatsApp belum terhubung.")
+					telegramBot.Send(editMsg)
+					return
+				}
+				ShowGroupListForRenameEdit(telegramBot, chatID, messageID, page)
+				return
+			}
+		}
+
 		// Handle pagination callbacks (join_approval_page_X)
 		if strings.HasPrefix(data, "join_approval_page_") {
 			pageStr := strings.TrimPrefix(data, "join_approval_page_")
@@ -1588,6 +2100,12 @@ Pairing adalah proses menghubungkan bot dengan akun WhatsApp Anda.
 			}
 		}
 
+		// Check if it's a manual reconnect callback (account_reconnect_<id>)
+		if strings.HasPrefix(data, "account_reconnect_") {
+			HandleAccountReconnectRequest(strings.TrimPrefix(data, "account_reconnect_"), telegramBot, chatID, messageID)
+			return
+		}
+
 		// Check if it's a switch account callback (multi_account_switch_<id>)
 		if strings.HasPrefix(data, "multi_account_switch_") {
 			var accountID int
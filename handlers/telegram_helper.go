@@ -24,3 +24,44 @@ func SendToTelegram(message string) {
 	// Tetap print ke console untuk logging
 	fmt.Println(message)
 }
+
+// SendToTelegramUser mengirim pesan ke Telegram user tertentu (misalnya pemilik akun WhatsApp)
+// Fallback ke SendToTelegram jika telegramID tidak diketahui
+func SendToTelegramUser(telegramID int64, message string) {
+	if telegramID == 0 {
+		SendToTelegram(message)
+		return
+	}
+	if TgBot != nil {
+		msg := tgbotapi.NewMessage(telegramID, message)
+		msg.ParseMode = "Markdown"
+		if _, err := TgBot.Send(msg); err != nil {
+			// Retry tanpa Markdown jika parsing gagal
+			msg.ParseMode = ""
+			TgBot.Send(msg)
+		}
+	}
+	// Tetap print ke console untuk logging
+	fmt.Println(message)
+}
+
+// SendToAccountOwner mengirim pesan ke Telegram user pemilik akun WhatsApp
+func SendToAccountOwner(accountID int, message string) {
+	account := GetAccountManager().GetAccount(accountID)
+	SendToTelegramUser(GetAccountTelegramID(account), message)
+}
+
+// SendToAccountOwnerWithKeyboard mengirim pesan beserta tombol inline ke pemilik akun WhatsApp (plain text)
+func SendToAccountOwnerWithKeyboard(accountID int, message string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	telegramID := GetAccountTelegramID(GetAccountManager().GetAccount(accountID))
+	if telegramID == 0 && TelegramConfig != nil {
+		telegramID = TelegramConfig.UserAllowedID
+	}
+	if TgBot != nil && telegramID != 0 {
+		msg := tgbotapi.NewMessage(telegramID, message)
+		msg.ReplyMarkup = keyboard
+		TgBot.Send(msg)
+	}
+	// Tetap print ke console untuk logging
+	fmt.Println(message)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"go.mau.fi/whatsmeow"
+)
+
+// File ini mengumpulkan pemakaian API internal whatsmeow (DangerousInternals) yang belum punya wrapper publik.
+// Nilai di bawah disalin dari whatsmeow yang dipin di go.mod; periksa ulang setiap kali whatsmeow di-update.
+const (
+	// Versi whatsmeow tempat nilai internal di file ini diambil
+	whatsmeowInternalsVersion = "v0.0.0-20251116104239-3aca43070cd4"
+	// Sama dengan mutationUpdateNewsletter di newsletter.go whatsmeow (tidak diekspor)
+	// whatsmeow sendiri mengubahnya ke ID versi desktop jika perlu (convertQueryID)
+	newsletterUpdateMutationID = "7150902998257522"
+)
+
+// updateNewsletterMetadata mengubah metadata saluran (name / description / picture) lewat mutation MEX internal whatsmeow
+func updateNewsletterMetadata(ctx context.Context, client *whatsmeow.Client, newsletterJID string, updates map[string]any) error {
+	_, err := client.DangerousInternals().SendMexIQ(ctx, newsletterUpdateMutationID, map[string]any{
+		"newsletter_id": newsletterJID,
+		"updates":       updates,
+	})
+	if err != nil {
+		return fmt.Errorf("mutation internal update saluran (ID %s, whatsmeow %s) gagal, ID mungkin berubah setelah update whatsmeow: %w",
+			newsletterUpdateMutationID, whatsmeowInternalsVersion, err)
+	}
+	return nil
+}
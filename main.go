@@ -25,6 +25,12 @@ func main() {
 	logger.Info("Version: 1.0.0")
 	logger.Info("Initializing...")
 
+	// Pastikan hanya satu instance bot yang memakai direktori data ini
+	// (misalnya reload air yang tumpang tindih dengan binary lama, atau go run dijalankan dua kali)
+	if err := utils.AcquireInstanceLock(); err != nil {
+		logger.Fatal("Instance bot lain sedang berjalan di direktori ini: %v", err)
+	}
+
 	// Migrate existing databases to user folders (if any)
 	if err := utils.MigrateDatabaseToUserFolder(); err != nil {
 		logger.Warn("Failed to migrate databases to user folders: %v", err)
@@ -43,6 +49,9 @@ func main() {
 	// Set event handler
 	startupManager.SetEventHandler(core.EventHandler)
 
+	// Set event handler per akun: setiap client multi-account mendapat handler yang membawa account ID-nya
+	handlers.GetAccountManager().SetEventHandlerFactory(core.NewAccountEventHandler)
+
 	// Initialize application
 	if err := startupManager.Initialize(); err != nil {
 		logger.Fatal("Failed to initialize application: %v", err)
@@ -88,7 +97,7 @@ func main() {
 		startupManager.GetTelegramBot(),
 	)
 
-	if err := shutdownManager.ShutdownWithTimeout(10 * time.Second); err != nil {
+	if err := shutdownManager.ShutdownWithTimeout(core.GracefulShutdownTimeout); err != nil {
 		logger.Error("Error during shutdown: %v", err)
 	}
 
@@ -121,6 +130,13 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 				continue
 			}
 
+			// Tolak pekerjaan baru saat bot sedang shutdown
+			if handlers.IsShuttingDown() {
+				callback := tgbotapi.NewCallback(update.CallbackQuery.ID, "⏳ Bot sedang dimatikan, coba lagi setelah bot aktif kembali.")
+				telegramBot.Request(callback)
+				continue
+			}
+
 			fmt.Printf("[DEBUG] Access granted, calling HandleCallbackQuery with data=%s\n", update.CallbackQuery.Data)
 			handlers.HandleCallbackQuery(update.CallbackQuery, waClient, telegramBot)
 			continue
@@ -138,6 +154,13 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Tolak pekerjaan baru saat bot sedang shutdown
+		if handlers.IsShuttingDown() {
+			msg := tgbotapi.NewMessage(update.Message.Chat.ID, "⏳ Bot sedang dimatikan (maintenance/restart). Silakan coba lagi setelah bot aktif kembali.")
+			telegramBot.Send(msg)
+			continue
+		}
+
 		// Handle commands
 		if update.Message.IsCommand() {
 			handlers.HandleTelegramCommand(update.Message, waClient, telegramBot)
@@ -180,6 +203,13 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Handle group selection from list (for rename feature)
+		if handlers.IsWaitingForRenameSelection(chatID) {
+			selection := strings.TrimSpace(update.Message.Text)
+			handlers.ProcessSelectedGroupsForRename(selection, chatID, telegramBot)
+			continue
+		}
+
 		// Handle group selection from list (for join approval feature)
 		if handlers.IsWaitingForJoinApprovalSelection(chatID) {
 			selection := strings.TrimSpace(update.Message.Text)
@@ -237,6 +267,49 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Handle input menu saluran (buat saluran, ubah nama/deskripsi, kirim foto saluran)
+		if handlers.IsWaitingForChannelInput(chatID) {
+			if len(update.Message.Photo) > 0 {
+				photo := update.Message.Photo[len(update.Message.Photo)-1]
+				go handlers.HandleChannelPhotoUpload(photo.FileID, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			} else if update.Message.Document != nil && strings.HasPrefix(update.Message.Document.MimeType, "image/") {
+				go handlers.HandleChannelPhotoUpload(update.Message.Document.FileID, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			} else if update.Message.Text != "" {
+				handlers.HandleChannelTextInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			}
+			continue
+		}
+
+		// Handle provisioning grup dari file spesifikasi (.csv/.json/.zip)
+		if update.Message.Document != nil && handlers.IsProvisionUpload(chatID, update.Message.Document.FileName) {
+			handlers.HandleProvisionFile(update.Message.Document.FileID, update.Message.Document.FileName, chatID, telegramBot, telegramBot.Token)
+			continue
+		}
+		if handlers.IsWaitingForProvisionInput(chatID) {
+			if handlers.GetProvisionInputType(chatID) == "delay" {
+				handlers.HandleDelayInputForProvision(update.Message.Text, chatID, telegramBot)
+			} else {
+				msg := tgbotapi.NewMessage(chatID, "📤 Kirim file spesifikasi dengan format .csv, .json, atau .zip.")
+				telegramBot.Send(msg)
+			}
+			continue
+		}
+
+		// Handle foto per grup dari ZIP (menu ZIP atau langkah kirim foto)
+		if update.Message.Document != nil && handlers.IsZipPhotoUpload(chatID, update.Message.Document.FileName) {
+			handlers.HandleZipPhotoUpload(update.Message.Document.FileID, chatID, telegramBot, telegramBot.Token)
+			continue
+		}
+		if handlers.IsWaitingForZipPhotoInput(chatID) {
+			if handlers.GetZipPhotoInputType(chatID) == "delay" {
+				handlers.HandleDelayInputForZipPhoto(update.Message.Text, chatID, telegramBot)
+			} else {
+				msg := tgbotapi.NewMessage(chatID, "📦 Kirim file dengan format .zip berisi gambar per grup.")
+				telegramBot.Send(msg)
+			}
+			continue
+		}
+
 		// Handle change photo input (text part)
 		if handlers.IsWaitingForPhotoInput(chatID) {
 			inputType := handlers.GetPhotoInputType(chatID)
@@ -319,6 +392,27 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Handle import CSV: file .csv dari menu import atau dari handler upload daftar grup
+		if update.Message.Document != nil && handlers.IsCSVImportUpload(chatID, update.Message.Document.FileName) {
+			handlers.HandleCSVImportFile(update.Message.Document.FileID, chatID, telegramBot, telegramBot.Token)
+			continue
+		}
+		if handlers.IsWaitingForCSVImportInput(chatID) {
+			if handlers.GetCSVImportInputType(chatID) == "delay" {
+				handlers.HandleDelayInputForCSVImport(update.Message.Text, chatID, telegramBot)
+			} else {
+				msg := tgbotapi.NewMessage(chatID, "📤 Kirim file dengan format .csv untuk import.")
+				telegramBot.Send(msg)
+			}
+			continue
+		}
+
+		// Handle input aturan auto-approve permintaan masuk grup
+		if handlers.IsWaitingForMemberRequestRules(chatID) && update.Message.Text != "" {
+			handlers.HandleMemberRequestRulesInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
 		// Handle change description input
 		if handlers.IsWaitingForDescriptionInput(chatID) {
 			inputType := handlers.GetDescriptionInputType(chatID)
@@ -393,6 +487,33 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Handle rename group input
+		if handlers.IsWaitingForRenameInput(chatID) {
+			inputType := handlers.GetRenameInputType(chatID)
+
+			// Handle file upload (.txt) for group names
+			if inputType == "group_name" && update.Message.Document != nil {
+				fileName := update.Message.Document.FileName
+				if strings.HasSuffix(strings.ToLower(fileName), ".txt") {
+					handlers.HandleFileInputForRename(update.Message.Document.FileID, chatID, telegramBot, telegramBot.Token)
+					continue
+				}
+			}
+
+			// Pola regex/template dipakai apa adanya (tanpa TrimSpace per baris)
+			input := update.Message.Text
+
+			if inputType == "group_name" {
+				handlers.HandleGroupNameInputForRename(input, chatID, telegramBot)
+			} else if inputType == "delay" {
+				handlers.HandleDelayInputForRename(input, chatID, telegramBot)
+			} else if inputType == "pattern" {
+				handlers.HandlePatternInputForRename(input, chatID, telegramBot)
+			}
+			// Pilihan mode & konfirmasi ditangani via button callbacks
+			continue
+		}
+
 		// Handle change join approval input
 		if handlers.IsWaitingForJoinApprovalInput(chatID) {
 			inputType := handlers.GetJoinApprovalInputType(chatID)
@@ -568,6 +689,42 @@ func startTelegramBotHandler(startupManager *core.StartupManager) {
 			continue
 		}
 
+		// Handle input komunitas (buat komunitas / tautkan grup)
+		if handlers.IsWaitingForCommunityInput(chatID) && update.Message.Text != "" {
+			handlers.HandleCommunityInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
+		// Handle input aturan pantau kata kunci
+		if handlers.IsWaitingForKeywordWatchInput(chatID) && update.Message.Text != "" {
+			handlers.HandleKeywordWatchInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
+		// Handle input nomor blocklist
+		if handlers.IsWaitingForBlocklistInput(chatID) && update.Message.Text != "" {
+			handlers.HandleBlocklistInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
+		// Handle input aturan moderasi
+		if handlers.IsWaitingForModerationInput(chatID) && update.Message.Text != "" {
+			handlers.HandleModerationInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
+		// Handle input template pesan sambutan
+		if handlers.IsWaitingForWelcomeInput(chatID) && update.Message.Text != "" {
+			handlers.HandleWelcomeInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
+		// Handle input roster admin deklaratif
+		if handlers.IsWaitingForAdminRosterInput(chatID) && update.Message.Text != "" {
+			handlers.HandleAdminRosterInput(update.Message.Text, chatID, handlers.GetWhatsAppClient(), telegramBot)
+			continue
+		}
+
 		// Handle admin/unadmin input
 		if handlers.IsWaitingForAdminInput(chatID) {
 			inputType := handlers.GetAdminInputType(chatID)
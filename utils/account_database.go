@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Connection pool per akun (key: path bot_data database)
+// Dipakai oleh event handler per akun agar tidak tergantung dbConfig global (akun yang sedang aktif)
+var (
+	accountDBPools      = make(map[string]*sql.DB)
+	accountDBPoolsMutex sync.Mutex
+)
+
+// GetBotDBPoolForPath mendapatkan connection pool untuk bot_data database milik akun tertentu
+// Berbeda dengan GetBotDBPool, pool ini tidak ikut berganti saat user switch akun
+func GetBotDBPoolForPath(dbPath string) (*sql.DB, error) {
+	if dbPath == "" {
+		return nil, fmt.Errorf("path database akun kosong")
+	}
+
+	accountDBPoolsMutex.Lock()
+	defer accountDBPoolsMutex.Unlock()
+
+	if db, exists := accountDBPools[dbPath]; exists {
+		return db, nil
+	}
+
+	// Pastikan tabel groups dan messages ada sebelum pool dipakai
+	if err := setupBotDBAt(dbPath); err != nil {
+		GetLogger().Warn("GetBotDBPoolForPath: Failed to setup bot database for path '%s': %v", dbPath, err)
+		// Continue anyway, mungkin sudah ada
+	}
+
+	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_cache=shared")
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka database akun: %w", err)
+	}
+	db.SetMaxOpenConns(5)
+	db.SetMaxIdleConns(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("gagal ping database akun: %w", err)
+	}
+
+	accountDBPools[dbPath] = db
+	GetLogger().Debug("GetBotDBPoolForPath: Pool created for path: %s", dbPath)
+	return db, nil
+}
+
+// accountBotDB mendapatkan database akun dbPath, atau database akun yang sedang aktif jika dbPath kosong
+// Dipakai fungsi ...At yang bisa dipanggil dari event akun mana pun
+func accountBotDB(dbPath string) (*sql.DB, error) {
+	if dbPath == "" {
+		return GetBotDBPool()
+	}
+	return GetBotDBPoolForPath(dbPath)
+}
+
+// CloseBotDBPoolForPath menutup connection pool milik satu akun (dipakai saat akun dihapus)
+func CloseBotDBPoolForPath(dbPath string) {
+	accountDBPoolsMutex.Lock()
+	defer accountDBPoolsMutex.Unlock()
+
+	if db, exists := accountDBPools[dbPath]; exists {
+		db.Close()
+		delete(accountDBPools, dbPath)
+	}
+}
+
+// CloseAllAccountDBPools menutup semua connection pool per akun
+func CloseAllAccountDBPools() {
+	accountDBPoolsMutex.Lock()
+	defer accountDBPoolsMutex.Unlock()
+
+	for path, db := range accountDBPools {
+		db.Close()
+		delete(accountDBPools, path)
+	}
+}
+
+// SaveGroupToAccountDB menyimpan grup ke database milik akun tertentu
+func SaveGroupToAccountDB(dbPath, groupJID, groupName string) error {
+	db, err := GetBotDBPoolForPath(dbPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT OR REPLACE INTO groups (group_jid, group_name, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+	`, groupJID, groupName)
+
+	return err
+}
+
+// BatchSaveGroupsToAccountDB menyimpan multiple grup sekaligus ke database milik akun tertentu
+func BatchSaveGroupsToAccountDB(dbPath string, groups map[string]string) error {
+	db, err := GetBotDBPoolForPath(dbPath)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO groups (group_jid, group_name, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for jid, name := range groups {
+		if _, err := stmt.Exec(jid, name); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetGroupNameAt mengambil nama grup dari database dbPath (kosong = akun aktif). Return "" jika belum tersimpan
+func GetGroupNameAt(dbPath, groupJID string) (string, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return "", err
+	}
+
+	var name sql.NullString
+	err = db.QueryRow("SELECT group_name FROM groups WHERE group_jid = ?", groupJID).Scan(&name)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return name.String, err
+}
@@ -42,7 +42,13 @@ func LogActivityError(action, description string, chatID int64, err error) error
 // LogActivityWithMetadata mencatat aktivitas dengan metadata tambahan
 func LogActivityWithMetadata(action, description string, chatID int64, metadata map[string]interface{}, success bool) error {
 	// CRITICAL FIX: Gunakan GetBotDBPool() untuk memastikan menggunakan database yang benar per user
-	db, err := GetBotDBPool()
+	return LogActivityWithMetadataAt("", action, description, chatID, metadata, success)
+}
+
+// LogActivityWithMetadataAt mencatat aktivitas ke database dbPath (kosong = akun aktif)
+// Dipakai handler event yang berjalan untuk akun selain akun aktif
+func LogActivityWithMetadataAt(dbPath, action, description string, chatID int64, metadata map[string]interface{}, success bool) error {
+	db, err := accountBotDB(dbPath)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// AdminRoster adalah daftar admin yang diinginkan untuk satu grup atau satu koleksi grup (tag)
+type AdminRoster struct {
+	ID        int64
+	AccountID string
+	GroupTarget
+	Phones         []string
+	DemoteUnlisted bool // Jika aktif, admin yang tidak ada di roster diturunkan
+	UpdatedAt      time.Time
+}
+
+// setupAdminRosterTable membuat tabel roster admin deklaratif
+func setupAdminRosterTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS admin_rosters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			target TEXT NOT NULL,
+			target_name TEXT DEFAULT '',
+			phones TEXT DEFAULT '',
+			demote_unlisted INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, target)
+		)
+	`)
+	return err
+}
+
+// SaveAdminRoster menyimpan roster admin (target yang sama milik akun yang sama akan ditimpa)
+func SaveAdminRoster(roster *AdminRoster) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO admin_rosters (account_id, target, target_name, phones, demote_unlisted, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id, target) DO UPDATE SET
+			target_name = excluded.target_name,
+			phones = excluded.phones,
+			demote_unlisted = excluded.demote_unlisted,
+			updated_at = CURRENT_TIMESTAMP
+	`, roster.AccountID, roster.Target, roster.TargetName, strings.Join(roster.Phones, "\n"), roster.DemoteUnlisted)
+	return err
+}
+
+// SetAdminRosterDemote mengubah opsi turunkan admin yang tidak terdaftar (hanya roster milik akun)
+func SetAdminRosterDemote(accountID string, id int64, demote bool) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE admin_rosters SET demote_unlisted = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND account_id = ?", demote, id, accountID)
+	return err
+}
+
+// DeleteAdminRoster menghapus roster admin milik akun
+func DeleteAdminRoster(accountID string, id int64) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM admin_rosters WHERE id = ? AND account_id = ?", id, accountID)
+	return err
+}
+
+// GetAdminRoster mengambil satu roster milik akun berdasarkan ID (nil jika tidak ada)
+func GetAdminRoster(accountID string, id int64) (*AdminRoster, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, target, target_name, phones, demote_unlisted, updated_at
+		FROM admin_rosters WHERE id = ? AND account_id = ?
+	`, id, accountID)
+	if err != nil {
+		return nil, err
+	}
+	rosters, err := scanAdminRosters(rows)
+	if err != nil || len(rosters) == 0 {
+		return nil, err
+	}
+	return &rosters[0], nil
+}
+
+// GetAdminRosters mengambil semua roster admin milik akun
+func GetAdminRosters(accountID string) ([]AdminRoster, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, target, target_name, phones, demote_unlisted, updated_at
+		FROM admin_rosters WHERE account_id = ?
+		ORDER BY target_name COLLATE NOCASE
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return scanAdminRosters(rows)
+}
+
+// scanAdminRosters membaca hasil query admin_rosters lalu menutup rows
+func scanAdminRosters(rows *sql.Rows) ([]AdminRoster, error) {
+	defer rows.Close()
+
+	var rosters []AdminRoster
+	for rows.Next() {
+		var roster AdminRoster
+		var targetName, phones sql.NullString
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&roster.ID, &roster.AccountID, &roster.Target, &targetName, &phones, &roster.DemoteUnlisted, &updatedAt); err != nil {
+			continue
+		}
+		roster.TargetName = targetName.String
+		if phones.String != "" {
+			roster.Phones = strings.Split(phones.String, "\n")
+		}
+		if updatedAt.Valid {
+			roster.UpdatedAt = updatedAt.Time
+		}
+		rosters = append(rosters, roster)
+	}
+	return rosters, rows.Err()
+}
+
+// GetGroupsByTag mengambil grup (JID -> nama) yang memiliki tag key=value (case-insensitive)
+func GetGroupsByTag(key, value string) (map[string]string, error) {
+	groups := make(map[string]string)
+
+	db, err := GetBotDBPool()
+	if err != nil {
+		return groups, err
+	}
+
+	rows, err := db.Query(`
+		SELECT t.group_jid, COALESCE(g.group_name, t.group_jid)
+		FROM group_tags t LEFT JOIN groups g ON g.group_jid = t.group_jid
+		WHERE t.tag_key = ? AND LOWER(t.tag_value) = LOWER(?)
+	`, strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value))
+	if err != nil {
+		return groups, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jid, name string
+		if err := rows.Scan(&jid, &name); err != nil {
+			continue
+		}
+		groups[jid] = name
+	}
+	return groups, rows.Err()
+}
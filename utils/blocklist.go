@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// BlocklistEntry adalah nomor yang diblokir dari semua grup yang dikelola akun
+type BlocklistEntry struct {
+	Phone     string // Format internasional tanpa + (628...)
+	Note      string
+	CreatedAt time.Time
+}
+
+// setupBlocklistTable membuat tabel blocklist nomor peserta per akun
+func setupBlocklistTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS participant_blocklist (
+			account_id TEXT NOT NULL,
+			phone TEXT NOT NULL,
+			note TEXT DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, phone)
+		)
+	`)
+	return err
+}
+
+// AddBlocklistEntries menambahkan nomor ke blocklist akun aktif (nomor yang sudah ada diperbarui catatannya)
+func AddBlocklistEntries(accountID string, entries []BlocklistEntry) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO participant_blocklist (account_id, phone, note, created_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id, phone) DO UPDATE SET note = excluded.note
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(accountID, entry.Phone, entry.Note); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveBlocklistPhones menghapus nomor dari blocklist akun aktif. Return jumlah yang terhapus
+func RemoveBlocklistPhones(accountID string, phones []string) (int, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, phone := range phones {
+		result, err := db.Exec("DELETE FROM participant_blocklist WHERE account_id = ? AND phone = ?", accountID, phone)
+		if err != nil {
+			return removed, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			removed += int(n)
+		}
+	}
+	return removed, nil
+}
+
+// GetBlocklistAt mengambil blocklist akun dari database dbPath (kosong = akun aktif), terbaru lebih dulu
+func GetBlocklistAt(dbPath, accountID string) ([]BlocklistEntry, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT phone, note, created_at FROM participant_blocklist
+		WHERE account_id = ? ORDER BY created_at DESC, phone
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []BlocklistEntry
+	for rows.Next() {
+		var e BlocklistEntry
+		var note sql.NullString
+		if err := rows.Scan(&e.Phone, &note, &e.CreatedAt); err != nil {
+			continue
+		}
+		e.Note = note.String
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetBlocklistSetAt mengambil blocklist akun sebagai set nomor untuk pengecekan cepat
+func GetBlocklistSetAt(dbPath, accountID string) (map[string]bool, error) {
+	entries, err := GetBlocklistAt(dbPath, accountID)
+	if err != nil {
+		return nil, err
+	}
+	phones := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		phones[entry.Phone] = true
+	}
+	return phones, nil
+}
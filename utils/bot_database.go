@@ -180,7 +180,11 @@ func ClearAppState() error {
 
 // SetupBotDB menyiapkan database untuk bot (menggunakan nama dinamis)
 func SetupBotDB() error {
-	dbName := GetBotDataDBPath()
+	return setupBotDBAt(GetBotDataDBPath())
+}
+
+// setupBotDBAt membuat tabel-tabel bot_data pada path database tertentu
+func setupBotDBAt(dbName string) error {
 	db, err := sql.Open("sqlite3", dbName)
 	if err != nil {
 		return err
@@ -211,6 +215,71 @@ func SetupBotDB() error {
 		return err
 	}
 
+	// Create tabel cache metadata grup & tag custom (dipakai template per grup)
+	if err := setupGroupMetadataTables(db); err != nil {
+		return err
+	}
+
+	// Create tabel registry link undangan grup (per akun)
+	if err := setupInviteLinkRegistryTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel permintaan join grup yang menunggu persetujuan admin
+	if err := setupJoinRequestTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel aturan auto-approve permintaan masuk grup yang kita kelola
+	if err := setupMemberRequestRulesTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel roster admin deklaratif per grup / koleksi grup (tag)
+	if err := setupAdminRosterTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel cache status akun di tiap grup (member/admin) untuk preflight operasi admin
+	if err := setupGroupCapabilityTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel snapshot grup yang ditinggalkan (link + metadata) untuk fitur gabung kembali
+	if err := setupLeftGroupsTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel keanggotaan komunitas WhatsApp per grup (komunitas, subgrup, grup pengumuman)
+	if err := setupGroupCommunityTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel cache saluran WhatsApp (newsletter) yang dimiliki / diikuti akun
+	if err := setupChannelsTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel template sambutan anggota baru dan saklar on/off per akun
+	if err := setupWelcomeTables(db); err != nil {
+		return err
+	}
+
+	// Create tabel aturan moderasi, hitungan peringatan dan riwayat tindakan moderasi
+	if err := setupModerationTables(db); err != nil {
+		return err
+	}
+
+	// Create tabel blocklist nomor peserta per akun
+	if err := setupBlocklistTable(db); err != nil {
+		return err
+	}
+
+	// Create tabel aturan pantau kata kunci (alert ke Telegram)
+	if err := setupKeywordWatchTable(db); err != nil {
+		return err
+	}
+
 	// Create whatsapp_accounts table untuk multi-account
 	// Juga buat di database master (bot_data.db) untuk memastikan konsistensi
 	masterDBPath := "bot_data.db"
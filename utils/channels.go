@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Channel menyimpan saluran WhatsApp (newsletter) yang dimiliki / diikuti akun
+type Channel struct {
+	JID             string
+	Name            string
+	Description     string
+	InviteCode      string
+	Role            string // owner, admin, subscriber, guest
+	SubscriberCount int
+	Verified        bool
+	UpdatedAt       time.Time
+}
+
+// setupChannelsTable membuat tabel cache saluran (newsletter) akun
+func setupChannelsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS channels (
+			channel_jid TEXT PRIMARY KEY,
+			name TEXT DEFAULT '',
+			description TEXT DEFAULT '',
+			invite_code TEXT DEFAULT '',
+			role TEXT DEFAULT '',
+			subscriber_count INTEGER DEFAULT 0,
+			verified INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// ReplaceChannels mengganti seluruh cache saluran dengan hasil GetSubscribedNewsletters terbaru
+func ReplaceChannels(channels []Channel) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM channels"); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO channels (channel_jid, name, description, invite_code, role, subscriber_count, verified, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range channels {
+		if _, err := stmt.Exec(c.JID, c.Name, c.Description, c.InviteCode, c.Role, c.SubscriberCount, c.Verified); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveChannel menyimpan satu saluran (setelah dibuat / diubah)
+func SaveChannel(c Channel) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT OR REPLACE INTO channels (channel_jid, name, description, invite_code, role, subscriber_count, verified, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, c.JID, c.Name, c.Description, c.InviteCode, c.Role, c.SubscriberCount, c.Verified)
+	return err
+}
+
+// GetChannels mengambil semua saluran dari cache (saluran milik sendiri lebih dulu, lalu urut nama)
+func GetChannels() ([]Channel, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT channel_jid, name, description, invite_code, role, subscriber_count, verified, updated_at
+		FROM channels
+		ORDER BY CASE role WHEN 'owner' THEN 0 WHEN 'admin' THEN 1 ELSE 2 END, LOWER(name)
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var channels []Channel
+	for rows.Next() {
+		var c Channel
+		if err := rows.Scan(&c.JID, &c.Name, &c.Description, &c.InviteCode, &c.Role, &c.SubscriberCount, &c.Verified, &c.UpdatedAt); err != nil {
+			continue
+		}
+		channels = append(channels, c)
+	}
+	return channels, rows.Err()
+}
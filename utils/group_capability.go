@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GroupCapability adalah status akun di satu grup hasil cache daftar peserta (GetJoinedGroups)
+type GroupCapability struct {
+	GroupJID     string
+	IsAdmin      bool // Akun ini admin / superadmin grup
+	IsAnnounce   bool // Hanya admin yang bisa mengirim pesan
+	IsLocked     bool // Hanya admin yang bisa mengubah info grup
+	MemberAddAll bool // Semua anggota boleh menambah anggota
+	UpdatedAt    time.Time
+}
+
+// setupGroupCapabilityTable membuat tabel cache status akun per grup
+func setupGroupCapabilityTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_capabilities (
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			is_admin INTEGER DEFAULT 0,
+			is_announce INTEGER DEFAULT 0,
+			is_locked INTEGER DEFAULT 0,
+			member_add_all INTEGER DEFAULT 0,
+			updated_at DATETIME NOT NULL,
+			PRIMARY KEY (account_id, group_jid)
+		)
+	`)
+	return err
+}
+
+// ReplaceGroupCapabilities mengganti seluruh cache status akun dengan hasil refresh terbaru
+// Grup yang tidak ada di daftar berarti akun sudah bukan member
+func ReplaceGroupCapabilities(accountID string, caps []GroupCapability) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM group_capabilities WHERE account_id = ?", accountID); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO group_capabilities (account_id, group_jid, is_admin, is_announce, is_locked, member_add_all, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	for _, c := range caps {
+		if _, err := stmt.Exec(accountID, c.GroupJID, c.IsAdmin, c.IsAnnounce, c.IsLocked, c.MemberAddAll, now); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetGroupCapabilities mengambil cache status akun per grup (key: JID grup) beserta waktu refresh terakhir
+func GetGroupCapabilities(accountID string) (map[string]GroupCapability, time.Time, error) {
+	caps := make(map[string]GroupCapability)
+	var refreshedAt time.Time
+
+	db, err := GetBotDBPool()
+	if err != nil {
+		return caps, refreshedAt, err
+	}
+
+	rows, err := db.Query(`
+		SELECT group_jid, is_admin, is_announce, is_locked, member_add_all, updated_at
+		FROM group_capabilities WHERE account_id = ?
+	`, accountID)
+	if err != nil {
+		return caps, refreshedAt, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c GroupCapability
+		if err := rows.Scan(&c.GroupJID, &c.IsAdmin, &c.IsAnnounce, &c.IsLocked, &c.MemberAddAll, &c.UpdatedAt); err != nil {
+			continue
+		}
+		caps[c.GroupJID] = c
+		if c.UpdatedAt.After(refreshedAt) {
+			refreshedAt = c.UpdatedAt
+		}
+	}
+	return caps, refreshedAt, rows.Err()
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GroupCommunity menyimpan keanggotaan komunitas WhatsApp untuk satu grup (pelengkap tabel groups)
+type GroupCommunity struct {
+	GroupJID       string
+	IsCommunity    bool   // Grup induk komunitas (parent group)
+	ParentJID      string // JID komunitas untuk subgrup / grup pengumuman ("" jika tidak tertaut)
+	IsAnnouncement bool   // Grup pengumuman bawaan komunitas (default subgroup)
+	UpdatedAt      time.Time
+}
+
+// setupGroupCommunityTable membuat tabel keanggotaan komunitas per grup
+func setupGroupCommunityTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_communities (
+			group_jid TEXT PRIMARY KEY,
+			is_community INTEGER DEFAULT 0,
+			parent_jid TEXT DEFAULT '',
+			is_announcement INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_group_communities_parent ON group_communities(parent_jid)`)
+	return err
+}
+
+// ReplaceGroupCommunities mengganti seluruh data komunitas dengan hasil refresh GetJoinedGroups
+// Hanya grup yang merupakan komunitas atau tertaut ke komunitas yang disimpan
+func ReplaceGroupCommunities(communities []GroupCommunity) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM group_communities"); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO group_communities (group_jid, is_community, parent_jid, is_announcement, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, c := range communities {
+		if _, err := stmt.Exec(c.GroupJID, c.IsCommunity, c.ParentJID, c.IsAnnouncement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SaveGroupCommunity menyimpan data komunitas satu grup (setelah buat komunitas / tautkan grup)
+func SaveGroupCommunity(c GroupCommunity) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT OR REPLACE INTO group_communities (group_jid, is_community, parent_jid, is_announcement, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+	`, c.GroupJID, c.IsCommunity, c.ParentJID, c.IsAnnouncement)
+	return err
+}
+
+// DeleteGroupCommunity menghapus data komunitas satu grup (setelah grup dilepas dari komunitas)
+func DeleteGroupCommunity(groupJID string) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("DELETE FROM group_communities WHERE group_jid = ?", groupJID)
+	return err
+}
+
+// GetGroupCommunities mengambil semua data komunitas (key: JID grup)
+func GetGroupCommunities() (map[string]GroupCommunity, error) {
+	communities := make(map[string]GroupCommunity)
+
+	db, err := GetBotDBPool()
+	if err != nil {
+		return communities, err
+	}
+
+	rows, err := db.Query(`
+		SELECT group_jid, is_community, parent_jid, is_announcement, updated_at
+		FROM group_communities
+	`)
+	if err != nil {
+		return communities, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c GroupCommunity
+		if err := rows.Scan(&c.GroupJID, &c.IsCommunity, &c.ParentJID, &c.IsAnnouncement, &c.UpdatedAt); err != nil {
+			continue
+		}
+		communities[c.GroupJID] = c
+	}
+	return communities, rows.Err()
+}
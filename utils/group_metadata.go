@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// GroupMetadata menyimpan metadata grup yang di-cache dari WhatsApp (dipakai template per grup)
+type GroupMetadata struct {
+	JID          string
+	MemberCount  int
+	GroupCreated time.Time
+	Topic        string
+	InviteLink   string
+	UpdatedAt    time.Time
+}
+
+// setupGroupMetadataTables membuat tabel cache metadata grup dan tag custom per grup
+func setupGroupMetadataTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_metadata (
+			group_jid TEXT PRIMARY KEY,
+			member_count INTEGER DEFAULT 0,
+			group_created_at DATETIME,
+			topic TEXT DEFAULT '',
+			invite_link TEXT DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_tags (
+			group_jid TEXT NOT NULL,
+			tag_key TEXT NOT NULL,
+			tag_value TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_jid, tag_key)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_invite_link_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_jid TEXT NOT NULL,
+			old_link TEXT DEFAULT '',
+			new_link TEXT NOT NULL,
+			reset_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`CREATE INDEX IF NOT EXISTS idx_invite_link_history_group ON group_invite_link_history(group_jid, reset_at)`)
+	return err
+}
+
+// SaveGroupMetadata menyimpan metadata grup dari GetGroupInfo (invite link lama tetap dipertahankan)
+func SaveGroupMetadata(meta *GroupMetadata) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	var created interface{}
+	if !meta.GroupCreated.IsZero() {
+		created = meta.GroupCreated
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO group_metadata (group_jid, member_count, group_created_at, topic, updated_at)
+		VALUES (?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(group_jid) DO UPDATE SET
+			member_count = excluded.member_count,
+			group_created_at = COALESCE(excluded.group_created_at, group_metadata.group_created_at),
+			topic = excluded.topic,
+			updated_at = CURRENT_TIMESTAMP
+	`, meta.JID, meta.MemberCount, created, meta.Topic)
+	return err
+}
+
+// SaveGroupInviteLink menyimpan link undangan grup ke cache metadata
+func SaveGroupInviteLink(groupJID, inviteLink string) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO group_metadata (group_jid, invite_link, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(group_jid) DO UPDATE SET
+			invite_link = excluded.invite_link,
+			updated_at = CURRENT_TIMESTAMP
+	`, groupJID, inviteLink)
+	return err
+}
+
+// SaveGroupInviteLinkReset mencatat riwayat reset link undangan (link lama & baru) lalu memperbarui cache link grup
+func SaveGroupInviteLinkReset(groupJID, oldLink, newLink string, resetAt time.Time) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO group_invite_link_history (group_jid, old_link, new_link, reset_at)
+		VALUES (?, ?, ?, ?)
+	`, groupJID, oldLink, newLink, resetAt)
+	if err != nil {
+		return err
+	}
+
+	return SaveGroupInviteLink(groupJID, newLink)
+}
+
+// GetGroupMetadata mengambil metadata grup dari cache (nil jika belum pernah di-cache)
+func GetGroupMetadata(groupJID string) (*GroupMetadata, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &GroupMetadata{JID: groupJID}
+	var created sql.NullTime
+	var topic, inviteLink sql.NullString
+	err = db.QueryRow(`
+		SELECT member_count, group_created_at, topic, invite_link, updated_at
+		FROM group_metadata WHERE group_jid = ?
+	`, groupJID).Scan(&meta.MemberCount, &created, &topic, &inviteLink, &meta.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if created.Valid {
+		meta.GroupCreated = created.Time
+	}
+	meta.Topic = topic.String
+	meta.InviteLink = inviteLink.String
+	return meta, nil
+}
+
+// SetGroupTag menyimpan tag custom untuk grup (value kosong = hapus tag)
+func SetGroupTag(groupJID, key, value string) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	key = strings.ToLower(strings.TrimSpace(key))
+	if strings.TrimSpace(value) == "" {
+		_, err = db.Exec("DELETE FROM group_tags WHERE group_jid = ? AND tag_key = ?", groupJID, key)
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT OR REPLACE INTO group_tags (group_jid, tag_key, tag_value, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+	`, groupJID, key, strings.TrimSpace(value))
+	return err
+}
+
+// GetGroupTags mengambil semua tag custom milik grup
+func GetGroupTags(groupJID string) (map[string]string, error) {
+	return GetGroupTagsAt("", groupJID)
+}
+
+// GetGroupTagsAt mengambil tag custom grup dari database dbPath (kosong = akun aktif)
+func GetGroupTagsAt(dbPath, groupJID string) (map[string]string, error) {
+	tags := make(map[string]string)
+
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return tags, err
+	}
+
+	rows, err := db.Query("SELECT tag_key, tag_value FROM group_tags WHERE group_jid = ?", groupJID)
+	if err != nil {
+		return tags, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			continue
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
@@ -0,0 +1,59 @@
+package utils
+
+import "strings"
+
+// Prefix target untuk koleksi grup berdasarkan tag (tag:key=value)
+const GroupTagPrefix = "tag:"
+
+// GroupTarget adalah target aturan per grup: satu grup (JID) atau satu koleksi grup (tag)
+// Di-embed oleh roster admin, template sambutan, aturan moderasi dan aturan pantau kata kunci
+type GroupTarget struct {
+	Target     string // JID grup, atau "tag:key=value" untuk semua grup dengan tag tersebut
+	TargetName string // Nama grup / label tag untuk ditampilkan
+}
+
+// IsTagTarget mengecek apakah target adalah koleksi grup (tag)
+func (t *GroupTarget) IsTagTarget() bool {
+	return strings.HasPrefix(t.Target, GroupTagPrefix)
+}
+
+// TagSelector mengembalikan key dan value tag dari target koleksi
+func (t *GroupTarget) TagSelector() (string, string, bool) {
+	selector, ok := strings.CutPrefix(t.Target, GroupTagPrefix)
+	if !ok {
+		return "", "", false
+	}
+	key, value, ok := strings.Cut(selector, "=")
+	if !ok {
+		return "", "", false
+	}
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+// MatchesTags mengecek apakah target koleksi cocok dengan tag grup
+func (t *GroupTarget) MatchesTags(tags map[string]string) bool {
+	key, value, ok := t.TagSelector()
+	return ok && strings.EqualFold(tags[key], value)
+}
+
+// findGroupTargetAt mencari item yang berlaku untuk grup: target grup persis lebih dulu,
+// lalu koleksi (tag) yang cocok dengan tag grup di database dbPath. Return nil jika tidak ada
+func findGroupTargetAt[T any](dbPath, groupJID string, items []T, target func(*T) *GroupTarget) (*T, error) {
+	for i := range items {
+		if target(&items[i]).Target == groupJID {
+			return &items[i], nil
+		}
+	}
+
+	tags, err := GetGroupTagsAt(dbPath, groupJID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		if target(&items[i]).MatchesTags(tags) {
+			return &items[i], nil
+		}
+	}
+	return nil, nil
+}
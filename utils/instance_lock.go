@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// InstanceLockFile adalah file lock untuk direktori data bot (satu direktori data = satu proses bot)
+const InstanceLockFile = "bot.lock"
+
+// ErrLockHeld dikembalikan jika lock sedang dipegang proses lain
+var ErrLockHeld = errors.New("lock sedang dipegang proses lain")
+
+// LockHeldError berisi detail lock yang sedang dipegang proses lain
+type LockHeldError struct {
+	Path string
+	PID  int // PID pemegang lock (0 jika tidak diketahui)
+}
+
+func (e *LockHeldError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("%s sedang dipakai proses lain (PID %d)", e.Path, e.PID)
+	}
+	return fmt.Sprintf("%s sedang dipakai proses lain", e.Path)
+}
+
+// Unwrap agar errors.Is(err, ErrLockHeld) bisa dipakai caller
+func (e *LockHeldError) Unwrap() error {
+	return ErrLockHeld
+}
+
+// fileLock adalah advisory lock berbasis file yang dipegang proses ini
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+var (
+	heldLocks      = make(map[string]*fileLock)
+	heldLocksMutex sync.Mutex
+)
+
+// AcquireInstanceLock mengunci direktori data agar hanya satu instance bot yang berjalan
+// Return *LockHeldError jika instance lain masih hidup
+func AcquireInstanceLock() error {
+	return acquireFileLock(InstanceLockFile)
+}
+
+// AcquireDBLock mengunci database sesi WhatsApp (whatsmeow-*.db) untuk proses ini
+// Aman dipanggil berulang kali untuk path yang sama (misalnya saat reconnect)
+func AcquireDBLock(dbPath string) error {
+	return acquireFileLock(dbPath + ".lock")
+}
+
+// ReleaseDBLock melepas lock database sesi WhatsApp (misalnya saat akun dihapus)
+func ReleaseDBLock(dbPath string) {
+	releaseFileLock(dbPath + ".lock")
+}
+
+// ReleaseAllLocks melepas semua lock yang dipegang proses ini (dipakai saat shutdown)
+func ReleaseAllLocks() {
+	heldLocksMutex.Lock()
+	paths := make([]string, 0, len(heldLocks))
+	for path := range heldLocks {
+		paths = append(paths, path)
+	}
+	heldLocksMutex.Unlock()
+
+	for _, path := range paths {
+		releaseFileLock(path)
+	}
+}
+
+// acquireFileLock mengambil advisory lock pada file lock dan menulis PID proses ini ke dalamnya
+// Lock dari proses yang crash otomatis dilepas oleh OS (atau terdeteksi lewat PID yang sudah mati)
+func acquireFileLock(path string) error {
+	heldLocksMutex.Lock()
+	defer heldLocksMutex.Unlock()
+
+	if _, held := heldLocks[path]; held {
+		return nil
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("gagal membuka file lock %s: %w", path, err)
+	}
+
+	previousPID := readLockPID(file)
+	if err := lockFile(file, previousPID); err != nil {
+		file.Close()
+		if errors.Is(err, ErrLockHeld) {
+			return &LockHeldError{Path: path, PID: previousPID}
+		}
+		return fmt.Errorf("gagal mengunci %s: %w", path, err)
+	}
+
+	// PID di file lama tapi lock berhasil diambil = lock basi dari proses yang crash
+	if previousPID > 0 && previousPID != os.Getpid() {
+		GetLogger().Warn("Lock: %s berisi PID %d yang sudah tidak aktif (stale lock), diambil alih", path, previousPID)
+	}
+
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+		file.Sync()
+	}
+
+	heldLocks[path] = &fileLock{path: path, file: file}
+	return nil
+}
+
+// releaseFileLock melepas lock tanpa menghapus file lock
+// File sengaja dibiarkan: jika dihapus, proses lain yang sudah membuka path yang sama bisa mengunci
+// inode lama sementara proses berikutnya membuat file baru, sehingga dua proses sama-sama memegang lock
+func releaseFileLock(path string) {
+	heldLocksMutex.Lock()
+	defer heldLocksMutex.Unlock()
+
+	lock, held := heldLocks[path]
+	if !held {
+		return
+	}
+	delete(heldLocks, path)
+
+	// Kosongkan PID selagi lock masih dipegang agar pelepasan normal tidak terbaca sebagai stale lock
+	lock.file.Truncate(0)
+	unlockFile(lock.file)
+	lock.file.Close()
+}
+
+// readLockPID membaca PID pemegang lock terakhir dari file lock (0 jika kosong/tidak valid)
+func readLockPID(file *os.File) int {
+	buf := make([]byte, 32)
+	n, _ := file.ReadAt(buf, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf[:n])))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
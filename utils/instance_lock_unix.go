@@ -0,0 +1,24 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile mengambil flock eksklusif non-blocking
+// flock otomatis dilepas kernel saat proses mati, jadi lock dari proses crash tidak pernah menahan startup
+func lockFile(file *os.File, previousPID int) error {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrLockHeld
+	}
+	return err
+}
+
+// unlockFile melepas flock
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package utils
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// Byte yang dikunci berada jauh di luar isi file agar PID pemegang lock tetap bisa dibaca proses lain
+const lockFileOffsetHigh = 1
+
+// lockFile mengambil LockFileEx eksklusif non-blocking
+// Lock otomatis dilepas Windows saat proses mati, jadi PID basi di file tidak pernah menahan startup
+func lockFile(file *os.File, previousPID int) error {
+	overlapped := &windows.Overlapped{OffsetHigh: lockFileOffsetHigh}
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return ErrLockHeld
+	}
+	return err
+}
+
+// unlockFile melepas LockFileEx
+func unlockFile(file *os.File) error {
+	overlapped := &windows.Overlapped{OffsetHigh: lockFileOffsetHigh}
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
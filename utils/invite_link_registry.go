@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Status link undangan di registry
+const (
+	InviteLinkStatusValid   = "valid"   // Link terakhir dicek masih aktif
+	InviteLinkStatusRevoked = "revoked" // Link sudah direset/dicabut (atau tidak valid lagi)
+)
+
+// InviteLinkRecord adalah satu entri registry link undangan grup per akun WhatsApp
+type InviteLinkRecord struct {
+	AccountID  string
+	GroupJID   string
+	InviteLink string
+	Status     string
+	FetchedAt  time.Time
+	CheckedAt  time.Time
+}
+
+// setupInviteLinkRegistryTable membuat tabel registry link undangan (per akun dan per grup)
+func setupInviteLinkRegistryTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_invite_links (
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			invite_link TEXT NOT NULL,
+			status TEXT DEFAULT 'valid',
+			fetched_at DATETIME NOT NULL,
+			checked_at DATETIME NOT NULL,
+			PRIMARY KEY (account_id, group_jid)
+		)
+	`)
+	return err
+}
+
+// SaveInviteLinkRecord menyimpan link yang baru diambil dari WhatsApp (status valid, waktu cek = sekarang)
+func SaveInviteLinkRecord(accountID, groupJID, inviteLink string) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	_, err = db.Exec(`
+		INSERT INTO group_invite_links (account_id, group_jid, invite_link, status, fetched_at, checked_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(account_id, group_jid) DO UPDATE SET
+			invite_link = excluded.invite_link,
+			status = excluded.status,
+			fetched_at = excluded.fetched_at,
+			checked_at = excluded.checked_at
+	`, accountID, groupJID, inviteLink, InviteLinkStatusValid, now, now)
+	return err
+}
+
+// UpdateInviteLinkStatusAt menyimpan hasil pengecekan validitas link (tanpa mengubah link) di database dbPath (kosong = akun aktif)
+func UpdateInviteLinkStatusAt(dbPath, accountID, groupJID, status string) error {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		UPDATE group_invite_links SET status = ?, checked_at = ?
+		WHERE account_id = ? AND group_jid = ?
+	`, status, time.Now(), accountID, groupJID)
+	return err
+}
+
+// GetInviteLinkRecordsAt mengambil semua link di registry milik akun dari database dbPath (kosong = akun aktif),
+// urut dari yang paling lama dicek
+func GetInviteLinkRecordsAt(dbPath, accountID string) ([]InviteLinkRecord, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT account_id, group_jid, invite_link, status, fetched_at, checked_at
+		FROM group_invite_links WHERE account_id = ?
+		ORDER BY checked_at ASC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []InviteLinkRecord
+	for rows.Next() {
+		var record InviteLinkRecord
+		if err := rows.Scan(&record.AccountID, &record.GroupJID, &record.InviteLink, &record.Status, &record.FetchedAt, &record.CheckedAt); err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Status permintaan join grup yang memakai persetujuan admin
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+// JoinRequestRecord adalah satu permintaan join grup (per akun) beserta link asalnya
+type JoinRequestRecord struct {
+	AccountID   string
+	GroupJID    string
+	GroupName   string
+	InviteLink  string
+	Status      string
+	RequestedAt time.Time
+	ResolvedAt  time.Time // Kosong selama masih pending
+}
+
+// setupJoinRequestTable membuat tabel permintaan join grup yang menunggu persetujuan
+func setupJoinRequestTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS group_join_requests (
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			group_name TEXT DEFAULT '',
+			invite_link TEXT DEFAULT '',
+			status TEXT DEFAULT 'pending',
+			requested_at DATETIME NOT NULL,
+			resolved_at DATETIME,
+			PRIMARY KEY (account_id, group_jid)
+		)
+	`)
+	return err
+}
+
+// SaveJoinRequest mencatat permintaan join yang menunggu persetujuan (permintaan ulang me-reset status ke pending)
+func SaveJoinRequest(accountID, groupJID, groupName, inviteLink string) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO group_join_requests (account_id, group_jid, group_name, invite_link, status, requested_at, resolved_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULL)
+		ON CONFLICT(account_id, group_jid) DO UPDATE SET
+			group_name = excluded.group_name,
+			invite_link = excluded.invite_link,
+			status = excluded.status,
+			requested_at = excluded.requested_at,
+			resolved_at = NULL
+	`, accountID, groupJID, groupName, inviteLink, JoinRequestPending, time.Now())
+	return err
+}
+
+// ResolveJoinRequestAt mengubah status permintaan yang masih pending (approved/rejected) di database akun dbPath
+// Return true jika ada permintaan pending yang diperbarui
+func ResolveJoinRequestAt(dbPath, accountID, groupJID, status string) (bool, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := db.Exec(`
+		UPDATE group_join_requests SET status = ?, resolved_at = ?
+		WHERE account_id = ? AND group_jid = ? AND status = ?
+	`, status, time.Now(), accountID, groupJID, JoinRequestPending)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// GetJoinRequestAt mengambil satu permintaan join dari database akun dbPath (nil jika tidak ada)
+func GetJoinRequestAt(dbPath, accountID, groupJID string) (*JoinRequestRecord, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT account_id, group_jid, group_name, invite_link, status, requested_at, resolved_at
+		FROM group_join_requests WHERE account_id = ? AND group_jid = ?
+	`, accountID, groupJID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := scanJoinRequests(rows)
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	return &records[0], nil
+}
+
+// GetJoinRequests mengambil semua permintaan join milik akun, terbaru lebih dulu
+func GetJoinRequests(accountID string) ([]JoinRequestRecord, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT account_id, group_jid, group_name, invite_link, status, requested_at, resolved_at
+		FROM group_join_requests WHERE account_id = ?
+		ORDER BY requested_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return scanJoinRequests(rows)
+}
+
+// scanJoinRequests membaca hasil query group_join_requests lalu menutup rows
+func scanJoinRequests(rows *sql.Rows) ([]JoinRequestRecord, error) {
+	defer rows.Close()
+
+	var records []JoinRequestRecord
+	for rows.Next() {
+		var record JoinRequestRecord
+		var groupName, inviteLink sql.NullString
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&record.AccountID, &record.GroupJID, &groupName, &inviteLink, &record.Status, &record.RequestedAt, &resolvedAt); err != nil {
+			continue
+		}
+		record.GroupName = groupName.String
+		record.InviteLink = inviteLink.String
+		if resolvedAt.Valid {
+			record.ResolvedAt = resolvedAt.Time
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
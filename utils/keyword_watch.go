@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Target aturan pantau untuk semua grup akun
+const KeywordWatchAllGroups = "*"
+
+// KeywordWatch adalah aturan pantau kata kunci / regex di grup WhatsApp yang dikirim sebagai alert ke Telegram
+// Target: JID grup, "tag:key=value" (koleksi grup), atau KeywordWatchAllGroups
+type KeywordWatch struct {
+	ID        int64
+	AccountID string
+	GroupTarget
+	Keywords        []string // Dicocokkan tanpa membedakan huruf besar/kecil (salah satu cocok = alert)
+	Regex           string   // Alternatif Keywords; dicocokkan tanpa membedakan huruf besar/kecil
+	ThrottleSeconds int      // Jeda minimal antar alert aturan ini
+	CreatedAt       time.Time
+}
+
+// setupKeywordWatchTable membuat tabel aturan pantau kata kunci
+func setupKeywordWatchTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS keyword_watches (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			target TEXT NOT NULL,
+			target_name TEXT DEFAULT '',
+			keywords TEXT DEFAULT '',
+			regex TEXT DEFAULT '',
+			throttle_seconds INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// AppliesTo mengecek apakah aturan berlaku untuk grup (tags = tag grup tersebut)
+func (w *KeywordWatch) AppliesTo(groupJID string, tags map[string]string) bool {
+	return w.Target == KeywordWatchAllGroups || w.Target == groupJID || w.MatchesTags(tags)
+}
+
+// SaveKeywordWatch menyimpan aturan pantau baru untuk akun aktif
+func SaveKeywordWatch(watch *KeywordWatch) (int64, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO keyword_watches (account_id, target, target_name, keywords, regex, throttle_seconds)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, watch.AccountID, watch.Target, watch.TargetName, strings.Join(watch.Keywords, "\n"), watch.Regex, watch.ThrottleSeconds)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// DeleteKeywordWatch menghapus aturan pantau milik akun
+func DeleteKeywordWatch(accountID string, id int64) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM keyword_watches WHERE id = ? AND account_id = ?", id, accountID)
+	return err
+}
+
+// GetKeywordWatchesAt mengambil semua aturan pantau akun dari database dbPath (kosong = akun aktif)
+func GetKeywordWatchesAt(dbPath, accountID string) ([]KeywordWatch, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, target, target_name, keywords, regex, throttle_seconds, created_at
+		FROM keyword_watches WHERE account_id = ?
+		ORDER BY id
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var watches []KeywordWatch
+	for rows.Next() {
+		var w KeywordWatch
+		var targetName, keywords, regex sql.NullString
+		var createdAt sql.NullTime
+		if err := rows.Scan(&w.ID, &w.AccountID, &w.Target, &targetName, &keywords, &regex, &w.ThrottleSeconds, &createdAt); err != nil {
+			continue
+		}
+		w.TargetName = targetName.String
+		if keywords.String != "" {
+			w.Keywords = strings.Split(keywords.String, "\n")
+		}
+		w.Regex = regex.String
+		if createdAt.Valid {
+			w.CreatedAt = createdAt.Time
+		}
+		watches = append(watches, w)
+	}
+	return watches, rows.Err()
+}
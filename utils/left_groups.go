@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// LeftGroupRecord adalah snapshot grup (link undangan + metadata) yang diambil sebelum akun keluar
+type LeftGroupRecord struct {
+	ID          int64
+	AccountID   string
+	GroupJID    string
+	GroupName   string
+	Topic       string
+	MemberCount int
+	InviteLink  string
+	LeftAt      time.Time
+	RejoinedAt  time.Time // Kosong jika belum bergabung kembali
+}
+
+// setupLeftGroupsTable membuat tabel snapshot grup yang ditinggalkan
+func setupLeftGroupsTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS left_groups (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			group_name TEXT DEFAULT '',
+			topic TEXT DEFAULT '',
+			member_count INTEGER DEFAULT 0,
+			invite_link TEXT NOT NULL,
+			left_at DATETIME NOT NULL,
+			rejoined_at DATETIME,
+			UNIQUE(account_id, group_jid)
+		)
+	`)
+	return err
+}
+
+// SaveLeftGroup menyimpan snapshot grup yang baru ditinggalkan (keluar ulang menimpa snapshot lama)
+func SaveLeftGroup(record *LeftGroupRecord) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO left_groups (account_id, group_jid, group_name, topic, member_count, invite_link, left_at, rejoined_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULL)
+		ON CONFLICT(account_id, group_jid) DO UPDATE SET
+			group_name = excluded.group_name,
+			topic = excluded.topic,
+			member_count = excluded.member_count,
+			invite_link = excluded.invite_link,
+			left_at = excluded.left_at,
+			rejoined_at = NULL
+	`, record.AccountID, record.GroupJID, record.GroupName, record.Topic, record.MemberCount, record.InviteLink, record.LeftAt)
+	return err
+}
+
+// MarkLeftGroupRejoined menandai grup sudah berhasil digabungi kembali
+func MarkLeftGroupRejoined(id int64) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE left_groups SET rejoined_at = ? WHERE id = ?", time.Now(), id)
+	return err
+}
+
+// GetRejoinableGroups mengambil grup yang ditinggalkan setelah since dan belum digabungi kembali, terbaru lebih dulu
+// Snapshot yang sudah lewat masa tenggang dihapus sekaligus
+func GetRejoinableGroups(accountID string, since time.Time) ([]LeftGroupRecord, error) {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec("DELETE FROM left_groups WHERE account_id = ? AND left_at < ?", accountID, since); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, group_jid, group_name, topic, member_count, invite_link, left_at, rejoined_at
+		FROM left_groups WHERE account_id = ? AND rejoined_at IS NULL
+		ORDER BY left_at DESC
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []LeftGroupRecord
+	for rows.Next() {
+		var record LeftGroupRecord
+		var groupName, topic sql.NullString
+		var rejoinedAt sql.NullTime
+		if err := rows.Scan(&record.ID, &record.AccountID, &record.GroupJID, &groupName, &topic, &record.MemberCount, &record.InviteLink, &record.LeftAt, &rejoinedAt); err != nil {
+			continue
+		}
+		record.GroupName = groupName.String
+		record.Topic = topic.String
+		if rejoinedAt.Valid {
+			record.RejoinedAt = rejoinedAt.Time
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
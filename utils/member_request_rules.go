@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// MemberRequestRules adalah aturan auto-approve permintaan masuk grup milik satu akun
+// Pattern berupa nomor lengkap (628123...) atau prefix kode negara/operator dengan akhiran * (62*, 6281*)
+type MemberRequestRules struct {
+	AccountID   string
+	Patterns    []string
+	AutoEnabled bool // Jika aktif, permintaan baru yang cocok langsung disetujui saat event masuk
+}
+
+// setupMemberRequestRulesTable membuat tabel aturan auto-approve permintaan masuk grup
+func setupMemberRequestRulesTable(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS member_request_rules (
+			account_id TEXT PRIMARY KEY,
+			patterns TEXT DEFAULT '',
+			auto_enabled INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// Matches mengecek apakah nomor (tanpa +) cocok dengan salah satu pattern
+func (r *MemberRequestRules) Matches(phone string) bool {
+	if phone == "" {
+		return false
+	}
+	for _, pattern := range r.Patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(phone, prefix) {
+				return true
+			}
+		} else if phone == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// GetMemberRequestRulesAt mengambil aturan akun dari database dbPath (kosong = akun aktif)
+// Jika belum pernah diatur, return aturan kosong (bukan nil)
+func GetMemberRequestRulesAt(dbPath, accountID string) (*MemberRequestRules, error) {
+	rules := &MemberRequestRules{AccountID: accountID}
+
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return rules, err
+	}
+
+	var patterns string
+	var autoEnabled bool
+	err = db.QueryRow("SELECT patterns, auto_enabled FROM member_request_rules WHERE account_id = ?", accountID).Scan(&patterns, &autoEnabled)
+	if err == sql.ErrNoRows {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, err
+	}
+
+	rules.AutoEnabled = autoEnabled
+	if patterns != "" {
+		rules.Patterns = strings.Split(patterns, "\n")
+	}
+	return rules, nil
+}
+
+// SaveMemberRequestRules menyimpan aturan auto-approve akun aktif
+func SaveMemberRequestRules(rules *MemberRequestRules) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO member_request_rules (account_id, patterns, auto_enabled, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id) DO UPDATE SET
+			patterns = excluded.patterns,
+			auto_enabled = excluded.auto_enabled,
+			updated_at = CURRENT_TIMESTAMP
+	`, rules.AccountID, strings.Join(rules.Patterns, "\n"), rules.AutoEnabled)
+	return err
+}
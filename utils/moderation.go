@@ -0,0 +1,296 @@
+package utils
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Aksi moderasi yang dicatat
+const (
+	ModerationActionWarn   = "warn"   // Hanya peringatan di grup
+	ModerationActionDelete = "delete" // Pesan dihapus untuk semua (revoke) + peringatan
+	ModerationActionRemove = "remove" // Peserta dikeluarkan setelah mencapai batas peringatan
+)
+
+// Jenis pelanggaran yang dideteksi
+const (
+	ViolationInviteLink = "invite_link"
+	ViolationForwarded  = "forwarded"
+	ViolationFlood      = "flood"
+	ViolationKeyword    = "keyword"
+)
+
+// ModerationRule adalah aturan moderasi otomatis untuk satu grup atau satu koleksi grup (tag)
+type ModerationRule struct {
+	ID        int64
+	AccountID string
+	GroupTarget
+	BlockInviteLinks bool     // Link undangan grup dari non-admin
+	BlockForwarded   bool     // Pesan "diteruskan berkali-kali"
+	FloodLimit       int      // Maksimal pesan per pengirim dalam FloodWindow detik (0 = nonaktif)
+	FloodWindow      int      // Detik
+	Keywords         []string // Kata terlarang (huruf kecil)
+	Action           string   // ModerationActionWarn atau ModerationActionDelete
+	MaxWarnings      int      // Peserta dikeluarkan setelah N peringatan (0 = tidak pernah)
+	UpdatedAt        time.Time
+}
+
+// ModerationAction adalah catatan satu tindakan moderasi (dipakai untuk tombol banding / undo)
+type ModerationAction struct {
+	ID        int64
+	AccountID string
+	GroupJID  string
+	GroupName string
+	SenderJID string
+	MessageID string
+	Violation string
+	Detail    string
+	Action    string
+	Warnings  int
+	Undone    bool
+	CreatedAt time.Time
+}
+
+// setupModerationTables membuat tabel aturan moderasi, hitungan peringatan dan riwayat tindakan
+func setupModerationTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS moderation_rules (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			target TEXT NOT NULL,
+			target_name TEXT DEFAULT '',
+			block_invite_links INTEGER DEFAULT 0,
+			block_forwarded INTEGER DEFAULT 0,
+			flood_limit INTEGER DEFAULT 0,
+			flood_window INTEGER DEFAULT 0,
+			keywords TEXT DEFAULT '',
+			action TEXT DEFAULT 'warn',
+			max_warnings INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, target)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS moderation_warnings (
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			sender_jid TEXT NOT NULL,
+			count INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (account_id, group_jid, sender_jid)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS moderation_actions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			group_jid TEXT NOT NULL,
+			group_name TEXT DEFAULT '',
+			sender_jid TEXT NOT NULL,
+			message_id TEXT DEFAULT '',
+			violation TEXT NOT NULL,
+			detail TEXT DEFAULT '',
+			action TEXT NOT NULL,
+			warnings INTEGER DEFAULT 0,
+			undone INTEGER DEFAULT 0,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// SaveModerationRule menyimpan aturan moderasi (target yang sama milik akun yang sama akan ditimpa)
+func SaveModerationRule(rule *ModerationRule) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO moderation_rules (account_id, target, target_name, block_invite_links, block_forwarded,
+			flood_limit, flood_window, keywords, action, max_warnings, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id, target) DO UPDATE SET
+			target_name = excluded.target_name,
+			block_invite_links = excluded.block_invite_links,
+			block_forwarded = excluded.block_forwarded,
+			flood_limit = excluded.flood_limit,
+			flood_window = excluded.flood_window,
+			keywords = excluded.keywords,
+			action = excluded.action,
+			max_warnings = excluded.max_warnings,
+			updated_at = CURRENT_TIMESTAMP
+	`, rule.AccountID, rule.Target, rule.TargetName, rule.BlockInviteLinks, rule.BlockForwarded,
+		rule.FloodLimit, rule.FloodWindow, strings.Join(rule.Keywords, "\n"), rule.Action, rule.MaxWarnings)
+	return err
+}
+
+// DeleteModerationRule menghapus aturan moderasi milik akun
+func DeleteModerationRule(accountID string, id int64) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM moderation_rules WHERE id = ? AND account_id = ?", id, accountID)
+	return err
+}
+
+// GetModerationRulesAt mengambil semua aturan moderasi akun dari database dbPath (kosong = akun aktif)
+func GetModerationRulesAt(dbPath, accountID string) ([]ModerationRule, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, target, target_name, block_invite_links, block_forwarded,
+			flood_limit, flood_window, keywords, action, max_warnings, updated_at
+		FROM moderation_rules WHERE account_id = ?
+		ORDER BY target_name COLLATE NOCASE
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []ModerationRule
+	for rows.Next() {
+		var r ModerationRule
+		var targetName, keywords sql.NullString
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&r.ID, &r.AccountID, &r.Target, &targetName, &r.BlockInviteLinks, &r.BlockForwarded,
+			&r.FloodLimit, &r.FloodWindow, &keywords, &r.Action, &r.MaxWarnings, &updatedAt); err != nil {
+			continue
+		}
+		r.TargetName = targetName.String
+		if keywords.String != "" {
+			r.Keywords = strings.Split(keywords.String, "\n")
+		}
+		if updatedAt.Valid {
+			r.UpdatedAt = updatedAt.Time
+		}
+		rules = append(rules, r)
+	}
+	return rules, rows.Err()
+}
+
+// FindModerationRuleAt mencari aturan moderasi untuk grup: aturan grup itu sendiri lebih dulu,
+// lalu aturan koleksi (tag) yang cocok dengan tag grup. Return nil jika tidak ada
+func FindModerationRuleAt(dbPath, accountID, groupJID string) (*ModerationRule, error) {
+	rules, err := GetModerationRulesAt(dbPath, accountID)
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+	return findGroupTargetAt(dbPath, groupJID, rules, func(r *ModerationRule) *GroupTarget { return &r.GroupTarget })
+}
+
+// AddModerationWarningAt menambah hitungan peringatan peserta di grup dan mengembalikan jumlah terbaru
+func AddModerationWarningAt(dbPath, accountID, groupJID, senderJID string) (int, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO moderation_warnings (account_id, group_jid, sender_jid, count, updated_at)
+		VALUES (?, ?, ?, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id, group_jid, sender_jid) DO UPDATE SET
+			count = count + 1,
+			updated_at = CURRENT_TIMESTAMP
+	`, accountID, groupJID, senderJID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = db.QueryRow("SELECT count FROM moderation_warnings WHERE account_id = ? AND group_jid = ? AND sender_jid = ?",
+		accountID, groupJID, senderJID).Scan(&count)
+	return count, err
+}
+
+// RemoveModerationWarningAt mengurangi satu peringatan peserta (banding diterima)
+func RemoveModerationWarningAt(dbPath, accountID, groupJID, senderJID string) error {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(`
+		UPDATE moderation_warnings SET count = MAX(count - 1, 0), updated_at = CURRENT_TIMESTAMP
+		WHERE account_id = ? AND group_jid = ? AND sender_jid = ?
+	`, accountID, groupJID, senderJID)
+	return err
+}
+
+// ResetModerationWarningsAt menghapus hitungan peringatan peserta (setelah dikeluarkan / dimasukkan kembali)
+func ResetModerationWarningsAt(dbPath, accountID, groupJID, senderJID string) error {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM moderation_warnings WHERE account_id = ? AND group_jid = ? AND sender_jid = ?",
+		accountID, groupJID, senderJID)
+	return err
+}
+
+// SaveModerationActionAt mencatat tindakan moderasi dan mengembalikan ID-nya
+func SaveModerationActionAt(dbPath string, action *ModerationAction) (int64, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO moderation_actions (account_id, group_jid, group_name, sender_jid, message_id, violation, detail, action, warnings)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, action.AccountID, action.GroupJID, action.GroupName, action.SenderJID, action.MessageID,
+		action.Violation, action.Detail, action.Action, action.Warnings)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// GetModerationActionAt mengambil satu tindakan moderasi. Return nil jika tidak ada
+func GetModerationActionAt(dbPath string, id int64) (*ModerationAction, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var a ModerationAction
+	var groupName, messageID, detail sql.NullString
+	err = db.QueryRow(`
+		SELECT id, account_id, group_jid, group_name, sender_jid, message_id, violation, detail, action, warnings, undone, created_at
+		FROM moderation_actions WHERE id = ?
+	`, id).Scan(&a.ID, &a.AccountID, &a.GroupJID, &groupName, &a.SenderJID, &messageID, &a.Violation, &detail,
+		&a.Action, &a.Warnings, &a.Undone, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.GroupName = groupName.String
+	a.MessageID = messageID.String
+	a.Detail = detail.String
+	return &a, nil
+}
+
+// MarkModerationActionUndoneAt menandai tindakan moderasi sudah dibatalkan (banding diterima)
+func MarkModerationActionUndoneAt(dbPath string, id int64) error {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("UPDATE moderation_actions SET undone = 1 WHERE id = ?", id)
+	return err
+}
@@ -14,10 +14,11 @@ type TelegramConfig struct {
 }
 
 type ConfigSettings struct {
-	MaxAccounts    int    `json:"max_accounts,omitempty"`
-	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
-	RetryAttempts  int    `json:"retry_attempts,omitempty"`
-	LogLevel       string `json:"log_level,omitempty"`
+	MaxAccounts      int    `json:"max_accounts,omitempty"`
+	TimeoutSeconds   int    `json:"timeout_seconds,omitempty"`
+	RetryAttempts    int    `json:"retry_attempts,omitempty"`
+	LogLevel         string `json:"log_level,omitempty"`
+	RejoinGraceHours int    `json:"rejoin_grace_hours,omitempty"` // Masa simpan link grup yang ditinggalkan (jam, default 72)
 }
 
 // LoadConfig memuat konfigurasi Telegram dari file akses.json
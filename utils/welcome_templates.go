@@ -0,0 +1,150 @@
+package utils
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WelcomeTemplate adalah template pesan sambutan anggota baru untuk satu grup atau satu koleksi grup (tag)
+type WelcomeTemplate struct {
+	ID        int64
+	AccountID string
+	GroupTarget
+	Message   string // Mendukung {mention}, {group}, {rules}, {count}
+	Rules     string // Peraturan grup (opsional)
+	UpdatedAt time.Time
+}
+
+// setupWelcomeTables membuat tabel template sambutan dan saklar on/off sambutan per akun
+func setupWelcomeTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS welcome_templates (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id TEXT NOT NULL,
+			target TEXT NOT NULL,
+			target_name TEXT DEFAULT '',
+			message TEXT NOT NULL,
+			rules TEXT DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(account_id, target)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS welcome_settings (
+			account_id TEXT PRIMARY KEY,
+			enabled INTEGER DEFAULT 0,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// SaveWelcomeTemplate menyimpan template sambutan (target yang sama milik akun yang sama akan ditimpa)
+func SaveWelcomeTemplate(template *WelcomeTemplate) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO welcome_templates (account_id, target, target_name, message, rules, updated_at)
+		VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id, target) DO UPDATE SET
+			target_name = excluded.target_name,
+			message = excluded.message,
+			rules = excluded.rules,
+			updated_at = CURRENT_TIMESTAMP
+	`, template.AccountID, template.Target, template.TargetName, template.Message, template.Rules)
+	return err
+}
+
+// DeleteWelcomeTemplate menghapus template sambutan milik akun accountID
+func DeleteWelcomeTemplate(accountID string, id int64) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM welcome_templates WHERE id = ? AND account_id = ?", id, accountID)
+	return err
+}
+
+// GetWelcomeTemplatesAt mengambil semua template sambutan akun dari database dbPath (kosong = akun aktif)
+func GetWelcomeTemplatesAt(dbPath, accountID string) ([]WelcomeTemplate, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`
+		SELECT id, account_id, target, target_name, message, rules, updated_at
+		FROM welcome_templates WHERE account_id = ?
+		ORDER BY target_name COLLATE NOCASE
+	`, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []WelcomeTemplate
+	for rows.Next() {
+		var t WelcomeTemplate
+		var targetName, rules sql.NullString
+		var updatedAt sql.NullTime
+		if err := rows.Scan(&t.ID, &t.AccountID, &t.Target, &targetName, &t.Message, &rules, &updatedAt); err != nil {
+			continue
+		}
+		t.TargetName = targetName.String
+		t.Rules = rules.String
+		if updatedAt.Valid {
+			t.UpdatedAt = updatedAt.Time
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+// FindWelcomeTemplateAt mencari template sambutan untuk grup: template grup itu sendiri lebih dulu,
+// lalu template koleksi (tag) yang cocok dengan tag grup. Return nil jika tidak ada
+func FindWelcomeTemplateAt(dbPath, accountID, groupJID string) (*WelcomeTemplate, error) {
+	templates, err := GetWelcomeTemplatesAt(dbPath, accountID)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return findGroupTargetAt(dbPath, groupJID, templates, func(t *WelcomeTemplate) *GroupTarget { return &t.GroupTarget })
+}
+
+// IsWelcomeEnabledAt mengecek saklar sambutan akun dari database dbPath (kosong = akun aktif)
+func IsWelcomeEnabledAt(dbPath, accountID string) (bool, error) {
+	db, err := accountBotDB(dbPath)
+	if err != nil {
+		return false, err
+	}
+
+	var enabled bool
+	err = db.QueryRow("SELECT enabled FROM welcome_settings WHERE account_id = ?", accountID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// SetWelcomeEnabled menyalakan / mematikan sambutan anggota baru untuk akun aktif
+func SetWelcomeEnabled(accountID string, enabled bool) error {
+	db, err := GetBotDBPool()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO welcome_settings (account_id, enabled, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(account_id) DO UPDATE SET
+			enabled = excluded.enabled,
+			updated_at = CURRENT_TIMESTAMP
+	`, accountID, enabled)
+	return err
+}